@@ -0,0 +1,136 @@
+package mint
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// AppendString appends the mint encoding of s to b, returning the
+// extended buffer
+func AppendString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(s)))
+
+	return append(b, s...)
+}
+
+// AppendDatetime appends the mint encoding of t to b, returning the
+// extended buffer
+func AppendDatetime(b []byte, t time.Time) []byte {
+	return binary.LittleEndian.AppendUint64(b, uint64(t.UnixNano()))
+}
+
+// AppendUuid appends the mint encoding of u to b, returning the
+// extended buffer
+func AppendUuid(b []byte, u uuid.UUID) []byte {
+	return append(b, u[:]...)
+}
+
+// AppendInt16 appends the mint encoding of i to b, returning the
+// extended buffer
+func AppendInt16(b []byte, i int16) []byte {
+	return binary.LittleEndian.AppendUint16(b, uint16(i))
+}
+
+// AppendUint16 appends the mint encoding of i to b, returning the
+// extended buffer
+func AppendUint16(b []byte, i uint16) []byte {
+	return binary.LittleEndian.AppendUint16(b, i)
+}
+
+// AppendInt32 appends the mint encoding of i to b, returning the
+// extended buffer
+func AppendInt32(b []byte, i int32) []byte {
+	return binary.LittleEndian.AppendUint32(b, uint32(i))
+}
+
+// AppendUint32 appends the mint encoding of i to b, returning the
+// extended buffer
+func AppendUint32(b []byte, i uint32) []byte {
+	return binary.LittleEndian.AppendUint32(b, i)
+}
+
+// AppendInt64 appends the mint encoding of i to b, returning the
+// extended buffer
+func AppendInt64(b []byte, i int64) []byte {
+	return binary.LittleEndian.AppendUint64(b, uint64(i))
+}
+
+// AppendFloat32 appends the mint encoding of f to b, returning the
+// extended buffer
+func AppendFloat32(b []byte, f float32) []byte {
+	return binary.LittleEndian.AppendUint32(b, math.Float32bits(f))
+}
+
+// AppendFloat64 appends the mint encoding of f to b, returning the
+// extended buffer
+func AppendFloat64(b []byte, f float64) []byte {
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+}
+
+// AppendByte appends the mint encoding of c to b, returning the
+// extended buffer
+func AppendByte(b []byte, c byte) []byte {
+	return append(b, c)
+}
+
+// AppendBool appends the mint encoding of v to b, returning the
+// extended buffer
+func AppendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+
+	return append(b, 0)
+}
+
+// appendMarshall appends m to b, using m's own AppendMarshall where
+// it has one, and falling back to Marshall otherwise
+func appendMarshall(b []byte, m Marshaller) ([]byte, error) {
+	if a, ok := m.(Appender); ok {
+		return a.AppendMarshall(b)
+	}
+
+	w := appendWriter(b)
+	err := m.Marshall(&w)
+
+	return w, err
+}
+
+// appendWriter is an io.Writer which appends to a byte slice
+type appendWriter []byte
+
+func (w *appendWriter) Write(p []byte) (int, error) {
+	*w = append(*w, p...)
+
+	return len(p), nil
+}
+
+// writeBytes writes b to w, for Marshall functions which build their
+// output into a scratch buffer with the Append functions
+func writeBytes(w io.Writer, b []byte) error {
+	_, err := w.Write(b)
+
+	return err
+}
+
+// writeAppended writes to w whatever appendTo appends to a pooled scratch
+// buffer. Marshall functions build their output this way, rather than in
+// a local array, which escapes to the heap on being passed to w, so that
+// marshalling a scalar doesn't allocate
+func writeAppended(w io.Writer, appendTo func([]byte) []byte) error {
+	buf := scratchPool.Get().(*[]byte)
+
+	b := appendTo((*buf)[:0])
+	err := writeBytes(w, b)
+
+	if cap(b) <= maxPooledBufferSize {
+		*buf = b[:0]
+		scratchPool.Put(buf)
+	}
+
+	return err
+}
@@ -0,0 +1,79 @@
+package mint
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type appenderMarshaller interface {
+	Marshaller
+	Appender
+}
+
+func TestAppendMarshall(t *testing.T) {
+	prefix := []byte("prefix")
+
+	for _, test := range []struct {
+		name string
+		v    appenderMarshaller
+	}{
+		{"Empty string", NewStringScalar("")},
+		{"String", NewStringScalar("Hello, World!")},
+		{"Massive string", NewStringScalar(makeLongString())},
+		{"Empty datetime", NewDatetimeScalar(time.Time{})},
+		{"Datetime", NewDatetimeScalar(time.Now())},
+		{"Uuid", NewUuidScalar(uuid.Must(uuid.NewV4()))},
+		{"Int16", NewInt16Scalar(-10_000)},
+		{"Uint16", NewUint16Scalar(10_000)},
+		{"Int32", NewInt32Scalar(-10_000)},
+		{"UInt32", NewUInt32Scalar(10_000)},
+		{"Int64", NewInt64Scalar(-10_000)},
+		{"Float32", NewFloat32Scalar(3.14)},
+		{"Float64", NewFloat64Scalar(-3.14)},
+		{"Byte", NewByteScalar('a')},
+		{"True", NewBoolScalar(true)},
+		{"False", NewBoolScalar(false)},
+		{"Slice", NewSliceCollection([]MarshallerUnmarshallerValuer{NewStringScalar("a"), NewInt64Scalar(1)}, false)},
+		{"Fixed length slice", NewSliceCollection([]MarshallerUnmarshallerValuer{NewStringScalar("a"), NewInt64Scalar(1)}, true)},
+		{"Slice of non-appenders", NewSliceCollection([]MarshallerUnmarshallerValuer{&customMUV{foo: "a", bar: 1, baz: true}}, false)},
+		{"Map", NewMapCollection(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer{NewStringScalar("a"): NewBoolScalar(true)})},
+		{"Empty map", NewMapCollection(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer{})},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			expect := new(bytes.Buffer)
+			expect.Write(prefix)
+
+			err := test.v.Marshall(expect)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			received, err := test.v.AppendMarshall(append([]byte{}, prefix...))
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !bytes.Equal(expect.Bytes(), received) {
+				t.Errorf("expected\n\t%v\nreceived\n\t%v", expect.Bytes(), received)
+			}
+		})
+	}
+}
+
+func TestAppendMarshall_Allocations(t *testing.T) {
+	b := make([]byte, 0, 1024)
+	s := NewStringScalar("Hello, World!")
+	i := NewInt64Scalar(12345)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		b, _ = s.AppendMarshall(b[:0])
+		b, _ = i.AppendMarshall(b)
+	})
+
+	if allocs != 0 {
+		t.Errorf("expected no allocations, received %v", allocs)
+	}
+}
@@ -15,6 +15,10 @@ type Benchmarker struct {
 	SomeNumber       int64
 }
 
+// NewBenchmarker returns a new Benchmarker, with each field set to its default value
+func NewBenchmarker() *Benchmarker {
+	return &Benchmarker{}
+}
 func (sf Benchmarker) Validate() error {
 	errors := make([]error, 0)
 	for _, err := range []error{} {
@@ -30,6 +34,16 @@ func (sf *Benchmarker) Transform() (err error) {
 func (sf Benchmarker) Value() any {
 	return sf
 }
+
+// BenchmarkerFingerprint identifies the encoding of Benchmarker, changing whenever the name, tag, or type of any of its fields, or anything those fields refer to, does
+const BenchmarkerFingerprint uint64 = 0x54f82b5e0cbed272
+
+func (sf Benchmarker) TypeName() string {
+	return "Benchmarker"
+}
+func (sf Benchmarker) Fingerprint() uint64 {
+	return BenchmarkerFingerprint
+}
 func (sf *Benchmarker) unmarshallID(r io.Reader) (err error) {
 	f := mint.NewUuidScalar(v5.UUID{})
 	err = f.Unmarshall(r)
@@ -63,11 +77,13 @@ func (sf *Benchmarker) unmarshallManyShortStrings(r io.Reader) (err error) {
 	if err != nil {
 		return
 	}
-	f.V = make([]mint.MarshallerUnmarshallerValuer, f.Len())
-	for i := range f.V {
-		f.V[i] = mint.NewStringScalar("")
+	if f.Len() == 0 {
+		sf.ManyShortStrings = nil
+		return
 	}
-	err = f.Unmarshall(r)
+	err = f.UnmarshallElements(r, func() mint.MarshallerUnmarshallerValuer {
+		return mint.NewStringScalar("")
+	})
 	if err != nil {
 		return
 	}
@@ -83,11 +99,13 @@ func (sf *Benchmarker) unmarshallManyLongStrings(r io.Reader) (err error) {
 	if err != nil {
 		return
 	}
-	f.V = make([]mint.MarshallerUnmarshallerValuer, f.Len())
-	for i := range f.V {
-		f.V[i] = mint.NewStringScalar("")
+	if f.Len() == 0 {
+		sf.ManyLongStrings = nil
+		return
 	}
-	err = f.Unmarshall(r)
+	err = f.UnmarshallElements(r, func() mint.MarshallerUnmarshallerValuer {
+		return mint.NewStringScalar("")
+	})
 	if err != nil {
 		return
 	}
@@ -107,23 +125,28 @@ func (sf *Benchmarker) unmarshallSomeNumber(r io.Reader) (err error) {
 	return
 }
 func (sf *Benchmarker) Unmarshall(r io.Reader) (err error) {
-	if err = sf.unmarshallID(r); err != nil {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
 		return
 	}
-	if err = sf.unmarshallShortString(r); err != nil {
-		return
+	defer dr.Leave()
+	if err = sf.unmarshallID(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "ID", err)
 	}
-	if err = sf.unmarshallLongString(r); err != nil {
-		return
+	if err = sf.unmarshallShortString(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "ShortString", err)
 	}
-	if err = sf.unmarshallManyShortStrings(r); err != nil {
-		return
+	if err = sf.unmarshallLongString(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "LongString", err)
 	}
-	if err = sf.unmarshallManyLongStrings(r); err != nil {
-		return
+	if err = sf.unmarshallManyShortStrings(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "ManyShortStrings", err)
 	}
-	if err = sf.unmarshallSomeNumber(r); err != nil {
-		return
+	if err = sf.unmarshallManyLongStrings(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "ManyLongStrings", err)
+	}
+	if err = sf.unmarshallSomeNumber(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "SomeNumber", err)
 	}
 	if err = sf.Transform(); err != nil {
 		return
@@ -174,3 +197,142 @@ func (sf Benchmarker) Marshall(w io.Writer) (err error) {
 	}
 	return
 }
+func (sf Benchmarker) appendManyShortStrings(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.ManyShortStrings)))
+	for _, v := range sf.ManyShortStrings {
+		b = mint.AppendString(b, v)
+	}
+	return
+}
+func (sf Benchmarker) appendManyLongStrings(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.ManyLongStrings)))
+	for _, v := range sf.ManyLongStrings {
+		b = mint.AppendString(b, v)
+	}
+	return
+}
+func (sf Benchmarker) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	b = mint.AppendUuid(b, sf.ID)
+	b = mint.AppendString(b, sf.ShortString)
+	b = mint.AppendString(b, sf.LongString)
+	if b, err = sf.appendManyShortStrings(b); err != nil {
+		return
+	}
+	if b, err = sf.appendManyLongStrings(b); err != nil {
+		return
+	}
+	b = mint.AppendInt64(b, sf.SomeNumber)
+	return
+}
+func (sf *Benchmarker) decodeManyShortStrings(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadSliceLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.ManyShortStrings = nil
+		return
+	}
+	sf.ManyShortStrings = make([]string, 0, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var e string
+		if e, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		sf.ManyShortStrings = append(sf.ManyShortStrings, e)
+	}
+	return
+}
+func (sf *Benchmarker) decodeManyLongStrings(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadSliceLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.ManyLongStrings = nil
+		return
+	}
+	sf.ManyLongStrings = make([]string, 0, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var e string
+		if e, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		sf.ManyLongStrings = append(sf.ManyLongStrings, e)
+	}
+	return
+}
+func (sf *Benchmarker) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	if sf.ID, err = d.ReadUuid(); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "ID", err)
+	}
+	if sf.ShortString, err = d.ReadString(); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "ShortString", err)
+	}
+	if sf.LongString, err = d.ReadString(); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "LongString", err)
+	}
+	if err = sf.decodeManyShortStrings(d); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "ManyShortStrings", err)
+	}
+	if err = sf.decodeManyLongStrings(d); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "ManyLongStrings", err)
+	}
+	if sf.SomeNumber, err = d.ReadInt64(); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "SomeNumber", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf *Benchmarker) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+func (sf Benchmarker) sizeManyShortStrings() (n int) {
+	n = mint.CollectionHeaderSize
+	for _, v := range sf.ManyShortStrings {
+		n += mint.StringSize(v)
+	}
+	return
+}
+func (sf Benchmarker) sizeManyLongStrings() (n int) {
+	n = mint.CollectionHeaderSize
+	for _, v := range sf.ManyLongStrings {
+		n += mint.StringSize(v)
+	}
+	return
+}
+func (sf Benchmarker) MarshalledSize() (n int) {
+	n += mint.UuidSize
+	n += mint.StringSize(sf.ShortString)
+	n += mint.StringSize(sf.LongString)
+	n += sf.sizeManyShortStrings()
+	n += sf.sizeManyLongStrings()
+	n += mint.Int64Size
+	return
+}
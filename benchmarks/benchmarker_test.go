@@ -1,12 +1,17 @@
 package benchmarks
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
+	"io"
 	"math/rand"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/gofrs/uuid/v5"
+	"github.com/vinyl-linux/mint"
 )
 
 var (
@@ -27,30 +32,166 @@ var (
 	strS100_10000 = makeStringSlice(str100, 100000)
 )
 
+// benchCase describes a Benchmarker to benchmark against
+type benchCase struct {
+	name                              string
+	shortString, longString           string
+	shortStringSlice, longStringSlice []string
+}
+
+// benchmarker returns the Benchmarker described by b
+func (b benchCase) benchmarker() Benchmarker {
+	return Benchmarker{
+		ID:               id,
+		SomeNumber:       rando,
+		ShortString:      b.shortString,
+		LongString:       b.longString,
+		ManyShortStrings: b.shortStringSlice,
+		ManyLongStrings:  b.longStringSlice,
+	}
+}
+
+var benches = []benchCase{
+	{"small types", str1, str10, strS1_10, strS10_10},
+	{"medium types", str10, str100, strS10_10, strS10_100},
+	{"loadsa data", str100, str10000, strS10_1000, strS100_10000},
+}
+
 func BenchmarkMarshall(b *testing.B) {
+	for _, bench := range benches {
+		b.Run(bench.name, func(b *testing.B) {
+			bb := bench.benchmarker()
+
+			for b.Loop() {
+				buf := new(bytes.Buffer)
+
+				err := bb.Marshall(buf)
+				if err != nil {
+					panic(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAppendMarshall(b *testing.B) {
+	for _, bench := range benches {
+		b.Run(bench.name, func(b *testing.B) {
+			bb := bench.benchmarker()
+
+			var (
+				buf []byte
+				err error
+			)
+
+			for b.Loop() {
+				buf, err = bb.AppendMarshall(buf[:0])
+				if err != nil {
+					panic(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshall(b *testing.B) {
+	for _, bench := range benches {
+		b.Run(bench.name, func(b *testing.B) {
+			bb := bench.benchmarker()
+
+			buf, err := bb.AppendMarshall(nil)
+			if err != nil {
+				panic(err)
+			}
+
+			r := bytes.NewReader(buf)
+
+			for b.Loop() {
+				r.Reset(buf)
+				out := new(Benchmarker)
+
+				err = out.Unmarshall(r)
+				if err != nil {
+					panic(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkScalarMarshall(b *testing.B) {
 	for _, bench := range []struct {
-		name                              string
-		shortString, longString           string
-		shortStringSlice, longStringSlice []string
+		name string
+		w    io.Writer
 	}{
-		{"small types", str1, str10, strS1_10, strS10_10},
-		{"medium types", str10, str100, strS10_10, strS10_100},
-		{"loadsa data", str100, str10000, strS10_1000, strS100_10000},
+		{"discard", io.Discard},
+		{"buffered", bufio.NewWriter(io.Discard)},
 	} {
 		b.Run(bench.name, func(b *testing.B) {
-			bb := Benchmarker{
-				ID:               id,
-				SomeNumber:       rando,
-				ShortString:      bench.shortString,
-				LongString:       bench.longString,
-				ManyShortStrings: bench.shortStringSlice,
-				ManyLongStrings:  bench.longStringSlice,
+			b.ReportAllocs()
+
+			i := mint.NewInt64Scalar(rando)
+			u := mint.NewUuidScalar(id)
+			s := mint.NewStringScalar(str100)
+
+			for b.Loop() {
+				for _, v := range []mint.Marshaller{i, u, s} {
+					err := v.Marshall(bench.w)
+					if err != nil {
+						panic(err)
+					}
+				}
 			}
+		})
+	}
+}
+
+func BenchmarkScalarUnmarshall(b *testing.B) {
+	buf := mint.AppendInt64(nil, rando)
+	buf = mint.AppendUuid(buf, id)
+	buf = mint.AppendString(buf, str100)
+
+	for _, bench := range []struct {
+		name string
+		r    func() io.Reader
+	}{
+		{"plain reader", func() io.Reader { return bytes.NewReader(buf) }},
+		{"decode reader", func() io.Reader { return mint.NewDecodeReader(bytes.NewReader(buf), nil) }},
+		{"bytes decoder", func() io.Reader { return mint.NewBytesDecoder(buf, nil) }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			i := mint.NewInt64Scalar(0)
+			u := mint.NewUuidScalar(uuid.Nil)
+			s := mint.NewStringScalar("")
 
 			for b.Loop() {
-				buf := new(bytes.Buffer)
+				r := bench.r()
 
-				err := bb.Marshall(buf)
+				for _, v := range []mint.Unmarshaller{i, u, s} {
+					err := v.Unmarshall(r)
+					if err != nil {
+						panic(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshallBytes(b *testing.B) {
+	for _, bench := range benches {
+		b.Run(bench.name, func(b *testing.B) {
+			bb := bench.benchmarker()
+
+			buf, err := bb.AppendMarshall(nil)
+			if err != nil {
+				panic(err)
+			}
+
+			for b.Loop() {
+				out := new(Benchmarker)
+
+				_, err = out.UnmarshallBytes(buf)
 				if err != nil {
 					panic(err)
 				}
@@ -59,6 +200,110 @@ func BenchmarkMarshall(b *testing.B) {
 	}
 }
 
+func BenchmarkEncoder(b *testing.B) {
+	bb := benches[1].benchmarker()
+
+	enc := mint.NewEncoder(io.Discard)
+
+	for b.Loop() {
+		err := enc.Encode(bb)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	err := enc.Flush()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// TestBenchmarker_MaxDepth checks that collections count towards
+// MaxDepth, the type itself being one level and its slices another
+func TestBenchmarker_MaxDepth(t *testing.T) {
+	buf, err := benches[0].benchmarker().AppendMarshall(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name      string
+		depth     int
+		expectErr bool
+	}{
+		{"Too shallow for the slice", 1, true},
+		{"Deep enough for the slice", 2, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			opts := &mint.DecodeOptions{MaxDepth: test.depth}
+
+			for name, unmarshall := range map[string]func(*Benchmarker) error{
+				"Unmarshall": func(out *Benchmarker) error {
+					return out.Unmarshall(mint.NewDecodeReader(bytes.NewReader(buf), opts))
+				},
+				"UnmarshallDecoder": func(out *Benchmarker) error {
+					return out.UnmarshallDecoder(mint.NewBytesDecoder(buf, opts))
+				},
+			} {
+				err := unmarshall(new(Benchmarker))
+
+				var le mint.ErrLimitExceeded
+
+				switch {
+				case test.expectErr && !errors.As(err, &le):
+					t.Errorf("%s: expected ErrLimitExceeded, received %#v", name, err)
+
+				case test.expectErr && le.Limit != "depth":
+					t.Errorf("%s: expected depth limit to be exceeded, received %q", name, le.Limit)
+
+				case !test.expectErr && err != nil:
+					t.Errorf("%s: unexpected error %#v", name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestBenchmarker_LargeDeclaredCount(t *testing.T) {
+	buf, err := (&Benchmarker{ID: id, ShortString: str1, LongString: str1}).AppendMarshall(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// replace the empty ManyShortStrings, which precedes the
+	// ManyLongStrings length and SomeNumber, with a count far larger
+	// than the one element which follows it
+	buf = mint.AppendString(mint.AppendUint32(buf[:len(buf)-16:len(buf)-16], 4<<20), str10)
+
+	for name, unmarshall := range map[string]func(*Benchmarker) error{
+		"Unmarshall": func(out *Benchmarker) error {
+			return out.Unmarshall(bytes.NewReader(buf))
+		},
+		"UnmarshallBytes": func(out *Benchmarker) error {
+			_, err := out.UnmarshallBytes(buf)
+
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var before, after runtime.MemStats
+
+			runtime.ReadMemStats(&before)
+			err := unmarshall(new(Benchmarker))
+			runtime.ReadMemStats(&after)
+
+			var te mint.ErrTruncated
+			if !errors.As(err, &te) {
+				t.Errorf("expected ErrTruncated, received %#v", err)
+			}
+
+			if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+				t.Errorf("expected at most 1MiB to be allocated, received %d bytes", allocated)
+			}
+		})
+	}
+}
+
 func makeStringSlice(s string, elems int) (out []string) {
 	out = make([]string, elems)
 	for idx := range out {
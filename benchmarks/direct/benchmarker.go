@@ -0,0 +1,347 @@
+package direct
+
+import (
+	v5 "github.com/gofrs/uuid/v5"
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+type Benchmarker struct {
+	ID               v5.UUID
+	ShortString      string
+	LongString       string
+	ManyShortStrings []string
+	ManyLongStrings  []string
+	SomeNumber       int64
+}
+
+// NewBenchmarker returns a new Benchmarker, with each field set to its default value
+func NewBenchmarker() *Benchmarker {
+	return &Benchmarker{}
+}
+func (sf Benchmarker) Validate() error {
+	errors := make([]error, 0)
+	for _, err := range []error{} {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return mint.ValidationErrors("Benchmarker", errors)
+}
+func (sf *Benchmarker) Transform() (err error) {
+	return
+}
+func (sf Benchmarker) Value() any {
+	return sf
+}
+
+// BenchmarkerFingerprint identifies the encoding of Benchmarker, changing whenever the name, tag, or type of any of its fields, or anything those fields refer to, does
+const BenchmarkerFingerprint uint64 = 0x54f82b5e0cbed272
+
+func (sf Benchmarker) TypeName() string {
+	return "Benchmarker"
+}
+func (sf Benchmarker) Fingerprint() uint64 {
+	return BenchmarkerFingerprint
+}
+func (sf *Benchmarker) unmarshallID(r io.Reader) (err error) {
+	if sf.ID, err = mint.ReadUuid(r); err != nil {
+		return
+	}
+	return
+}
+func (sf *Benchmarker) unmarshallShortString(r io.Reader) (err error) {
+	if sf.ShortString, err = mint.ReadString(r); err != nil {
+		return
+	}
+	return
+}
+func (sf *Benchmarker) unmarshallLongString(r io.Reader) (err error) {
+	if sf.LongString, err = mint.ReadString(r); err != nil {
+		return
+	}
+	return
+}
+func (sf *Benchmarker) unmarshallManyShortStrings(r io.Reader) (err error) {
+	l, err := mint.ReadSliceLen(r)
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.ManyShortStrings = nil
+		return
+	}
+	sf.ManyShortStrings = make([]string, 0, mint.CollectionCap(l))
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := 0; i < l; i++ {
+		var e string
+		if e, err = mint.ReadString(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		sf.ManyShortStrings = append(sf.ManyShortStrings, e)
+	}
+	return
+}
+func (sf *Benchmarker) unmarshallManyLongStrings(r io.Reader) (err error) {
+	l, err := mint.ReadSliceLen(r)
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.ManyLongStrings = nil
+		return
+	}
+	sf.ManyLongStrings = make([]string, 0, mint.CollectionCap(l))
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := 0; i < l; i++ {
+		var e string
+		if e, err = mint.ReadString(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		sf.ManyLongStrings = append(sf.ManyLongStrings, e)
+	}
+	return
+}
+func (sf *Benchmarker) unmarshallSomeNumber(r io.Reader) (err error) {
+	if sf.SomeNumber, err = mint.ReadInt64(r); err != nil {
+		return
+	}
+	return
+}
+func (sf *Benchmarker) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	if err = sf.unmarshallID(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "ID", err)
+	}
+	if err = sf.unmarshallShortString(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "ShortString", err)
+	}
+	if err = sf.unmarshallLongString(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "LongString", err)
+	}
+	if err = sf.unmarshallManyShortStrings(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "ManyShortStrings", err)
+	}
+	if err = sf.unmarshallManyLongStrings(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "ManyLongStrings", err)
+	}
+	if err = sf.unmarshallSomeNumber(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Benchmarker", "SomeNumber", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf Benchmarker) marshallManyShortStrings(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.ManyShortStrings)))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for _, v := range sf.ManyShortStrings {
+		b = mint.AppendString(b[:0], v)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf Benchmarker) marshallManyLongStrings(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.ManyLongStrings)))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for _, v := range sf.ManyLongStrings {
+		b = mint.AppendString(b[:0], v)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf Benchmarker) Marshall(w io.Writer) (err error) {
+	var b []byte
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	b = mint.AppendUuid(b[:0], sf.ID)
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	b = mint.AppendString(b[:0], sf.ShortString)
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	b = mint.AppendString(b[:0], sf.LongString)
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	if err = sf.marshallManyShortStrings(w); err != nil {
+		return
+	}
+	if err = sf.marshallManyLongStrings(w); err != nil {
+		return
+	}
+	b = mint.AppendInt64(b[:0], sf.SomeNumber)
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	return
+}
+func (sf Benchmarker) appendManyShortStrings(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.ManyShortStrings)))
+	for _, v := range sf.ManyShortStrings {
+		b = mint.AppendString(b, v)
+	}
+	return
+}
+func (sf Benchmarker) appendManyLongStrings(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.ManyLongStrings)))
+	for _, v := range sf.ManyLongStrings {
+		b = mint.AppendString(b, v)
+	}
+	return
+}
+func (sf Benchmarker) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	b = mint.AppendUuid(b, sf.ID)
+	b = mint.AppendString(b, sf.ShortString)
+	b = mint.AppendString(b, sf.LongString)
+	if b, err = sf.appendManyShortStrings(b); err != nil {
+		return
+	}
+	if b, err = sf.appendManyLongStrings(b); err != nil {
+		return
+	}
+	b = mint.AppendInt64(b, sf.SomeNumber)
+	return
+}
+func (sf *Benchmarker) decodeManyShortStrings(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadSliceLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.ManyShortStrings = nil
+		return
+	}
+	sf.ManyShortStrings = make([]string, 0, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var e string
+		if e, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		sf.ManyShortStrings = append(sf.ManyShortStrings, e)
+	}
+	return
+}
+func (sf *Benchmarker) decodeManyLongStrings(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadSliceLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.ManyLongStrings = nil
+		return
+	}
+	sf.ManyLongStrings = make([]string, 0, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var e string
+		if e, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		sf.ManyLongStrings = append(sf.ManyLongStrings, e)
+	}
+	return
+}
+func (sf *Benchmarker) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	if sf.ID, err = d.ReadUuid(); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "ID", err)
+	}
+	if sf.ShortString, err = d.ReadString(); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "ShortString", err)
+	}
+	if sf.LongString, err = d.ReadString(); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "LongString", err)
+	}
+	if err = sf.decodeManyShortStrings(d); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "ManyShortStrings", err)
+	}
+	if err = sf.decodeManyLongStrings(d); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "ManyLongStrings", err)
+	}
+	if sf.SomeNumber, err = d.ReadInt64(); err != nil {
+		return mint.WrapDecodeError(d, "Benchmarker", "SomeNumber", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf *Benchmarker) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+func (sf Benchmarker) sizeManyShortStrings() (n int) {
+	n = mint.CollectionHeaderSize
+	for _, v := range sf.ManyShortStrings {
+		n += mint.StringSize(v)
+	}
+	return
+}
+func (sf Benchmarker) sizeManyLongStrings() (n int) {
+	n = mint.CollectionHeaderSize
+	for _, v := range sf.ManyLongStrings {
+		n += mint.StringSize(v)
+	}
+	return
+}
+func (sf Benchmarker) MarshalledSize() (n int) {
+	n += mint.UuidSize
+	n += mint.StringSize(sf.ShortString)
+	n += mint.StringSize(sf.LongString)
+	n += sf.sizeManyShortStrings()
+	n += sf.sizeManyLongStrings()
+	n += mint.Int64Size
+	return
+}
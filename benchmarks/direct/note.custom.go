@@ -0,0 +1,10 @@
+package direct
+
+import (
+	"strings"
+)
+
+// Trim trims s of surrounding whitespace
+func (sf Note) Trim(s string) (string, error) {
+	return strings.TrimSpace(s), nil
+}
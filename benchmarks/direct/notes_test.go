@@ -0,0 +1,60 @@
+package direct
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestNotebook_MarshalledSize(t *testing.T) {
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("UTC+1", 60*60))
+
+	n := Notebook{Note: Note{Text: "  hi  ", Readings: []Reading{{TakenAt: at}}}}
+
+	t.Run("Sizing leaves the value as it is", func(t *testing.T) {
+		size := n.MarshalledSize()
+
+		if n.Note.Text != "  hi  " {
+			t.Errorf("expected Text to be untouched, received %q", n.Note.Text)
+		}
+
+		if n.Note.Readings[0].TakenAt.Location() == time.UTC {
+			t.Error("expected Readings to be untouched")
+		}
+
+		if size != n.MarshalledSize() {
+			t.Errorf("expected the same size each time, received %d and %d", size, n.MarshalledSize())
+		}
+	})
+
+	t.Run("Marshall sizes the transformed value it encodes", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		err := n.Marshall(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		transformed := n
+		transformed.Note.Text = "hi"
+
+		if expect := transformed.MarshalledSize(); expect != buf.Len() {
+			t.Errorf("expected %d bytes, received %d", expect, buf.Len())
+		}
+
+		var out Notebook
+
+		read, err := out.UnmarshallBytes(buf.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if read != buf.Len() {
+			t.Errorf("expected to read %d bytes, received %d", buf.Len(), read)
+		}
+
+		if out.Note.Text != "hi" {
+			t.Errorf("expected %q, received %q", "hi", out.Note.Text)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package direct
+
+import (
+	"fmt"
+	"time"
+)
+
+// InUtc ensures t is in UTC, which it is once transformed
+func (sf Reading) InUtc(name string, t time.Time) error {
+	if t.Location() != time.UTC {
+		return fmt.Errorf("%s should be in UTC", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,434 @@
+package direct
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+type Readings struct {
+	Series []Reading
+	Latest map[string]Reading
+	Pairs  [2][]Reading
+}
+
+// NewReadings returns a new Readings, with each field set to its default value
+func NewReadings() *Readings {
+	return &Readings{}
+}
+func (sf Readings) Validate() error {
+	errors := make([]error, 0)
+	for _, err := range []error{} {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	for i := range sf.Series {
+		if err := sf.Series[i].Validate(); err != nil {
+			errors = append(errors, mint.ErrInvalidElement{Path: "Series" + mint.IndexSegment(i), Err: err})
+		}
+	}
+	{
+		from := len(errors)
+		for k, v := range sf.Latest {
+			if err := v.Validate(); err != nil {
+				errors = append(errors, mint.ErrInvalidElement{Path: "Latest" + mint.KeySegment(k), Err: err})
+			}
+		}
+		mint.SortInvalidElements(errors[from:])
+	}
+	for i := range sf.Pairs {
+		for i1 := range sf.Pairs[i] {
+			if err := sf.Pairs[i][i1].Validate(); err != nil {
+				errors = append(errors, mint.ErrInvalidElement{Path: "Pairs" + mint.IndexSegment(i) + mint.IndexSegment(i1), Err: err})
+			}
+		}
+	}
+	return mint.ValidationErrors("Readings", errors)
+}
+func (sf *Readings) Transform() (err error) {
+	for i := range sf.Series {
+		if err = mint.Transform(&sf.Series[i]); err != nil {
+			return
+		}
+	}
+	for k, v := range sf.Latest {
+		if err = mint.Transform(&v); err != nil {
+			return
+		}
+		sf.Latest[k] = v
+	}
+	for i := range sf.Pairs {
+		for i1 := range sf.Pairs[i] {
+			if err = mint.Transform(&sf.Pairs[i][i1]); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+func (sf Readings) Value() any {
+	return sf
+}
+
+// ReadingsFingerprint identifies the encoding of Readings, changing whenever the name, tag, or type of any of its fields, or anything those fields refer to, does
+const ReadingsFingerprint uint64 = 0x2e3f4511dfe64106
+
+func (sf Readings) TypeName() string {
+	return "Readings"
+}
+func (sf Readings) Fingerprint() uint64 {
+	return ReadingsFingerprint
+}
+func (sf *Readings) unmarshallSeries(r io.Reader) (err error) {
+	l, err := mint.ReadSliceLen(r)
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Series = nil
+		return
+	}
+	sf.Series = make([]Reading, 0, mint.CollectionCap(l))
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := 0; i < l; i++ {
+		var e Reading
+		if err = e.Unmarshall(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		sf.Series = append(sf.Series, e)
+	}
+	return
+}
+func (sf *Readings) unmarshallLatest(r io.Reader) (err error) {
+	l, err := mint.ReadMapLen(r)
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Latest = nil
+		return
+	}
+	sf.Latest = make(map[string]Reading, mint.CollectionCap(l))
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := 0; i < l; i++ {
+		var k string
+		var v Reading
+		if k, err = mint.ReadString(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		if err = v.Unmarshall(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.KeySegment(k), err)
+		}
+		sf.Latest[k] = v
+	}
+	return
+}
+func (sf *Readings) unmarshallPairs(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := range sf.Pairs {
+		l1, err := mint.ReadSliceLen(r)
+		if err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		if l1 == 0 {
+			sf.Pairs[i] = nil
+		} else {
+			sf.Pairs[i] = make([]Reading, 0, mint.CollectionCap(l1))
+			if err = dr.Enter(); err != nil {
+				return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+			}
+			for i1 := 0; i1 < l1; i1++ {
+				var e1 Reading
+				if err = e1.Unmarshall(r); err != nil {
+					dr.Leave()
+					return mint.WrapDecodeError(r, "", mint.IndexSegment(i), mint.WrapDecodeError(r, "", mint.IndexSegment(i1), err))
+				}
+				sf.Pairs[i] = append(sf.Pairs[i], e1)
+			}
+			dr.Leave()
+		}
+	}
+	return
+}
+func (sf *Readings) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	if err = sf.unmarshallSeries(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Readings", "Series", err)
+	}
+	if err = sf.unmarshallLatest(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Readings", "Latest", err)
+	}
+	if err = sf.unmarshallPairs(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Readings", "Pairs", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf Readings) marshallSeries(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.Series)))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for _, v := range sf.Series {
+		if err = v.Marshall(w); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf Readings) marshallLatest(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.Latest)*2))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for k, v := range sf.Latest {
+		b = mint.AppendString(b[:0], k)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+		if err = v.Marshall(w); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf Readings) marshallPairs(w io.Writer) (err error) {
+	var b []byte
+	for _, v := range sf.Pairs {
+		b = mint.AppendUint32(b[:0], uint32(len(v)))
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+		for _, v1 := range v {
+			if err = v1.Marshall(w); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+func (sf Readings) Marshall(w io.Writer) (err error) {
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if err = sf.marshallSeries(w); err != nil {
+		return
+	}
+	if err = sf.marshallLatest(w); err != nil {
+		return
+	}
+	if err = sf.marshallPairs(w); err != nil {
+		return
+	}
+	return
+}
+func (sf Readings) appendSeries(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.Series)))
+	for _, v := range sf.Series {
+		if b, err = v.AppendMarshall(b); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf Readings) appendLatest(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.Latest)*2))
+	for k, v := range sf.Latest {
+		b = mint.AppendString(b, k)
+		if b, err = v.AppendMarshall(b); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf Readings) appendPairs(in []byte) (b []byte, err error) {
+	b = in
+	for _, v := range sf.Pairs {
+		b = mint.AppendUint32(b, uint32(len(v)))
+		for _, v1 := range v {
+			if b, err = v1.AppendMarshall(b); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+func (sf Readings) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if b, err = sf.appendSeries(b); err != nil {
+		return
+	}
+	if b, err = sf.appendLatest(b); err != nil {
+		return
+	}
+	if b, err = sf.appendPairs(b); err != nil {
+		return
+	}
+	return
+}
+func (sf *Readings) decodeSeries(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadSliceLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Series = nil
+		return
+	}
+	sf.Series = make([]Reading, 0, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var e Reading
+		if err = e.UnmarshallDecoder(d); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		sf.Series = append(sf.Series, e)
+	}
+	return
+}
+func (sf *Readings) decodeLatest(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadMapLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Latest = nil
+		return
+	}
+	sf.Latest = make(map[string]Reading, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var k string
+		var v Reading
+		if k, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		if err = v.UnmarshallDecoder(d); err != nil {
+			return mint.WrapDecodeError(d, "", mint.KeySegment(k), err)
+		}
+		sf.Latest[k] = v
+	}
+	return
+}
+func (sf *Readings) decodePairs(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := range sf.Pairs {
+		l1, err := d.ReadSliceLen()
+		if err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		if l1 == 0 {
+			sf.Pairs[i] = nil
+		} else {
+			sf.Pairs[i] = make([]Reading, 0, mint.CollectionCap(l1))
+			if err = d.Enter(); err != nil {
+				return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+			}
+			for i1 := 0; i1 < l1; i1++ {
+				var e1 Reading
+				if err = e1.UnmarshallDecoder(d); err != nil {
+					d.Leave()
+					return mint.WrapDecodeError(d, "", mint.IndexSegment(i), mint.WrapDecodeError(d, "", mint.IndexSegment(i1), err))
+				}
+				sf.Pairs[i] = append(sf.Pairs[i], e1)
+			}
+			d.Leave()
+		}
+	}
+	return
+}
+func (sf *Readings) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	if err = sf.decodeSeries(d); err != nil {
+		return mint.WrapDecodeError(d, "Readings", "Series", err)
+	}
+	if err = sf.decodeLatest(d); err != nil {
+		return mint.WrapDecodeError(d, "Readings", "Latest", err)
+	}
+	if err = sf.decodePairs(d); err != nil {
+		return mint.WrapDecodeError(d, "Readings", "Pairs", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf *Readings) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+func (sf Readings) sizeSeries() (n int) {
+	n = mint.CollectionHeaderSize
+	for _, v := range sf.Series {
+		n += v.MarshalledSize()
+	}
+	return
+}
+func (sf Readings) sizeLatest() (n int) {
+	n = mint.CollectionHeaderSize
+	for k, v := range sf.Latest {
+		n += mint.StringSize(k) + v.MarshalledSize()
+	}
+	return
+}
+func (sf Readings) sizePairs() (n int) {
+	for _, v := range sf.Pairs {
+		n += mint.CollectionHeaderSize
+		for _, v1 := range v {
+			n += v1.MarshalledSize()
+		}
+	}
+	return
+}
+func (sf Readings) MarshalledSize() (n int) {
+	n += sf.sizeSeries()
+	n += sf.sizeLatest()
+	n += sf.sizePairs()
+	return
+}
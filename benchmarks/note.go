@@ -0,0 +1,254 @@
+package benchmarks
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+type Note struct {
+	// Text is trimmed of surrounding whitespace before being encoded, which changes its size
+	Text     string
+	Readings []Reading
+}
+
+// NewNote returns a new Note, with each field set to its default value
+func NewNote() *Note {
+	return &Note{}
+}
+func (sf Note) Validate() error {
+	errors := make([]error, 0)
+	for _, err := range []error{} {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	for i := range sf.Readings {
+		if err := sf.Readings[i].Validate(); err != nil {
+			errors = append(errors, mint.ErrInvalidElement{Path: "Readings" + mint.IndexSegment(i), Err: err})
+		}
+	}
+	return mint.ValidationErrors("Note", errors)
+}
+func (sf *Note) Transform() (err error) {
+	sf.Text, err = sf.Trim(sf.Text)
+	if err != nil {
+		return
+	}
+	for i := range sf.Readings {
+		if err = mint.Transform(&sf.Readings[i]); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf Note) Value() any {
+	return sf
+}
+
+// NoteFingerprint identifies the encoding of Note, changing whenever the name, tag, or type of any of its fields, or anything those fields refer to, does
+const NoteFingerprint uint64 = 0xd6d939c1ed576e1a
+
+func (sf Note) TypeName() string {
+	return "Note"
+}
+func (sf Note) Fingerprint() uint64 {
+	return NoteFingerprint
+}
+func (sf *Note) unmarshallText(r io.Reader) (err error) {
+	f := mint.NewStringScalar("")
+	err = f.Unmarshall(r)
+	if err != nil {
+		return
+	}
+	sf.Text = f.Value().(string)
+	return
+}
+func (sf *Note) unmarshallReadings(r io.Reader) (err error) {
+	f := mint.NewSliceCollection(nil, false)
+	err = f.ReadSize(r)
+	if err != nil {
+		return
+	}
+	if f.Len() == 0 {
+		sf.Readings = nil
+		return
+	}
+	err = f.UnmarshallElements(r, func() mint.MarshallerUnmarshallerValuer {
+		return new(Reading)
+	})
+	if err != nil {
+		return
+	}
+	sf.Readings = make([]Reading, f.Len())
+	for i, v := range f.Value().([]mint.MarshallerUnmarshallerValuer) {
+		sf.Readings[i] = v.Value().(Reading)
+	}
+	return
+}
+func (sf *Note) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	if err = dr.EnterBody(); err != nil {
+		return mint.WrapDecodeError(dr, "Note", "", err)
+	}
+	defer dr.LeaveBody()
+	*sf = *NewNote()
+	_, err = mint.ReadSizedPresence(dr, 0)
+	if err != nil {
+		return mint.WrapDecodeError(dr, "Note", "", err)
+	}
+	if dr.More() {
+		if err = sf.unmarshallText(dr); err != nil {
+			return mint.WrapDecodeError(dr, "Note", "Text", err)
+		}
+	}
+	if dr.More() {
+		if err = sf.unmarshallReadings(dr); err != nil {
+			return mint.WrapDecodeError(dr, "Note", "Readings", err)
+		}
+	}
+	if err = dr.SkipBody(); err != nil {
+		return mint.WrapDecodeError(dr, "Note", "", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf Note) marshallReadings(w io.Writer) (err error) {
+	f := make([]mint.MarshallerUnmarshallerValuer, len(sf.Readings))
+	for i := range f {
+		f[i] = &(sf.Readings[i])
+	}
+	return mint.NewSliceCollection(f, false).Marshall(w)
+}
+func (sf Note) Marshall(w io.Writer) (err error) {
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if err = mint.WriteBodySize(w, sf.MarshalledSize()-mint.BodyHeaderSize); err != nil {
+		return
+	}
+	if err = mint.WriteSizedPresence(w, 0, 0); err != nil {
+		return
+	}
+	if err = mint.NewStringScalar(sf.Text).Marshall(w); err != nil {
+		return
+	}
+	if err = sf.marshallReadings(w); err != nil {
+		return
+	}
+	return
+}
+func (sf Note) appendReadings(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.Readings)))
+	for _, v := range sf.Readings {
+		if b, err = v.AppendMarshall(b); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf Note) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	b, body := mint.AppendBodyHeader(b)
+	b = mint.AppendSizedPresence(b, 0, 0)
+	b = mint.AppendString(b, sf.Text)
+	if b, err = sf.appendReadings(b); err != nil {
+		return
+	}
+	mint.PutBodySize(b, body)
+	return
+}
+func (sf *Note) decodeReadings(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadSliceLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Readings = nil
+		return
+	}
+	sf.Readings = make([]Reading, 0, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var e Reading
+		if err = e.UnmarshallDecoder(d); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		sf.Readings = append(sf.Readings, e)
+	}
+	return
+}
+func (sf *Note) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	if err = d.EnterBody(); err != nil {
+		return mint.WrapDecodeError(d, "Note", "", err)
+	}
+	defer d.LeaveBody()
+	*sf = *NewNote()
+	_, err = d.ReadSizedPresence(0)
+	if err != nil {
+		return mint.WrapDecodeError(d, "Note", "", err)
+	}
+	if d.More() {
+		if sf.Text, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "Note", "Text", err)
+		}
+	}
+	if d.More() {
+		if err = sf.decodeReadings(d); err != nil {
+			return mint.WrapDecodeError(d, "Note", "Readings", err)
+		}
+	}
+	if err = d.SkipBody(); err != nil {
+		return mint.WrapDecodeError(d, "Note", "", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf *Note) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+func (sf Note) sizeReadings() (n int) {
+	n = mint.CollectionHeaderSize
+	for _, v := range sf.Readings {
+		n += v.MarshalledSize()
+	}
+	return
+}
+func (sf Note) MarshalledSize() (n int) {
+	n += mint.BodyHeaderSize
+	n += mint.SizedPresenceSize(0)
+	n += mint.StringSize(sf.Text)
+	n += sf.sizeReadings()
+	return
+}
@@ -0,0 +1,158 @@
+package benchmarks
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+type Notebook struct {
+	Note Note
+}
+
+// NewNotebook returns a new Notebook, with each field set to its default value
+func NewNotebook() *Notebook {
+	return &Notebook{}
+}
+func (sf Notebook) Validate() error {
+	errors := make([]error, 0)
+	for _, err := range []error{} {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return mint.ValidationErrors("Notebook", errors)
+}
+func (sf *Notebook) Transform() (err error) {
+	if err = mint.Transform(&sf.Note); err != nil {
+		return
+	}
+	return
+}
+func (sf Notebook) Value() any {
+	return sf
+}
+
+// NotebookFingerprint identifies the encoding of Notebook, changing whenever the name, tag, or type of any of its fields, or anything those fields refer to, does
+const NotebookFingerprint uint64 = 0xe09c2200561f88c5
+
+func (sf Notebook) TypeName() string {
+	return "Notebook"
+}
+func (sf Notebook) Fingerprint() uint64 {
+	return NotebookFingerprint
+}
+func (sf *Notebook) unmarshallNote(r io.Reader) (err error) {
+	f := new(Note)
+	err = f.Unmarshall(r)
+	if err != nil {
+		return
+	}
+	sf.Note = f.Value().(Note)
+	return
+}
+func (sf *Notebook) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	if err = dr.EnterBody(); err != nil {
+		return mint.WrapDecodeError(dr, "Notebook", "", err)
+	}
+	defer dr.LeaveBody()
+	*sf = *NewNotebook()
+	_, err = mint.ReadSizedPresence(dr, 0)
+	if err != nil {
+		return mint.WrapDecodeError(dr, "Notebook", "", err)
+	}
+	if dr.More() {
+		if err = sf.unmarshallNote(dr); err != nil {
+			return mint.WrapDecodeError(dr, "Notebook", "Note", err)
+		}
+	}
+	if err = dr.SkipBody(); err != nil {
+		return mint.WrapDecodeError(dr, "Notebook", "", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf Notebook) Marshall(w io.Writer) (err error) {
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if err = mint.WriteBodySize(w, sf.MarshalledSize()-mint.BodyHeaderSize); err != nil {
+		return
+	}
+	if err = mint.WriteSizedPresence(w, 0, 0); err != nil {
+		return
+	}
+	if err = sf.Note.Marshall(w); err != nil {
+		return
+	}
+	return
+}
+func (sf Notebook) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	b, body := mint.AppendBodyHeader(b)
+	b = mint.AppendSizedPresence(b, 0, 0)
+	if b, err = sf.Note.AppendMarshall(b); err != nil {
+		return
+	}
+	mint.PutBodySize(b, body)
+	return
+}
+func (sf *Notebook) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	if err = d.EnterBody(); err != nil {
+		return mint.WrapDecodeError(d, "Notebook", "", err)
+	}
+	defer d.LeaveBody()
+	*sf = *NewNotebook()
+	_, err = d.ReadSizedPresence(0)
+	if err != nil {
+		return mint.WrapDecodeError(d, "Notebook", "", err)
+	}
+	if d.More() {
+		if err = sf.Note.UnmarshallDecoder(d); err != nil {
+			return mint.WrapDecodeError(d, "Notebook", "Note", err)
+		}
+	}
+	if err = d.SkipBody(); err != nil {
+		return mint.WrapDecodeError(d, "Notebook", "", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf *Notebook) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+func (sf Notebook) MarshalledSize() (n int) {
+	n += mint.BodyHeaderSize
+	n += mint.SizedPresenceSize(0)
+	n += sf.Note.MarshalledSize()
+	return
+}
@@ -0,0 +1,120 @@
+package benchmarks
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+	"time"
+)
+
+type Reading struct {
+	// TakenAt is when this reading was taken, which is only valid once transformed into UTC
+	TakenAt time.Time
+}
+
+// NewReading returns a new Reading, with each field set to its default value
+func NewReading() *Reading {
+	return &Reading{}
+}
+func (sf Reading) Validate() error {
+	errors := make([]error, 0)
+	for _, err := range []error{sf.InUtc("TakenAt", sf.TakenAt)} {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return mint.ValidationErrors("Reading", errors)
+}
+func (sf *Reading) Transform() (err error) {
+	sf.TakenAt, err = mint.DateInUtc(sf.TakenAt)
+	if err != nil {
+		return
+	}
+	return
+}
+func (sf Reading) Value() any {
+	return sf
+}
+
+// ReadingFingerprint identifies the encoding of Reading, changing whenever the name, tag, or type of any of its fields, or anything those fields refer to, does
+const ReadingFingerprint uint64 = 0x9a8d111100d09186
+
+func (sf Reading) TypeName() string {
+	return "Reading"
+}
+func (sf Reading) Fingerprint() uint64 {
+	return ReadingFingerprint
+}
+func (sf *Reading) unmarshallTakenAt(r io.Reader) (err error) {
+	f := mint.NewDatetimeScalar(time.Time{})
+	err = f.Unmarshall(r)
+	if err != nil {
+		return
+	}
+	sf.TakenAt = f.Value().(time.Time)
+	return
+}
+func (sf *Reading) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	if err = sf.unmarshallTakenAt(dr); err != nil {
+		return mint.WrapDecodeError(dr, "Reading", "TakenAt", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf Reading) Marshall(w io.Writer) (err error) {
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if err = mint.NewDatetimeScalar(sf.TakenAt).Marshall(w); err != nil {
+		return
+	}
+	return
+}
+func (sf Reading) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	b = mint.AppendDatetime(b, sf.TakenAt)
+	return
+}
+func (sf *Reading) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	if sf.TakenAt, err = d.ReadDatetime(); err != nil {
+		return mint.WrapDecodeError(d, "Reading", "TakenAt", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf *Reading) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+func (sf Reading) MarshalledSize() (n int) {
+	n += mint.DatetimeSize
+	return
+}
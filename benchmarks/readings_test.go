@@ -0,0 +1,61 @@
+package benchmarks
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestReadings_Transform(t *testing.T) {
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("UTC+1", 60*60))
+
+	newReadings := func() Readings {
+		return Readings{
+			Series: []Reading{{TakenAt: at}},
+			Latest: map[string]Reading{"a": {TakenAt: at}},
+			Pairs:  [2][]Reading{{{TakenAt: at}}, {{TakenAt: at}}},
+		}
+	}
+
+	t.Run("Elements are invalid until transformed", func(t *testing.T) {
+		r := newReadings()
+
+		if err := r.Validate(); err == nil {
+			t.Fatal("expected error, received nil")
+		}
+
+		if err := r.Transform(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := r.Validate(); err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		for _, reading := range []Reading{r.Series[0], r.Latest["a"], r.Pairs[0][0], r.Pairs[1][0]} {
+			if reading.TakenAt.Location() != time.UTC {
+				t.Errorf("expected %s to be transformed into UTC", reading.TakenAt)
+			}
+		}
+	})
+
+	t.Run("Marshall transforms elements before validating them", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		err := newReadings().Marshall(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out Readings
+
+		err = out.Unmarshall(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !out.Series[0].TakenAt.Equal(at) {
+			t.Errorf("expected %s, received %s", at, out.Series[0].TakenAt)
+		}
+	})
+}
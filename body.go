@@ -0,0 +1,155 @@
+package mint
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// BodyHeaderSize is the size of the length which precedes the body
+// of an evolvable type
+const BodyHeaderSize = 4
+
+// WriteBodySize writes the length of the body of an evolvable type,
+// which is n bytes long, to w
+func WriteBodySize(w io.Writer, n int) error {
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendUint32(b, uint32(n))
+	})
+}
+
+// AppendBodyHeader appends space for the length of the body of an
+// evolvable type to b, returning the extended buffer along with the
+// offset at which the body starts.
+//
+// Once the body has been appended, PutBodySize fills in its length
+func AppendBodyHeader(b []byte) ([]byte, int) {
+	b = append(b, make([]byte, BodyHeaderSize)...)
+
+	return b, len(b)
+}
+
+// PutBodySize fills in the length of the body of an evolvable type,
+// which runs from start to the end of b, as returned by AppendBodyHeader
+func PutBodySize(b []byte, start int) {
+	binary.LittleEndian.PutUint32(b[start-BodyHeaderSize:start], uint32(len(b)-start))
+}
+
+// EnterBody reads the length of the body of an evolvable type, after
+// which nothing past the end of that body may be read until LeaveBody is
+// called. Every successful call to EnterBody must be paired with a call
+// to LeaveBody.
+//
+// Fields are read from a body for as long as More returns true, at which
+// point SkipBody discards any fields which the reader doesn't know about
+func (d *DecodeReader) EnterBody() error {
+	b, err := readScratch(d, BodyHeaderSize)
+	if err != nil {
+		return err
+	}
+
+	l := int64(binary.LittleEndian.Uint32(b))
+
+	// A body can't extend past the end of the body it's nested in
+	if len(d.ends) > 0 {
+		remaining := d.ends[len(d.ends)-1] - d.offset
+		if l > remaining {
+			return ErrTruncated{
+				Offset:   d.offset + remaining,
+				Read:     remaining,
+				Expected: l,
+				err:      io.ErrUnexpectedEOF,
+			}
+		}
+	}
+
+	d.ends = append(d.ends, d.offset+l)
+
+	return nil
+}
+
+// More returns true where there are fields left to read in the body
+// of the evolvable type being decoded
+func (d *DecodeReader) More() bool {
+	return len(d.ends) > 0 && d.offset < d.ends[len(d.ends)-1]
+}
+
+// SkipBody discards the remainder of the body of the evolvable type
+// being decoded, which holds any fields added since the reader was
+// generated
+func (d *DecodeReader) SkipBody() error {
+	if !d.More() {
+		return nil
+	}
+
+	l := d.ends[len(d.ends)-1] - d.offset
+
+	n, err := io.CopyN(io.Discard, d, l)
+	if err != nil {
+		// io.CopyN reports any shortfall as io.EOF, whereas
+		// io.ReadFull distinguishes between no, and some, data
+		if n > 0 && err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return truncatedErr(d, err, n, l)
+	}
+
+	return nil
+}
+
+// LeaveBody records that decoding has finished with the body of an
+// evolvable type
+func (d *DecodeReader) LeaveBody() {
+	if len(d.ends) > 0 {
+		d.ends = d.ends[:len(d.ends)-1]
+	}
+}
+
+// EnterBody reads the length of the body of an evolvable type, after
+// which nothing past the end of that body may be read until LeaveBody is
+// called. Every successful call to EnterBody must be paired with a call
+// to LeaveBody
+func (d *BytesDecoder) EnterBody() error {
+	l, err := d.ReadUint32()
+	if err != nil {
+		return err
+	}
+
+	if int64(l) > int64(d.Remaining()) {
+		return ErrTruncated{
+			Offset:   int64(d.limit()),
+			Read:     int64(d.Remaining()),
+			Expected: int64(l),
+			err:      io.ErrUnexpectedEOF,
+		}
+	}
+
+	d.ends = append(d.ends, d.offset+int(l))
+
+	return nil
+}
+
+// More returns true where there are fields left to read in the body
+// of the evolvable type being decoded
+func (d *BytesDecoder) More() bool {
+	return len(d.ends) > 0 && d.Remaining() > 0
+}
+
+// SkipBody discards the remainder of the body of the evolvable type
+// being decoded, which holds any fields added since the reader was
+// generated
+func (d *BytesDecoder) SkipBody() error {
+	if len(d.ends) > 0 {
+		d.offset = d.limit()
+	}
+
+	return nil
+}
+
+// LeaveBody records that decoding has finished with the body of an
+// evolvable type
+func (d *BytesDecoder) LeaveBody() {
+	if len(d.ends) > 0 {
+		d.ends = d.ends[:len(d.ends)-1]
+	}
+}
@@ -0,0 +1,142 @@
+package mint
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// body returns an evolvable body holding the int16 fields f
+func body(f ...int16) []byte {
+	b, start := AppendBodyHeader(nil)
+	for _, i := range f {
+		b = AppendInt16(b, i)
+	}
+
+	PutBodySize(b, start)
+
+	return b
+}
+
+// bodyReader reads from evolvable bodies via either a DecodeReader
+// or a BytesDecoder
+type bodyReader interface {
+	io.Reader
+	EnterBody() error
+	More() bool
+	SkipBody() error
+	LeaveBody()
+}
+
+func bodyReaders(b []byte) map[string]bodyReader {
+	return map[string]bodyReader{
+		"DecodeReader": NewDecodeReader(bytes.NewReader(b), nil),
+		"BytesDecoder": NewBytesDecoder(b, nil),
+	}
+}
+
+func TestBody(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		input  []byte
+		read   int
+		expect []int16
+	}{
+		{"Reader knows every field", body(1, 2), 2, []int16{1, 2}},
+		{"Reader knows fewer fields", body(1, 2, 3), 2, []int16{1, 2}},
+		{"Reader knows more fields", body(1), 2, []int16{1}},
+		{"Empty body", body(), 2, []int16{}},
+	} {
+		for name, r := range bodyReaders(append(test.input, 0xff, 0xff)) {
+			t.Run(test.name+" via "+name, func(t *testing.T) {
+				err := r.EnterBody()
+				if err != nil {
+					t.Fatalf("unexpected error %#v", err)
+				}
+
+				received := make([]int16, 0)
+				for i := 0; i < test.read && r.More(); i++ {
+					v, err := ReadInt16(r)
+					if err != nil {
+						t.Fatalf("unexpected error %#v", err)
+					}
+
+					received = append(received, v)
+				}
+
+				err = r.SkipBody()
+				if err != nil {
+					t.Fatalf("unexpected error %#v", err)
+				}
+
+				r.LeaveBody()
+
+				if len(test.expect) != len(received) {
+					t.Fatalf("expected %v, received %v", test.expect, received)
+				}
+
+				for i := range received {
+					if test.expect[i] != received[i] {
+						t.Errorf("expected %v, received %v", test.expect, received)
+					}
+				}
+
+				// Whatever follows the body must be left intact
+				v, err := ReadInt16(r)
+				if err != nil {
+					t.Fatalf("unexpected error %#v", err)
+				}
+
+				if v != -1 {
+					t.Errorf("expected -1 after body, received %d", v)
+				}
+			})
+		}
+	}
+}
+
+func TestBody_Bounded(t *testing.T) {
+	for name, r := range bodyReaders(append(body(1), 0, 0)) {
+		t.Run(name, func(t *testing.T) {
+			err := r.EnterBody()
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			_, err = ReadInt32(r)
+			if !errors.As(err, new(ErrTruncated)) {
+				t.Errorf("expected ErrTruncated, received %#v", err)
+			}
+		})
+	}
+}
+
+func TestBody_Truncated(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		input  []byte
+		nested bool
+	}{
+		{"Missing length", []byte{0x02, 0x00}, false},
+		{"Length longer than input", body(1, 2)[:6], false},
+		{"Length longer than enclosing body", append(AppendUint32(nil, 6), body(1, 2)...), true},
+	} {
+		for name, r := range bodyReaders(test.input) {
+			t.Run(test.name+" via "+name, func(t *testing.T) {
+				err := r.EnterBody()
+				if err == nil && test.nested {
+					err = r.EnterBody()
+				}
+
+				if err == nil {
+					err = r.SkipBody()
+				}
+
+				if !errors.As(err, new(ErrTruncated)) {
+					t.Errorf("expected ErrTruncated, received %#v", err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package mint
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// BytesDecoder decodes mint data from a byte slice already held in
+// memory, reading directly from that slice rather than going through
+// an io.Reader.
+//
+// Every read is bounds checked, returning ErrTruncated where the slice
+// is too short, and lengths are checked against DecodeOptions in the same
+// way as a DecodeReader.
+//
+// BytesDecoder also implements io.Reader, and so may be passed to any
+// Unmarshaller
+type BytesDecoder struct {
+	b      []byte
+	offset int
+	opts   DecodeOptions
+	depth  int
+
+	// ends holds the offset at which each evolvable type currently
+	// being decoded finishes, innermost last; see EnterBody
+	ends []int
+}
+
+// NewBytesDecoder returns a BytesDecoder reading from the start of b,
+// enforcing the limits in o. A nil o uses DefaultDecodeOptions
+func NewBytesDecoder(b []byte, o *DecodeOptions) *BytesDecoder {
+	opts := DefaultDecodeOptions
+	if o != nil {
+		opts = o.withDefaults()
+	}
+
+	return &BytesDecoder{
+		b:    b,
+		opts: opts,
+	}
+}
+
+// Offset returns the number of bytes decoded so far
+func (d *BytesDecoder) Offset() int64 {
+	return int64(d.offset)
+}
+
+// Remaining returns the number of bytes left to decode; within the
+// body of an evolvable type, this is the number left in that body
+func (d *BytesDecoder) Remaining() int {
+	return d.limit() - d.offset
+}
+
+// limit returns the offset at which input runs out; either the end
+// of the evolvable type being decoded, or else the end of the slice
+func (d *BytesDecoder) limit() int {
+	if len(d.ends) > 0 {
+		return d.ends[len(d.ends)-1]
+	}
+
+	return len(d.b)
+}
+
+// Enter records that decoding has descended into a type or collection,
+// returning an error should this breach MaxDepth. Every successful call
+// to Enter must be paired with a call to Leave
+func (d *BytesDecoder) Enter() error {
+	if d.depth >= d.opts.MaxDepth {
+		return ErrLimitExceeded{
+			Limit:    "depth",
+			Max:      int64(d.opts.MaxDepth),
+			Received: int64(d.depth + 1),
+		}
+	}
+
+	d.depth++
+
+	return nil
+}
+
+// Leave records that decoding has finished with a type or collection
+func (d *BytesDecoder) Leave() {
+	if d.depth > 0 {
+		d.depth--
+	}
+}
+
+// Read implements io.Reader; within the body of an evolvable type, it
+// reads no further than the end of that body
+func (d *BytesDecoder) Read(p []byte) (n int, err error) {
+	if d.Remaining() == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+
+	n = copy(p, d.b[d.offset:d.limit()])
+	d.offset += n
+
+	return
+}
+
+// ReadString reads a string
+func (d *BytesDecoder) ReadString() (s string, err error) {
+	start := d.offset
+
+	l, err := d.ReadInt64()
+	if err != nil {
+		return
+	}
+
+	err = checkStringLength(d, l)
+	if err != nil {
+		return
+	}
+
+	b, err := d.next(l)
+	if err != nil {
+		d.offset = start
+
+		return
+	}
+
+	return string(b), nil
+}
+
+// ReadDatetime reads a datetime
+func (d *BytesDecoder) ReadDatetime() (time.Time, error) {
+	i, err := d.ReadInt64()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return timeFromUnixNano(i), nil
+}
+
+// ReadUuid reads a uuid
+func (d *BytesDecoder) ReadUuid() (u uuid.UUID, err error) {
+	b, err := d.next(uuid.Size)
+	if err != nil {
+		return
+	}
+
+	copy(u[:], b)
+
+	return
+}
+
+// ReadInt16 reads an int16
+func (d *BytesDecoder) ReadInt16() (int16, error) {
+	i, err := d.ReadUint16()
+
+	return int16(i), err
+}
+
+// ReadUint16 reads a uint16
+func (d *BytesDecoder) ReadUint16() (uint16, error) {
+	b, err := d.next(2)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+// ReadInt32 reads an int32
+func (d *BytesDecoder) ReadInt32() (int32, error) {
+	i, err := d.ReadUint32()
+
+	return int32(i), err
+}
+
+// ReadUint32 reads a uint32
+func (d *BytesDecoder) ReadUint32() (uint32, error) {
+	b, err := d.next(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// ReadInt64 reads an int64
+func (d *BytesDecoder) ReadInt64() (int64, error) {
+	b, err := d.next(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(binary.LittleEndian.Uint64(b)), nil
+}
+
+// ReadFloat32 reads a float32
+func (d *BytesDecoder) ReadFloat32() (float32, error) {
+	i, err := d.ReadUint32()
+
+	return math.Float32frombits(i), err
+}
+
+// ReadFloat64 reads a float64
+func (d *BytesDecoder) ReadFloat64() (float64, error) {
+	b, err := d.next(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+}
+
+// ReadByte reads a byte
+func (d *BytesDecoder) ReadByte() (byte, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
+
+// ReadBool reads a bool
+func (d *BytesDecoder) ReadBool() (bool, error) {
+	b, err := d.ReadByte()
+
+	return b != 0, err
+}
+
+// ReadSliceLen reads the number of elements in a slice, returning
+// ErrTruncated where there are fewer bytes left than elements
+func (d *BytesDecoder) ReadSliceLen() (int, error) {
+	l, err := d.ReadUint32()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(l), d.checkCollectionLength(l)
+}
+
+// ReadMapLen reads the number of entries in a map, returning
+// ErrTruncated where there are fewer bytes left than entries
+func (d *BytesDecoder) ReadMapLen() (int, error) {
+	l, err := d.ReadUint32()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(l / 2), d.checkCollectionLength(l / 2)
+}
+
+// checkCollectionLength ensures a collection declaring l elements is
+// within the limits in force, and that there are at least as many bytes
+// left as elements, so that a count alone can't cause anything to be
+// allocated for elements which the input doesn't hold
+func (d *BytesDecoder) checkCollectionLength(l uint32) error {
+	err := checkCollectionLength(d, l)
+	if err != nil {
+		return err
+	}
+
+	if remaining := d.Remaining(); int64(l) > int64(remaining) {
+		return ErrTruncated{
+			Offset:   d.Offset(),
+			Read:     int64(remaining),
+			Expected: int64(l),
+			err:      io.ErrUnexpectedEOF,
+		}
+	}
+
+	return nil
+}
+
+// next returns the next n bytes, advancing the cursor past them
+func (d *BytesDecoder) next(n int64) (b []byte, err error) {
+	remaining := int64(d.Remaining())
+	if n > remaining {
+		err = io.ErrUnexpectedEOF
+		if remaining == 0 {
+			err = io.EOF
+		}
+
+		return nil, ErrTruncated{
+			Offset:   int64(d.limit()),
+			Read:     remaining,
+			Expected: n,
+			err:      err,
+		}
+	}
+
+	b = d.b[d.offset : d.offset+int(n)]
+	d.offset += int(n)
+
+	return
+}
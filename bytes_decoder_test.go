@@ -0,0 +1,214 @@
+package mint
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestBytesDecoder(t *testing.T) {
+	now := time.Now().Truncate(0)
+	u := uuid.Must(uuid.NewV4())
+
+	b := AppendString(nil, "Hello, World!")
+	b = AppendDatetime(b, now)
+	b = AppendDatetime(b, time.Time{})
+	b = AppendUuid(b, u)
+	b = AppendInt16(b, -10_000)
+	b = AppendUint16(b, 10_000)
+	b = AppendInt32(b, -10_000)
+	b = AppendUint32(b, 10_000)
+	b = AppendInt64(b, -10_000)
+	b = AppendFloat32(b, 3.14)
+	b = AppendFloat64(b, -3.14)
+	b = AppendByte(b, 'a')
+	b = AppendBool(b, true)
+
+	d := NewBytesDecoder(b, nil)
+
+	for _, test := range []struct {
+		name   string
+		f      func() (any, error)
+		expect any
+	}{
+		{"String", func() (any, error) { return d.ReadString() }, "Hello, World!"},
+		{"Datetime", func() (any, error) { return d.ReadDatetime() }, now},
+		{"Empty datetime", func() (any, error) { return d.ReadDatetime() }, time.Time{}},
+		{"Uuid", func() (any, error) { return d.ReadUuid() }, u},
+		{"Int16", func() (any, error) { return d.ReadInt16() }, int16(-10_000)},
+		{"Uint16", func() (any, error) { return d.ReadUint16() }, uint16(10_000)},
+		{"Int32", func() (any, error) { return d.ReadInt32() }, int32(-10_000)},
+		{"Uint32", func() (any, error) { return d.ReadUint32() }, uint32(10_000)},
+		{"Int64", func() (any, error) { return d.ReadInt64() }, int64(-10_000)},
+		{"Float32", func() (any, error) { return d.ReadFloat32() }, float32(3.14)},
+		{"Float64", func() (any, error) { return d.ReadFloat64() }, float64(-3.14)},
+		{"Byte", func() (any, error) { return d.ReadByte() }, byte('a')},
+		{"Bool", func() (any, error) { return d.ReadBool() }, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			received, err := test.f()
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if tm, ok := received.(time.Time); ok {
+				if !tm.Equal(test.expect.(time.Time)) {
+					t.Errorf("expected %v, received %v", test.expect, received)
+				}
+
+				return
+			}
+
+			if test.expect != received {
+				t.Errorf("expected %#v, received %#v", test.expect, received)
+			}
+		})
+	}
+
+	if d.Remaining() != 0 {
+		t.Errorf("expected all input to be consumed, %d bytes remain", d.Remaining())
+	}
+}
+
+func TestBytesDecoder_Truncated(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		input  []byte
+		f      func(*BytesDecoder) error
+		expect ErrTruncated
+	}{
+		{"Empty input", []byte{}, func(d *BytesDecoder) (err error) { _, err = d.ReadInt64(); return }, ErrTruncated{Offset: 0, Read: 0, Expected: 8}},
+		{"Short int", []byte{1, 2}, func(d *BytesDecoder) (err error) { _, err = d.ReadInt32(); return }, ErrTruncated{Offset: 2, Read: 2, Expected: 4}},
+		{"Short string", AppendString(nil, "Hello, World!")[:12], func(d *BytesDecoder) (err error) { _, err = d.ReadString(); return }, ErrTruncated{Offset: 12, Read: 4, Expected: 13}},
+		{"Short uuid", make([]byte, 10), func(d *BytesDecoder) (err error) { _, err = d.ReadUuid(); return }, ErrTruncated{Offset: 10, Read: 10, Expected: 16}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.f(NewBytesDecoder(test.input, nil))
+			if err == nil {
+				t.Fatal("expected error, received none")
+			}
+
+			var received ErrTruncated
+			if !errors.As(err, &received) {
+				t.Fatalf("expected ErrTruncated, received %#v", err)
+			}
+
+			if received.Offset != test.expect.Offset || received.Read != test.expect.Read || received.Expected != test.expect.Expected {
+				t.Errorf("expected %#v, received %#v", test.expect, received)
+			}
+
+			if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Errorf("expected error to unwrap to an EOF, received %#v", err)
+			}
+		})
+	}
+}
+
+func TestBytesDecoder_Limits(t *testing.T) {
+	opts := &DecodeOptions{
+		MaxStringBytes:        4,
+		MaxCollectionElements: 2,
+		MaxDepth:              1,
+	}
+
+	for _, test := range []struct {
+		name   string
+		input  []byte
+		f      func(*BytesDecoder) error
+		expect string
+	}{
+		{"Long string", AppendString(nil, "Hello"), func(d *BytesDecoder) (err error) { _, err = d.ReadString(); return }, "string bytes"},
+		{"Negative string length", AppendInt64(nil, -1), func(d *BytesDecoder) (err error) { _, err = d.ReadString(); return }, "string bytes"},
+		{"Long slice", AppendUint32(nil, 3), func(d *BytesDecoder) (err error) { _, err = d.ReadSliceLen(); return }, "collection elements"},
+		{"Long map", AppendUint32(nil, 6), func(d *BytesDecoder) (err error) { _, err = d.ReadMapLen(); return }, "collection elements"},
+		{"Too deep", nil, func(d *BytesDecoder) (err error) {
+			if err = d.Enter(); err != nil {
+				return
+			}
+
+			return d.Enter()
+		}, "depth"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.f(NewBytesDecoder(test.input, opts))
+			if err == nil {
+				t.Fatal("expected error, received none")
+			}
+
+			var received ErrLimitExceeded
+			if !errors.As(err, &received) {
+				t.Fatalf("expected ErrLimitExceeded, received %#v", err)
+			}
+
+			if test.expect != received.Limit {
+				t.Errorf("expected %q, received %q", test.expect, received.Limit)
+			}
+		})
+	}
+}
+
+func TestBytesDecoder_AsReader(t *testing.T) {
+	b := AppendString(nil, "Hello, World!")
+	b = AppendInt64(b, 12345)
+
+	d := NewBytesDecoder(b, nil)
+
+	s := NewStringScalar("")
+	err := s.Unmarshall(d)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if s.Value() != "Hello, World!" {
+		t.Errorf("expected %q, received %q", "Hello, World!", s.Value())
+	}
+
+	i, err := d.ReadInt64()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if i != 12345 {
+		t.Errorf("expected 12345, received %d", i)
+	}
+}
+
+func TestBytesDecoder_ReadWithinBody(t *testing.T) {
+	b := AppendUint32(nil, 2)
+	b = append(b, "hi"...)
+	b = append(b, "garbage"...)
+
+	d := NewBytesDecoder(b, nil)
+
+	err := d.EnterBody()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	body, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if string(body) != "hi" {
+		t.Errorf("expected %q, received %q", "hi", body)
+	}
+
+	if d.Remaining() != 0 {
+		t.Errorf("expected 0 bytes remaining in body, received %d", d.Remaining())
+	}
+
+	d.LeaveBody()
+
+	rest, err := io.ReadAll(d)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if string(rest) != "garbage" {
+		t.Errorf("expected %q, received %q", "garbage", rest)
+	}
+}
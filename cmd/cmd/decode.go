@@ -0,0 +1,191 @@
+/*
+Copyright © 2023 Vinyl Linux
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/spf13/cobra"
+	"github.com/vinyl-linux/mint/dynamic"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// decodeCmd represents the decode command
+var decodeCmd = &cobra.Command{
+	Use:   "decode --schema ./directory/of/mint-documents --type Type [payload.bin]",
+	Short: "Decode a binary payload to JSON",
+	Long: `Decode parses a directory full of mint documents and uses them to
+decode a binary payload of the given type, printing it as JSON.
+
+The payload is read from the named file or, where none is given (or
+the name is -), from stdin. Datetimes are printed as RFC3339, uuids as
+strings, and enums by name. Input holding anything more than a single
+payload is rejected.
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("only one payload may be decoded at a time")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		err := decode(cmd, args)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+			failer(errInvalid)
+		}
+	},
+}
+
+// decode does the work of decodeCmd, returning any error it encounters
+func decode(cmd *cobra.Command, args []string) (err error) {
+	a, err := parser.ParseDir(mustString(cmd.Flags().GetString("schema")))
+	if err != nil {
+		return
+	}
+
+	t, err := dynamic.New(a, mustString(cmd.Flags().GetString("type")))
+	if err != nil {
+		return
+	}
+
+	in := cmd.InOrStdin()
+	if len(args) == 1 && args[0] != "-" {
+		// #nosec: G304
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+
+		// #nosec: G307
+		defer f.Close()
+
+		in = f
+	}
+
+	m, err := t.Unmarshall(in)
+	if err != nil {
+		return
+	}
+
+	// a payload followed by anything else is not a payload of this type,
+	// however well the start of it decodes
+	trailing, err := io.Copy(io.Discard, in)
+	if err != nil {
+		return
+	}
+
+	if trailing > 0 {
+		return fmt.Errorf("%d unexpected bytes after %s payload", trailing, t.Name())
+	}
+
+	out, err := json.MarshalIndent(jsonValue(m), "", "  ")
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", out)
+
+	return
+}
+
+// jsonValue converts v, a value returned by the dynamic package, into
+// something encoding/json prints legibly; maps are keyed by strings,
+// and unions printed as an object holding just the variant which is set
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case dynamic.Message:
+		out := make(map[string]any, len(v))
+		for k, fv := range v {
+			out[k] = jsonValue(fv)
+		}
+
+		return out
+
+	case dynamic.Union:
+		return map[string]any{
+			v.Variant: jsonValue(v.Value),
+		}
+
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = jsonValue(e)
+		}
+
+		return out
+
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, mv := range v {
+			out[jsonKey(k)] = jsonValue(mv)
+		}
+
+		return out
+
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+
+	case uuid.UUID:
+		return v.String()
+	}
+
+	return v
+}
+
+// jsonKey returns the string a map key is printed as
+func jsonKey(k any) string {
+	switch k := k.(type) {
+	case string:
+		return k
+
+	case time.Time:
+		return k.UTC().Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprint(k)
+}
+
+func init() {
+	rootCmd.AddCommand(decodeCmd)
+
+	decodeCmd.Flags().StringP("schema", "s", "", "Directory of mint documents describing the payload")
+	decodeCmd.Flags().StringP("type", "t", "", "Type of the payload, optionally qualified by package, such as geo.Location")
+
+	cobra.CheckErr(decodeCmd.MarkFlagRequired("schema"))
+	cobra.CheckErr(decodeCmd.MarkFlagRequired("type"))
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/vinyl-linux/mint/dynamic"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+const expectDecodedLocation = `{
+  "ID": "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+  "Labels": {
+    "country": "uk"
+  },
+  "Latitude": 51.5,
+  "Location": "London",
+  "Longitude": -0.125,
+  "Tags": [
+    "capital",
+    "big"
+  ],
+  "Type": "Home"
+}
+`
+
+func locationPayload(t *testing.T) []byte {
+	t.Helper()
+
+	a, err := parser.ParseDir("testdata/valid-documents")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typ, err := dynamic.New(a, "Location")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	err = typ.Marshall(buf, dynamic.Message{
+		"Location":  "London",
+		"Latitude":  float32(51.5),
+		"Longitude": float32(-0.125),
+		"Tags":      []any{"capital", "big"},
+		"Labels":    map[any]any{"country": "uk"},
+		"ID":        uuid.Must(uuid.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")),
+		"Type":      "Home",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestDecodeCmd_Run(t *testing.T) {
+	origFailer := failer
+	defer func() {
+		failer = origFailer
+	}()
+
+	payload := locationPayload(t)
+
+	fn := filepath.Join(t.TempDir(), "payload.bin")
+
+	err := os.WriteFile(fn, payload, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name         string
+		schema       string
+		typ          string
+		args         []string
+		stdin        []byte
+		expectStatus int
+		expectOutput string
+	}{
+		{"From file", "testdata/valid-documents", "Location", []string{fn}, nil, 0, expectDecodedLocation},
+		{"From stdin", "testdata/valid-documents", "Location", nil, payload, 0, expectDecodedLocation},
+		{"From stdin, named", "testdata/valid-documents", "Location", []string{"-"}, payload, 0, expectDecodedLocation},
+		{"Invalid documents", "testdata/invalid-document", "Location", []string{fn}, nil, 1, ""},
+		{"Unknown type", "testdata/valid-documents", "Nope", []string{fn}, nil, 1, ""},
+		{"Missing payload", "testdata/valid-documents", "Location", []string{"testdata/nonsuch"}, nil, 1, ""},
+		{"Truncated payload", "testdata/valid-documents", "Location", nil, payload[:10], 1, ""},
+		{"Trailing garbage", "testdata/valid-documents", "Location", nil, append(payload[:len(payload):len(payload)], "garbage"...), 1, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rs := returnedStatus{0}
+			failer = rs.fail
+
+			stdout := new(bytes.Buffer)
+
+			decodeCmd.SetOut(stdout)
+			decodeCmd.SetErr(new(bytes.Buffer))
+			decodeCmd.SetIn(bytes.NewReader(test.stdin))
+
+			defer func() {
+				decodeCmd.SetOut(nil)
+				decodeCmd.SetErr(nil)
+				decodeCmd.SetIn(nil)
+			}()
+
+			err := decodeCmd.Flags().Set("schema", test.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = decodeCmd.Flags().Set("type", test.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decodeCmd.Run(decodeCmd, test.args)
+
+			if test.expectStatus != rs.v {
+				t.Errorf("expected %d, received %d", test.expectStatus, rs.v)
+			}
+
+			if test.expectOutput != stdout.String() {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expectOutput, stdout.String())
+			}
+		})
+	}
+}
+
+func TestDecodeCmd_Arg(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{"no args reads stdin", []string{}, false},
+		{"one arg is successful", []string{"payload.bin"}, false},
+		{"two many args errors", []string{"payload.bin", "another.bin"}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := decodeCmd.Args(nil, test.args)
+			if err == nil && test.expectErr {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectErr {
+				t.Errorf("unexpected error %#v", err)
+			}
+		})
+	}
+}
+
+func TestJsonValue(t *testing.T) {
+	received := jsonValue(dynamic.Message{
+		"Union": dynamic.Union{Variant: "Note", Value: "hi"},
+		"Keys":  map[any]any{int16(3): true},
+	})
+
+	expect := map[string]any{
+		"Union": map[string]any{"Note": "hi"},
+		"Keys":  map[string]any{"3": true},
+	}
+
+	if !reflect.DeepEqual(expect, received) {
+		t.Errorf("expected %#v, received %#v", expect, received)
+	}
+}
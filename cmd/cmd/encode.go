@@ -0,0 +1,182 @@
+/*
+Copyright © 2023 Vinyl Linux
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+ 1. Redistributions of source code must retain the above copyright notice,
+    this list of conditions and the following disclaimer.
+
+ 2. Redistributions in binary form must reproduce the above copyright notice,
+    this list of conditions and the following disclaimer in the documentation
+    and/or other materials provided with the distribution.
+
+ 3. Neither the name of the copyright holder nor the names of its contributors
+    may be used to endorse or promote products derived from this software
+    without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vinyl-linux/mint/dynamic"
+	"github.com/vinyl-linux/mint/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// encodeCmd represents the encode command
+var encodeCmd = &cobra.Command{
+	Use:   "encode --schema ./directory/of/mint-documents --type Type [fixture.json]",
+	Short: "Encode a JSON or YAML fixture to binary",
+	Long: `Encode parses a directory full of mint documents and uses them to
+encode a JSON or YAML fixture of the given type, writing the binary
+payload to stdout.
+
+The fixture is read from the named file or, where none is given (or
+the name is -), from stdin, and is written as mint decode prints it.
+Each value is checked against the type it's encoded as, and mint's
+builtin transforms and validators are run, with errors reporting the
+path to the offending value.
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("only one fixture may be encoded at a time")
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		err := encode(cmd, args)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+			failer(errInvalid)
+		}
+	},
+}
+
+// encode does the work of encodeCmd, returning any error it encounters
+func encode(cmd *cobra.Command, args []string) (err error) {
+	a, err := parser.ParseDir(mustString(cmd.Flags().GetString("schema")))
+	if err != nil {
+		return
+	}
+
+	t, err := dynamic.New(a, mustString(cmd.Flags().GetString("type")))
+	if err != nil {
+		return
+	}
+
+	in := cmd.InOrStdin()
+	format := mustString(cmd.Flags().GetString("format"))
+
+	if len(args) == 1 && args[0] != "-" {
+		// #nosec: G304
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+
+		// #nosec: G307
+		defer f.Close()
+
+		in = f
+
+		if format == "" {
+			format = fixtureFormat(args[0])
+		}
+	}
+
+	v, err := readFixture(in, format)
+	if err != nil {
+		return
+	}
+
+	m, err := t.Convert(v)
+	if err != nil {
+		return
+	}
+
+	err = t.Transform(m)
+	if err != nil {
+		return
+	}
+
+	err = t.Validate(m)
+	if err != nil {
+		return
+	}
+
+	// Encode to a buffer first, so that nothing is written on error
+	buf := new(bytes.Buffer)
+
+	err = t.Marshall(buf, m)
+	if err != nil {
+		return
+	}
+
+	_, err = buf.WriteTo(cmd.OutOrStdout())
+
+	return
+}
+
+// fixtureFormat returns the format of the fixture fn, based on its
+// extension
+func fixtureFormat(fn string) string {
+	switch filepath.Ext(fn) {
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+
+	return "json"
+}
+
+// readFixture reads a fixture in format from r; numbers in JSON fixtures
+// are read as json.Numbers, so that large integers keep their precision
+func readFixture(r io.Reader, format string) (v any, err error) {
+	switch format {
+	case "", "json":
+		d := json.NewDecoder(r)
+		d.UseNumber()
+
+		err = d.Decode(&v)
+
+	case "yaml":
+		err = yaml.NewDecoder(r).Decode(&v)
+
+	default:
+		err = fmt.Errorf("unknown fixture format %q, expected json or yaml", format)
+	}
+
+	return
+}
+
+func init() {
+	rootCmd.AddCommand(encodeCmd)
+
+	encodeCmd.Flags().StringP("schema", "s", "", "Directory of mint documents describing the fixture")
+	encodeCmd.Flags().StringP("type", "t", "", "Type of the fixture, optionally qualified by package, such as geo.Location")
+	encodeCmd.Flags().StringP("format", "f", "", "Format of the fixture, either json or yaml (default is based on the fixture's extension, or json for stdin)")
+
+	cobra.CheckErr(encodeCmd.MarkFlagRequired("schema"))
+	cobra.CheckErr(encodeCmd.MarkFlagRequired("type"))
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const locationYAML = `
+Location: London
+Latitude: 51.5
+Longitude: -0.125
+Tags:
+  - capital
+  - big
+Labels:
+  country: uk
+ID: 6ba7b810-9dad-11d1-80b4-00c04fd430c8
+Type: Home
+`
+
+func TestEncodeCmd_Run(t *testing.T) {
+	origFailer := failer
+	defer func() {
+		failer = origFailer
+	}()
+
+	payload := locationPayload(t)
+	dir := t.TempDir()
+
+	fixtures := map[string]string{
+		"location.json": expectDecodedLocation,
+		"location.yaml": locationYAML,
+		"location.txt":  locationYAML,
+		"empty.json":    `{"Location": "", "ID": "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}`,
+		"invalid.json":  `{"Location": "London", "Tags": ["capital", 3]}`,
+	}
+
+	for fn, contents := range fixtures {
+		err := os.WriteFile(filepath.Join(dir, fn), []byte(contents), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		name         string
+		typ          string
+		format       string
+		args         []string
+		stdin        string
+		expectStatus int
+		expectOutput []byte
+		expectError  string
+	}{
+		{"JSON file", "Location", "", []string{filepath.Join(dir, "location.json")}, "", 0, payload, ""},
+		{"YAML file", "Location", "", []string{filepath.Join(dir, "location.yaml")}, "", 0, payload, ""},
+		{"YAML file, named format", "Location", "yaml", []string{filepath.Join(dir, "location.txt")}, "", 0, payload, ""},
+		{"JSON from stdin", "Location", "", nil, expectDecodedLocation, 0, payload, ""},
+		{"YAML from stdin", "Location", "yaml", []string{"-"}, locationYAML, 0, payload, ""},
+		{"Unknown type", "Nope", "", nil, expectDecodedLocation, 1, nil, "unknown type Nope"},
+		{"Unknown format", "Location", "toml", nil, expectDecodedLocation, 1, nil, `unknown fixture format "toml"`},
+		{"Malformed fixture", "Location", "", nil, `{"Location": `, 1, nil, "unexpected EOF"},
+		{"Missing fixture", "Location", "", []string{filepath.Join(dir, "nonsuch.json")}, "", 1, nil, "no such file"},
+		{"Invalid value", "Location", "", []string{filepath.Join(dir, "invalid.json")}, "", 1, nil, "error encoding Location.Tags[1]: expected string"},
+		{"Failed validation", "Location", "", []string{filepath.Join(dir, "empty.json")}, "", 1, nil, "Location: Location should not be empty"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rs := returnedStatus{0}
+			failer = rs.fail
+
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			encodeCmd.SetOut(stdout)
+			encodeCmd.SetErr(stderr)
+			encodeCmd.SetIn(strings.NewReader(test.stdin))
+
+			defer func() {
+				encodeCmd.SetOut(nil)
+				encodeCmd.SetErr(nil)
+				encodeCmd.SetIn(nil)
+			}()
+
+			for flag, value := range map[string]string{
+				"schema": "testdata/valid-documents",
+				"type":   test.typ,
+				"format": test.format,
+			} {
+				err := encodeCmd.Flags().Set(flag, value)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			encodeCmd.Run(encodeCmd, test.args)
+
+			if test.expectStatus != rs.v {
+				t.Errorf("expected %d, received %d", test.expectStatus, rs.v)
+			}
+
+			if !bytes.Equal(test.expectOutput, stdout.Bytes()) {
+				t.Errorf("expected\n%v\nreceived\n%v", test.expectOutput, stdout.Bytes())
+			}
+
+			if !strings.Contains(stderr.String(), test.expectError) {
+				t.Errorf("expected %q in %q", test.expectError, stderr.String())
+			}
+		})
+	}
+}
+
+func TestEncodeCmd_Arg(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{"no args reads stdin", []string{}, false},
+		{"one arg is successful", []string{"fixture.json"}, false},
+		{"two many args errors", []string{"fixture.json", "another.json"}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := encodeCmd.Args(nil, test.args)
+			if err == nil && test.expectErr {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectErr {
+				t.Errorf("unexpected error %#v", err)
+			}
+		})
+	}
+}
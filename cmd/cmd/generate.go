@@ -61,13 +61,18 @@ generates valid go code for Marshalling/ Unmarshalling types.
 			failer(errInvalid)
 		}
 
-		gen, _ := generator.New(a, &generator.GeneratorOptions{
+		gen, err := generator.New(a, &generator.GeneratorOptions{
 			PackageName:             mustString(cmd.Flags().GetString("package")),
 			Directory:               mustString(cmd.Flags().GetString("dest")),
 			MakeDirectory:           mustBool(cmd.Flags().GetBool("mkdir")),
 			CustomFunctionSkeletons: mustBool(cmd.Flags().GetBool("functions")),
 			Clobber:                 mustBool(cmd.Flags().GetBool("clobber")),
+			DirectEncoding:          mustBool(cmd.Flags().GetBool("direct")),
 		})
+		if err != nil {
+			fmt.Printf("%v\n", err)
+			failer(errInvalid)
+		}
 
 		err = gen.Generate()
 		if err != nil {
@@ -109,5 +114,6 @@ func init() {
 	generateCmd.Flags().BoolP("mkdir", "m", true, "Create dest directory if not exist (note: if the destination directory exists then nothing happens)")
 	generateCmd.Flags().BoolP("functions", "f", false, "Create skeleton functions for any custom validators and/or transforms found in documents")
 	generateCmd.Flags().BoolP("clobber", "c", false, "Clobber any pre-generated validators and/or transforms (note: this replaces all custom code back to placeholders)")
+	generateCmd.Flags().BoolP("direct", "D", false, "Generate marshallers which read and write values directly, rather than boxing each in a scalar (note: the wire format is the same either way)")
 
 }
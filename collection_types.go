@@ -1,7 +1,7 @@
 package mint
 
 import (
-	"encoding/binary"
+	"fmt"
 	"io"
 )
 
@@ -28,12 +28,17 @@ func (s *SliceCollection) ReadSize(r io.Reader) (err error) {
 		return nil
 	}
 
-	return binary.Read(r, binary.LittleEndian, &s.len)
+	l, err := ReadSliceLen(r)
+	s.len = uint32(l)
+
+	return
 }
 
 func (s SliceCollection) Marshall(w io.Writer) (err error) {
 	if !s.fixedLength {
-		err = binary.Write(w, binary.LittleEndian, s.len)
+		err = writeAppended(w, func(b []byte) []byte {
+			return AppendUint32(b, s.len)
+		})
 		if err != nil {
 			return
 		}
@@ -49,12 +54,70 @@ func (s SliceCollection) Marshall(w io.Writer) (err error) {
 	return nil
 }
 
+func (s SliceCollection) AppendMarshall(b []byte) (_ []byte, err error) {
+	if !s.fixedLength {
+		b = AppendUint32(b, s.len)
+	}
+
+	for _, i := range s.V {
+		b, err = appendMarshall(b, i)
+		if err != nil {
+			return
+		}
+	}
+
+	return b, nil
+}
+
 func (s *SliceCollection) Unmarshall(r io.Reader) (err error) {
+	if int(s.len) > len(s.V) {
+		return fmt.Errorf("slice collection expects %d elements, but only has space for %d", s.len, len(s.V))
+	}
+
+	dr := AsDecodeReader(r)
+
+	err = dr.Enter()
+	if err != nil {
+		return
+	}
+
+	defer dr.Leave()
+
 	for i := uint32(0); i < s.len; i++ {
-		err = s.V[i].Unmarshall(r)
+		err = s.V[i].Unmarshall(dr)
 		if err != nil {
-			return
+			return WrapDecodeError(dr, "", IndexSegment(int(i)), err)
+		}
+	}
+
+	return
+}
+
+// UnmarshallElements reads Len elements from r, each into a new value
+// returned by elem, appending them to V as they're read. Unlike
+// Unmarshall, V needn't have room for every element beforehand, and so
+// a large count is only allocated for as its elements actually arrive
+func (s *SliceCollection) UnmarshallElements(r io.Reader, elem func() MarshallerUnmarshallerValuer) (err error) {
+	dr := AsDecodeReader(r)
+
+	err = dr.Enter()
+	if err != nil {
+		return
+	}
+
+	defer dr.Leave()
+
+	s.V = make([]MarshallerUnmarshallerValuer, 0, CollectionCap(int(s.len)))
+
+	for i := uint32(0); i < s.len; i++ {
+		v := elem()
+
+		err = v.Unmarshall(dr)
+		if err != nil {
+			return WrapDecodeError(dr, "", IndexSegment(int(i)), err)
 		}
+
+		s.V = append(s.V, v)
 	}
 
 	return
@@ -64,6 +127,18 @@ func (s SliceCollection) Value() any {
 	return s.V
 }
 
+func (s SliceCollection) Size() (n int) {
+	if !s.fixedLength {
+		n = CollectionHeaderSize
+	}
+
+	for _, i := range s.V {
+		n += sizeOf(i)
+	}
+
+	return
+}
+
 type MapCollection struct {
 	V   map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer
 	len uint32
@@ -80,14 +155,8 @@ func (s MapCollection) Len() int {
 }
 
 func (s *MapCollection) ReadSize(r io.Reader) (err error) {
-	var intermediate uint32 = 0
-
-	err = binary.Read(r, binary.LittleEndian, &intermediate)
-	if err != nil {
-		return
-	}
-
-	s.len = intermediate / 2
+	l, err := ReadMapLen(r)
+	s.len = uint32(l)
 
 	return
 }
@@ -96,14 +165,48 @@ func (s MapCollection) Marshall(w io.Writer) (err error) {
 	return NewSliceCollection(s.slice(), false).Marshall(w)
 }
 
+func (s MapCollection) AppendMarshall(b []byte) (_ []byte, err error) {
+	b = AppendUint32(b, uint32(len(s.V)*2))
+
+	for k, v := range s.V {
+		b, err = appendMarshall(b, k)
+		if err != nil {
+			return
+		}
+
+		b, err = appendMarshall(b, v)
+		if err != nil {
+			return
+		}
+	}
+
+	return b, nil
+}
+
 func (s *MapCollection) Unmarshall(r io.Reader) (err error) {
 	sl := s.slice()
 
-	err = NewSliceCollection(sl, false).Unmarshall(r)
+	dr := AsDecodeReader(r)
+
+	err = dr.Enter()
 	if err != nil {
 		return
 	}
 
+	defer dr.Leave()
+
+	for i := 0; i < len(sl); i += 2 {
+		err = sl[i].Unmarshall(dr)
+		if err != nil {
+			return WrapDecodeError(dr, "", IndexSegment(i/2), err)
+		}
+
+		err = sl[i+1].Unmarshall(dr)
+		if err != nil {
+			return WrapDecodeError(dr, "", KeySegment(sl[i].Value()), err)
+		}
+	}
+
 	s.V = make(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer)
 
 	for i := 0; i < len(sl); i += 2 {
@@ -114,10 +217,56 @@ func (s *MapCollection) Unmarshall(r io.Reader) (err error) {
 	return
 }
 
+// UnmarshallEntries reads Len entries from r, each into new values
+// returned by key and value, adding them to V as they're read, much as
+// SliceCollection.UnmarshallElements does
+func (s *MapCollection) UnmarshallEntries(r io.Reader, key, value func() MarshallerUnmarshallerValuer) (err error) {
+	dr := AsDecodeReader(r)
+
+	err = dr.Enter()
+	if err != nil {
+		return
+	}
+
+	defer dr.Leave()
+
+	s.V = make(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer, CollectionCap(int(s.len)))
+
+	for i := 0; i < int(s.len); i++ {
+		k := key()
+
+		err = k.Unmarshall(dr)
+		if err != nil {
+			return WrapDecodeError(dr, "", IndexSegment(i), err)
+		}
+
+		v := value()
+
+		err = v.Unmarshall(dr)
+		if err != nil {
+			return WrapDecodeError(dr, "", KeySegment(k.Value()), err)
+		}
+
+		s.V[k] = v
+	}
+
+	return
+}
+
 func (s MapCollection) Value() any {
 	return s.V
 }
 
+func (s MapCollection) Size() (n int) {
+	n = CollectionHeaderSize
+
+	for k, v := range s.V {
+		n += sizeOf(k) + sizeOf(v)
+	}
+
+	return
+}
+
 func (s MapCollection) slice() []MarshallerUnmarshallerValuer {
 	slice := make([]MarshallerUnmarshallerValuer, len(s.V)*2)
 
@@ -2,6 +2,7 @@ package mint
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -274,6 +275,72 @@ func TestMapCollection(t *testing.T) {
 	}
 }
 
+func TestCollections_Truncated(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		f    func(r io.Reader) error
+	}{
+		{"Slice size", func(r io.Reader) error { return NewSliceCollection(nil, false).ReadSize(r) }},
+		{"Map size", func(r io.Reader) error { return NewMapCollection(nil).ReadSize(r) }},
+		{"Slice elements", func(r io.Reader) error {
+			return NewSliceCollection([]MarshallerUnmarshallerValuer{NewBoolScalar(false)}, true).Unmarshall(r)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.f(new(bytes.Buffer))
+
+			var te ErrTruncated
+			if !errors.As(err, &te) {
+				t.Errorf("expected ErrTruncated, received %#v", err)
+			}
+		})
+	}
+}
+
+func TestCollections_ErrorPaths(t *testing.T) {
+	sl := new(bytes.Buffer)
+	_ = NewSliceCollection([]MarshallerUnmarshallerValuer{NewStringScalar("a"), NewStringScalar("b")}, true).Marshall(sl)
+
+	mp := new(bytes.Buffer)
+	_ = NewMapCollection(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer{NewStringScalar("a"): NewStringScalar("b")}).Marshall(mp)
+
+	for _, test := range []struct {
+		name       string
+		b          []byte
+		f          func(r io.Reader) error
+		expectPath string
+	}{
+		{"Slice element", sl.Bytes()[:sl.Len()-1], func(r io.Reader) error {
+			return NewSliceCollection([]MarshallerUnmarshallerValuer{NewStringScalar(""), NewStringScalar("")}, true).Unmarshall(r)
+		}, "[1]"},
+		{"Map key", mp.Bytes()[:6], func(r io.Reader) error {
+			m := NewMapCollection(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer{NewStringScalar(""): NewStringScalar("")})
+			_ = m.ReadSize(r)
+
+			return m.Unmarshall(r)
+		}, "[0]"},
+		{"Map value", mp.Bytes()[:mp.Len()-1], func(r io.Reader) error {
+			m := NewMapCollection(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer{NewStringScalar(""): NewStringScalar("")})
+			_ = m.ReadSize(r)
+
+			return m.Unmarshall(r)
+		}, `["a"]`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.f(bytes.NewReader(test.b))
+
+			var de ErrDecode
+			if !errors.As(err, &de) {
+				t.Fatalf("expected ErrDecode, received %#v", err)
+			}
+
+			if test.expectPath != de.Path() {
+				t.Errorf("expected %q, received %q", test.expectPath, de.Path())
+			}
+		})
+	}
+}
+
 func makeLongStringSlice() (out []string) {
 	out = make([]string, 100_000)
 	for i := range out {
@@ -292,3 +359,89 @@ func makeLongStringBoolMap() (out map[string]bool) {
 
 	return
 }
+
+func TestCollections_UnmarshallIncrementally(t *testing.T) {
+	newString := func() MarshallerUnmarshallerValuer { return NewStringScalar("") }
+
+	sl := new(bytes.Buffer)
+	_ = NewSliceCollection([]MarshallerUnmarshallerValuer{NewStringScalar("a"), NewStringScalar("b")}, false).Marshall(sl)
+
+	mp := new(bytes.Buffer)
+	_ = NewMapCollection(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer{NewStringScalar("a"): NewStringScalar("b")}).Marshall(mp)
+
+	t.Run("Slice", func(t *testing.T) {
+		r := bytes.NewReader(sl.Bytes())
+
+		s := NewSliceCollection(nil, false)
+		if err := s.ReadSize(r); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.UnmarshallElements(r, newString); err != nil {
+			t.Fatal(err)
+		}
+
+		expect := []any{"a", "b"}
+
+		received := make([]any, 0, len(s.V))
+		for _, v := range s.V {
+			received = append(received, v.Value())
+		}
+
+		if !reflect.DeepEqual(expect, received) {
+			t.Errorf("expected %#v, received %#v", expect, received)
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		r := bytes.NewReader(mp.Bytes())
+
+		m := NewMapCollection(nil)
+		if err := m.ReadSize(r); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := m.UnmarshallEntries(r, newString, newString); err != nil {
+			t.Fatal(err)
+		}
+
+		expect := map[any]any{"a": "b"}
+
+		received := make(map[any]any, len(m.V))
+		for k, v := range m.V {
+			received[k.Value()] = v.Value()
+		}
+
+		if !reflect.DeepEqual(expect, received) {
+			t.Errorf("expected %#v, received %#v", expect, received)
+		}
+	})
+
+	t.Run("Declared count larger than input", func(t *testing.T) {
+		b := AppendUint32(nil, 1<<20)
+
+		s := NewSliceCollection(nil, false)
+		r := NewDecodeReader(bytes.NewReader(b), &DefaultDecodeOptions)
+		if err := s.ReadSize(r); err != nil {
+			t.Fatal(err)
+		}
+
+		var te ErrTruncated
+		if err := s.UnmarshallElements(r, newString); !errors.As(err, &te) {
+			t.Errorf("expected ErrTruncated, received %#v", err)
+		}
+
+		if c := cap(s.V); c > CollectionCap(1<<20) {
+			t.Errorf("expected at most %d preallocated elements, received %d", CollectionCap(1<<20), c)
+		}
+	})
+
+	t.Run("BytesDecoder rejects counts beyond the remaining input", func(t *testing.T) {
+		d := NewBytesDecoder(AppendUint32(nil, 1<<20), &DefaultDecodeOptions)
+
+		var te ErrTruncated
+		if _, err := ReadSliceLen(d); !errors.As(err, &te) {
+			t.Errorf("expected ErrTruncated, received %#v", err)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package mint
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+)
+
+// Compression is the algorithm with which the body of an envelope
+// is compressed
+type Compression uint8
+
+const (
+	// NoCompression leaves the body of an envelope as is
+	NoCompression Compression = iota
+
+	// Flate compresses the body of an envelope with raw DEFLATE, as per
+	// compress/flate, which is the smaller of the two
+	Flate
+
+	// Gzip compresses the body of an envelope with gzip, as per
+	// compress/gzip, which may be read by other tools
+	Gzip
+)
+
+func (c Compression) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+
+	case Flate:
+		return "flate"
+
+	case Gzip:
+		return "gzip"
+	}
+
+	return "unknown"
+}
+
+// compress returns b compressed with c
+func compress(b []byte, c Compression) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	var (
+		w   io.WriteCloser
+		err error
+	)
+
+	switch c {
+	case Flate:
+		w, err = flate.NewWriter(buf, flate.DefaultCompression)
+
+	case Gzip:
+		w = gzip.NewWriter(buf)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(b)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompress returns b decompressed with c, refusing to inflate it to
+// more than max bytes, so that a small body can't claim gigabytes of
+// memory
+func decompress(b []byte, c Compression, max int64) ([]byte, error) {
+	var (
+		r   io.ReadCloser
+		err error
+	)
+
+	switch c {
+	case Flate:
+		r = flate.NewReader(bytes.NewReader(b))
+
+	case Gzip:
+		r, err = gzip.NewReader(bytes.NewReader(b))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	out, err := io.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(out)) > max {
+		return nil, ErrLimitExceeded{
+			Limit:    "decompressed bytes",
+			Max:      max,
+			Received: int64(len(out)),
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,342 @@
+package mint
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// DecodeOptions bounds the resources a decode may consume, allowing
+// untrusted input to be read without a single malicious length prefix
+// allocating gigabytes of memory.
+//
+// Any field left as its zero value falls back to the corresponding
+// value in DefaultDecodeOptions
+type DecodeOptions struct {
+	// MaxStringBytes is the longest string, in bytes, which may be read
+	MaxStringBytes int64
+
+	// MaxCollectionElements is the largest number of elements a slice,
+	// or entries a map, may declare
+	MaxCollectionElements uint32
+
+	// MaxTotalBytes is the most bytes which may be read from the
+	// underlying reader. By default this is unbounded
+	MaxTotalBytes int64
+
+	// MaxDepth is the deepest that types and collections may be nested
+	MaxDepth int
+
+	// MaxFrameBytes is the largest frame, in bytes, a FrameReader may
+	// read; the whole of a frame is held in memory while it's decoded
+	MaxFrameBytes int64
+}
+
+// DefaultDecodeOptions are the limits applied to any reader which isn't
+// a DecodeReader, and which fill in any unset DecodeOptions
+var DefaultDecodeOptions = DecodeOptions{
+	MaxStringBytes:        64 << 20,
+	MaxCollectionElements: 4 << 20,
+	MaxTotalBytes:         0,
+	MaxDepth:              64,
+	MaxFrameBytes:         16 << 20,
+}
+
+// DecodeReader wraps an io.Reader, enforcing DecodeOptions across
+// everything read through it.
+//
+// Generated Unmarshall functions wrap whichever reader they're passed
+// in a DecodeReader (unless it already is one), and so callers wishing
+// to set their own limits need only pass one in
+type DecodeReader struct {
+	r      io.Reader
+	opts   DecodeOptions
+	offset int64
+	depth  int
+
+	// base is the offset from which MaxTotalBytes is counted, allowing
+	// a Decoder to apply limits to each value in a stream
+	base int64
+
+	// ends holds the offset at which each evolvable type currently
+	// being decoded finishes, innermost last; see EnterBody
+	ends []int64
+
+	// scratch holds scalars as they're read, sized for the largest
+	// (a uuid), saving an allocation per read
+	scratch [16]byte
+}
+
+// NewDecodeReader returns a DecodeReader over r, enforcing the limits in o.
+// A nil o uses DefaultDecodeOptions
+func NewDecodeReader(r io.Reader, o *DecodeOptions) *DecodeReader {
+	opts := DefaultDecodeOptions
+	if o != nil {
+		opts = o.withDefaults()
+	}
+
+	return &DecodeReader{
+		r:    r,
+		opts: opts,
+	}
+}
+
+// AsDecodeReader returns r if it is already a DecodeReader, or else
+// wraps it in one with DefaultDecodeOptions.
+//
+// A BytesDecoder is wrapped with its own options, offset, and depth,
+// so that limits and error offsets carry over
+func AsDecodeReader(r io.Reader) *DecodeReader {
+	switch dr := r.(type) {
+	case *DecodeReader:
+		return dr
+
+	case *BytesDecoder:
+		return &DecodeReader{
+			r:      r,
+			opts:   dr.opts,
+			offset: dr.Offset(),
+			depth:  dr.depth,
+		}
+	}
+
+	return NewDecodeReader(r, nil)
+}
+
+// Read implements io.Reader, refusing to read past MaxTotalBytes, or
+// past the end of the body of the evolvable type being decoded
+func (d *DecodeReader) Read(p []byte) (n int, err error) {
+	if d.opts.MaxTotalBytes > 0 {
+		remaining := d.opts.MaxTotalBytes - (d.offset - d.base)
+		if remaining <= 0 && len(p) > 0 {
+			return 0, ErrLimitExceeded{
+				Limit:    "total bytes",
+				Max:      d.opts.MaxTotalBytes,
+				Received: d.offset - d.base + int64(len(p)),
+			}
+		}
+
+		if int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+
+	if len(d.ends) > 0 {
+		remaining := d.ends[len(d.ends)-1] - d.offset
+		if remaining <= 0 && len(p) > 0 {
+			return 0, io.EOF
+		}
+
+		if int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+
+	n, err = d.r.Read(p)
+	d.offset += int64(n)
+
+	return
+}
+
+// Offset returns the number of bytes read so far
+func (d *DecodeReader) Offset() int64 {
+	return d.offset
+}
+
+// Enter records that decoding has descended into a type or collection,
+// returning an error should this breach MaxDepth. Every successful call
+// to Enter must be paired with a call to Leave
+func (d *DecodeReader) Enter() error {
+	if d.depth >= d.opts.MaxDepth {
+		return ErrLimitExceeded{
+			Limit:    "depth",
+			Max:      int64(d.opts.MaxDepth),
+			Received: int64(d.depth + 1),
+		}
+	}
+
+	d.depth++
+
+	return nil
+}
+
+// Leave records that decoding has finished with a type or collection
+func (d *DecodeReader) Leave() {
+	if d.depth > 0 {
+		d.depth--
+	}
+}
+
+func (o DecodeOptions) withDefaults() DecodeOptions {
+	if o.MaxStringBytes == 0 {
+		o.MaxStringBytes = DefaultDecodeOptions.MaxStringBytes
+	}
+
+	if o.MaxCollectionElements == 0 {
+		o.MaxCollectionElements = DefaultDecodeOptions.MaxCollectionElements
+	}
+
+	if o.MaxTotalBytes == 0 {
+		o.MaxTotalBytes = DefaultDecodeOptions.MaxTotalBytes
+	}
+
+	if o.MaxDepth == 0 {
+		o.MaxDepth = DefaultDecodeOptions.MaxDepth
+	}
+
+	if o.MaxFrameBytes == 0 {
+		o.MaxFrameBytes = DefaultDecodeOptions.MaxFrameBytes
+	}
+
+	return o
+}
+
+// offsetter is implemented by readers which track how far through
+// their input they are, such as DecodeReader and BytesDecoder
+type offsetter interface {
+	Offset() int64
+}
+
+// decodeOptions returns the limits in force for r
+func decodeOptions(r io.Reader) DecodeOptions {
+	switch dr := r.(type) {
+	case *DecodeReader:
+		return dr.opts
+
+	case *BytesDecoder:
+		return dr.opts
+	}
+
+	return DefaultDecodeOptions
+}
+
+// checkStringLength ensures a string length prefix read from r is sane
+// before anything is allocated for it
+func checkStringLength(r io.Reader, l int64) error {
+	limit := decodeOptions(r).MaxStringBytes
+	if l < 0 || l > limit {
+		return ErrLimitExceeded{
+			Limit:    "string bytes",
+			Max:      limit,
+			Received: l,
+		}
+	}
+
+	return nil
+}
+
+// checkCollectionLength ensures an element count read from r is sane
+// before anything is allocated for it
+func checkCollectionLength(r io.Reader, l uint32) error {
+	limit := decodeOptions(r).MaxCollectionElements
+	if l > limit {
+		return ErrLimitExceeded{
+			Limit:    "collection elements",
+			Max:      int64(limit),
+			Received: int64(l),
+		}
+	}
+
+	return nil
+}
+
+// collectionChunkElements is the most elements of a collection allocated
+// ahead of actually being read; larger collections grow as elements
+// arrive, so that a count prefix alone can't force a large allocation,
+// however large each element may be
+const collectionChunkElements = 1 << 10
+
+// CollectionCap returns the capacity to allocate for a collection which
+// declares l elements, before any of them have been read. Collections
+// are then grown with append, as readString does for long strings
+func CollectionCap(l int) int {
+	return min(l, collectionChunkElements)
+}
+
+// stringChunkSize is the most bytes of a string allocated ahead of
+// actually being read; longer strings grow as data arrives, so that
+// a length prefix alone can't force a large allocation
+const stringChunkSize = 64 << 10
+
+// readFull reads exactly len(b) bytes from r, returning ErrTruncated
+// should r run out of data first
+func readFull(r io.Reader, b []byte) error {
+	n, err := io.ReadFull(r, b)
+
+	return truncatedErr(r, err, int64(n), int64(len(b)))
+}
+
+// readScratch reads exactly n bytes from r, returning them in a buffer
+// which is only valid until the next read from r.
+//
+// Where r is a BytesDecoder this returns a window onto its input, and
+// where r is a DecodeReader this uses its scratch space; in either case
+// nothing is allocated
+func readScratch(r io.Reader, n int) (b []byte, err error) {
+	switch dr := r.(type) {
+	case *BytesDecoder:
+		return dr.next(int64(n))
+
+	case *DecodeReader:
+		if n <= len(dr.scratch) {
+			b = dr.scratch[:n]
+		}
+	}
+
+	if b == nil {
+		b = make([]byte, n)
+	}
+
+	err = readFull(r, b)
+
+	return
+}
+
+// readString reads a string of l bytes from r
+func readString(r io.Reader, l int64) (string, error) {
+	if l <= stringChunkSize {
+		b := make([]byte, l)
+
+		err := readFull(r, b)
+		if err != nil {
+			return "", err
+		}
+
+		return string(b), nil
+	}
+
+	sb := strings.Builder{}
+
+	n, err := io.CopyN(&sb, r, l)
+	if err != nil {
+		// io.CopyN reports any shortfall as io.EOF, whereas
+		// io.ReadFull distinguishes between no, and some, data
+		if errors.Is(err, io.EOF) && n > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return "", truncatedErr(r, err, n, l)
+	}
+
+	return sb.String(), nil
+}
+
+// truncatedErr converts the errors io functions return when running
+// out of data into ErrTruncated, passing any other error through as is
+func truncatedErr(r io.Reader, err error, read, expected int64) error {
+	if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+
+	offset := read
+	if o, ok := r.(offsetter); ok {
+		offset = o.Offset()
+	}
+
+	return ErrTruncated{
+		Offset:   offset,
+		Read:     read,
+		Expected: expected,
+		err:      err,
+	}
+}
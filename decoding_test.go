@@ -0,0 +1,117 @@
+package mint
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDecodeReader_Limits(t *testing.T) {
+	str := new(bytes.Buffer)
+	_ = NewStringScalar("Hello, World!").Marshall(str)
+
+	sl := new(bytes.Buffer)
+	_ = NewSliceCollection([]MarshallerUnmarshallerValuer{NewBoolScalar(true), NewBoolScalar(false)}, false).Marshall(sl)
+
+	mp := new(bytes.Buffer)
+	_ = NewMapCollection(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer{
+		NewStringScalar("a"): NewBoolScalar(true),
+		NewStringScalar("b"): NewBoolScalar(true),
+	}).Marshall(mp)
+
+	for _, test := range []struct {
+		name        string
+		b           []byte
+		opts        *DecodeOptions
+		f           func(*DecodeReader) error
+		expectLimit string
+	}{
+		{"String within limit succeeds", str.Bytes(), &DecodeOptions{MaxStringBytes: 13}, unmarshallString, ""},
+		{"String over limit errors", str.Bytes(), &DecodeOptions{MaxStringBytes: 12}, unmarshallString, "string bytes"},
+		{"Negative string length errors", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, nil, unmarshallString, "string bytes"},
+		{"Huge string length errors", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}, nil, unmarshallString, "string bytes"},
+		{"Slice within limit succeeds", sl.Bytes(), &DecodeOptions{MaxCollectionElements: 2}, readSliceSize, ""},
+		{"Slice over limit errors", sl.Bytes(), &DecodeOptions{MaxCollectionElements: 1}, readSliceSize, "collection elements"},
+		{"Huge slice length errors", []byte{0xff, 0xff, 0xff, 0xff}, nil, readSliceSize, "collection elements"},
+		{"Map within limit succeeds", mp.Bytes(), &DecodeOptions{MaxCollectionElements: 2}, readMapSize, ""},
+		{"Map over limit errors", mp.Bytes(), &DecodeOptions{MaxCollectionElements: 1}, readMapSize, "collection elements"},
+		{"Total bytes within limit succeeds", str.Bytes(), &DecodeOptions{MaxTotalBytes: int64(str.Len())}, unmarshallString, ""},
+		{"Total bytes over limit errors", str.Bytes(), &DecodeOptions{MaxTotalBytes: 6}, unmarshallString, "total bytes"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.f(NewDecodeReader(bytes.NewReader(test.b), test.opts))
+
+			var le ErrLimitExceeded
+
+			switch {
+			case test.expectLimit == "" && err != nil:
+				t.Errorf("unexpected error %#v", err)
+
+			case test.expectLimit != "" && !errors.As(err, &le):
+				t.Errorf("expected ErrLimitExceeded, received %#v", err)
+
+			case test.expectLimit != "" && le.Limit != test.expectLimit:
+				t.Errorf("expected %q limit, received %q", test.expectLimit, le.Limit)
+			}
+		})
+	}
+}
+
+func TestDecodeReader_Depth(t *testing.T) {
+	dr := NewDecodeReader(new(bytes.Buffer), &DecodeOptions{MaxDepth: 2})
+
+	for i := 0; i < 2; i++ {
+		err := dr.Enter()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+	}
+
+	err := dr.Enter()
+	if err == nil {
+		t.Fatalf("expected error, received none")
+	}
+
+	dr.Leave()
+
+	err = dr.Enter()
+	if err != nil {
+		t.Errorf("unexpected error %#v", err)
+	}
+}
+
+func TestDecodeReader_Nested(t *testing.T) {
+	b := new(bytes.Buffer)
+	_ = NewSliceCollection([]MarshallerUnmarshallerValuer{NewBoolScalar(true)}, true).Marshall(b)
+
+	dr := NewDecodeReader(b, &DecodeOptions{MaxDepth: 1})
+	_ = dr.Enter()
+
+	err := NewSliceCollection([]MarshallerUnmarshallerValuer{NewBoolScalar(false)}, true).Unmarshall(dr)
+	if err == nil {
+		t.Errorf("expected error, received none")
+	}
+}
+
+func TestAsDecodeReader(t *testing.T) {
+	dr := NewDecodeReader(new(bytes.Buffer), nil)
+	if AsDecodeReader(dr) != dr {
+		t.Errorf("expected existing DecodeReader to be reused")
+	}
+
+	if AsDecodeReader(new(bytes.Buffer)).opts != DefaultDecodeOptions {
+		t.Errorf("expected default options")
+	}
+}
+
+func unmarshallString(dr *DecodeReader) error {
+	return NewStringScalar("").Unmarshall(dr)
+}
+
+func readSliceSize(dr *DecodeReader) error {
+	return NewSliceCollection(nil, false).ReadSize(dr)
+}
+
+func readMapSize(dr *DecodeReader) error {
+	return NewMapCollection(nil).ReadSize(dr)
+}
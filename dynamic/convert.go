@@ -0,0 +1,383 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/vinyl-linux/mint"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// Convert converts v, a value decoded from JSON or YAML into an any,
+// into a Message of type t, checking the type of each value as it goes.
+//
+// Values are expected as printed by mint decode; datetimes as RFC3339
+// strings, uuids as strings, enums by name, unions as an object holding
+// just the variant which is set, and map keys as strings. Numbers may be
+// any go numeric type, or a json.Number, so long as they fit the type
+// they're converted to.
+//
+// Fields which are null are left out of the returned Message, and so are
+// marshalled as absent, or with their default value. Errors are returned
+// as an ErrEncode, recording the path to the offending value
+func (t *Type) Convert(v any) (Message, error) {
+	return t.s.convertMessage(t.at, v)
+}
+
+// convertMessage converts v, an object, into a Message of type at
+func (s schema) convertMessage(at parser.AnnotatedType, v any) (Message, error) {
+	var fields map[string]any
+
+	switch v := v.(type) {
+	case map[string]any:
+		fields = v
+
+	case Message:
+		fields = v
+
+	default:
+		return nil, wrapEncodeError(at.Name, "", ErrInvalidValue{Expected: at.Name, Received: v})
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	m := make(Message, len(fields))
+	for _, name := range names {
+		e, ok := entry(at, name)
+		if !ok {
+			return nil, wrapEncodeError(at.Name, name, ErrUnknownField{Field: name})
+		}
+
+		if fields[name] == nil {
+			continue
+		}
+
+		fv, err := s.convertValue(e.DataType, fields[name])
+		if err != nil {
+			return nil, wrapEncodeError(at.Name, name, err)
+		}
+
+		m[name] = fv
+	}
+
+	return m, nil
+}
+
+// convertValue converts v into a value of data type dt
+func (s schema) convertValue(dt *parser.DataType, v any) (any, error) {
+	switch {
+	case dt.Scalar != nil:
+		return s.convertScalar(dt.Scalar.Type, v)
+
+	case dt.Map != nil:
+		return s.convertMap(dt, v)
+	}
+
+	return s.convertSlice(dt, v)
+}
+
+// convertSlice converts v, a list, into a slice or array of data type dt
+func (s schema) convertSlice(dt *parser.DataType, v any) (any, error) {
+	elems, ok := v.([]any)
+	if !ok {
+		return nil, ErrInvalidValue{Expected: dt.String(), Received: v}
+	}
+
+	if dt.FixedSizeSlice != nil && len(elems) != dt.FixedSizeSlice.Size {
+		return nil, ErrInvalidLength{Expected: dt.FixedSizeSlice.Size, Received: len(elems)}
+	}
+
+	out := make([]any, len(elems))
+	for i, e := range elems {
+		ev, err := s.convertValue(dt.Elem(), e)
+		if err != nil {
+			return nil, wrapEncodeError("", mint.IndexSegment(i), err)
+		}
+
+		out[i] = ev
+	}
+
+	return out, nil
+}
+
+// convertMap converts v, an object, into a map of data type dt, parsing
+// each key from the string it's written as.
+//
+// Keys are converted in order, so that the same input always returns
+// the same error
+func (s schema) convertMap(dt *parser.DataType, v any) (any, error) {
+	var entries map[any]any
+
+	switch v := v.(type) {
+	case map[string]any:
+		entries = make(map[any]any, len(v))
+		for k, mv := range v {
+			entries[k] = mv
+		}
+
+	case map[any]any:
+		entries = v
+
+	default:
+		return nil, ErrInvalidValue{Expected: dt.String(), Received: v}
+	}
+
+	out := make(map[any]any, len(entries))
+	for _, k := range sortedKeys(entries) {
+		seg := mint.KeySegment(k)
+
+		key, err := s.convertKey(dt.Map.Key, k)
+		if err != nil {
+			return nil, wrapEncodeError("", seg, err)
+		}
+
+		mv, err := s.convertValue(dt.Map.Value, entries[k])
+		if err != nil {
+			return nil, wrapEncodeError("", seg, err)
+		}
+
+		out[key] = mv
+	}
+
+	return out, nil
+}
+
+// convertKey converts k, the key of a map, into a value of the scalar or
+// enum called t; keys are generally strings, and so numbers and bools
+// are parsed from them
+func (s schema) convertKey(t string, k any) (any, error) {
+	ks, ok := k.(string)
+	if !ok {
+		return s.convertScalar(t, k)
+	}
+
+	switch t {
+	case "bool":
+		b, err := strconv.ParseBool(ks)
+		if err != nil {
+			return nil, ErrInvalidValue{Expected: t, Received: k}
+		}
+
+		return b, nil
+
+	case "int16", "int32", "int64", "float32", "float64", "byte":
+		return s.convertScalar(t, json.Number(ks))
+	}
+
+	return s.convertScalar(t, ks)
+}
+
+// convertScalar converts v into a value of the builtin scalar, type,
+// enum, or union called t
+func (s schema) convertScalar(t string, v any) (any, error) {
+	if _, ok := builtins[t]; ok {
+		return convertBuiltin(t, v)
+	}
+
+	d, err := s.lookup(t)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case d.enum != nil:
+		return convertEnum(*d.enum, v)
+
+	case d.union != nil:
+		return d.s.convertUnion(*d.union, v)
+	}
+
+	return d.s.convertMessage(*d.typ, v)
+}
+
+// convertEnum converts v, the name of a value of enum e, returning it
+// where e has such a value
+func convertEnum(e parser.Enum, v any) (any, error) {
+	name, _ := v.(string)
+
+	for _, ev := range e.Values {
+		if ev.Value.Key == name {
+			return name, nil
+		}
+	}
+
+	return nil, ErrInvalidValue{Expected: e.Name, Received: v}
+}
+
+// convertUnion converts v, an object holding exactly one variant of
+// union u, into a Union
+func (s schema) convertUnion(u parser.Union, v any) (any, error) {
+	obj, ok := v.(map[string]any)
+	if !ok || len(obj) != 1 {
+		return nil, ErrInvalidValue{Expected: u.Name, Received: v}
+	}
+
+	var name string
+	for k := range obj {
+		name = k
+	}
+
+	for _, variant := range u.Variants {
+		if variant.Name != name {
+			continue
+		}
+
+		cv, err := s.convertScalar(variant.Type, obj[name])
+		if err != nil {
+			return nil, wrapEncodeError(u.Name, variant.Name, err)
+		}
+
+		return Union{Variant: name, Value: cv}, nil
+	}
+
+	return nil, ErrUnknownVariant{Union: u.Name, Variant: name}
+}
+
+// convertBuiltin converts v into a value of the builtin scalar t
+func convertBuiltin(t string, v any) (any, error) {
+	invalid := ErrInvalidValue{Expected: t, Received: v}
+
+	switch t {
+	case "string":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+
+	case "datetime":
+		switch v := v.(type) {
+		case time.Time:
+			return v, nil
+
+		case string:
+			d, err := time.Parse(time.RFC3339Nano, v)
+			if err == nil {
+				return d, nil
+			}
+
+			invalid.Expected = "RFC3339 datetime"
+		}
+
+	case "uuid":
+		switch v := v.(type) {
+		case uuid.UUID:
+			return v, nil
+
+		case string:
+			u, err := uuid.FromString(v)
+			if err == nil {
+				return u, nil
+			}
+		}
+
+	case "bool":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+
+	case "int16":
+		if i, ok := toInteger(v, math.MinInt16, math.MaxInt16); ok {
+			return int16(i), nil
+		}
+
+	case "int32":
+		if i, ok := toInteger(v, math.MinInt32, math.MaxInt32); ok {
+			return int32(i), nil
+		}
+
+	case "int64":
+		if i, ok := toInteger(v, math.MinInt64, math.MaxInt64); ok {
+			return i, nil
+		}
+
+	case "byte":
+		if i, ok := toInteger(v, 0, math.MaxUint8); ok {
+			return byte(i), nil
+		}
+
+	case "float32":
+		if f, ok := toFloat(v); ok && math.Abs(f) <= math.MaxFloat32 {
+			return float32(f), nil
+		}
+
+	case "float64":
+		if f, ok := toFloat(v); ok {
+			return f, nil
+		}
+	}
+
+	return nil, invalid
+}
+
+// toInteger returns v as an int64, where v is a whole number between min
+// and max inclusive
+func toInteger(v any, min, max int64) (i int64, ok bool) {
+	switch v := v.(type) {
+	case json.Number:
+		var err error
+
+		i, err = v.Int64()
+		ok = err == nil
+
+	case int:
+		i, ok = int64(v), true
+
+	case int64:
+		i, ok = v, true
+
+	case uint64:
+		i, ok = int64(v), v <= math.MaxInt64
+
+	case float64:
+		i, ok = int64(v), v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64
+	}
+
+	return i, ok && i >= min && i <= max
+}
+
+// toFloat returns v as a float64, where v is a number
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+
+		return f, err == nil
+
+	case int:
+		return float64(v), true
+
+	case int64:
+		return float64(v), true
+
+	case uint64:
+		return float64(v), true
+
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+// sortedKeys returns the keys of m, sorted by the path segments which
+// describe them, so that maps are walked in the same order each time
+func sortedKeys(m map[any]any) []any {
+	keys := make([]any, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return mint.KeySegment(keys[i]) < mint.KeySegment(keys[j])
+	})
+
+	return keys
+}
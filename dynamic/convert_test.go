@@ -0,0 +1,142 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestType_Convert(t *testing.T) {
+	typ := mustNew(t, mustParse(t, sampleDocument), "Sample")
+
+	d := json.NewDecoder(strings.NewReader(`{
+  "Name": "jo",
+  "Count": 5,
+  "Level": "Low",
+  "Origin": null,
+  "Grid": [[1, 2], []],
+  "Levels": {"a": "Low", "b": "High"},
+  "Shape": {"Point": {"X": 1.5, "Y": -2}},
+  "ID": "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+  "At": "2024-06-01T12:00:00Z",
+  "Enabled": true,
+  "Flags": 255,
+  "Big": -9007199254740993,
+  "Ratio": 0.5
+}`))
+	d.UseNumber()
+
+	var in any
+
+	err := d.Decode(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received, err := typ.Convert(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Message{
+		"Name":  "jo",
+		"Count": int32(5),
+		"Level": "Low",
+		"Grid": []any{
+			[]any{int16(1), int16(2)},
+			[]any{},
+		},
+		"Levels": map[any]any{
+			"a": "Low",
+			"b": "High",
+		},
+		"Shape":   Union{Variant: "Point", Value: Message{"X": 1.5, "Y": float64(-2)}},
+		"ID":      uuid.Must(uuid.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")),
+		"At":      time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		"Enabled": true,
+		"Flags":   byte(255),
+		"Big":     int64(-9007199254740993),
+		"Ratio":   float32(0.5),
+	}
+
+	if !reflect.DeepEqual(expect, received) {
+		t.Errorf("expected\n%#v\nreceived\n%#v", expect, received)
+	}
+}
+
+func TestType_Convert_Keys(t *testing.T) {
+	typ := mustNew(t, mustParse(t, `
+type Keyed {
+    map<int32, string> Ints = 0;
+    map<bool, string> Bools = 1;
+    map<uuid, string> Uuids = 2;
+}
+`), "Keyed")
+
+	received, err := typ.Convert(map[string]any{
+		"Ints":  map[any]any{3: "three", "-4": "minus four"},
+		"Bools": map[string]any{"true": "yes"},
+		"Uuids": map[string]any{"6ba7b810-9dad-11d1-80b4-00c04fd430c8": "ns"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Message{
+		"Ints":  map[any]any{int32(3): "three", int32(-4): "minus four"},
+		"Bools": map[any]any{true: "yes"},
+		"Uuids": map[any]any{uuid.Must(uuid.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")): "ns"},
+	}
+
+	if !reflect.DeepEqual(expect, received) {
+		t.Errorf("expected\n%#v\nreceived\n%#v", expect, received)
+	}
+}
+
+func TestType_Convert_Errors(t *testing.T) {
+	typ := mustNew(t, mustParse(t, sampleDocument), "Sample")
+
+	for _, test := range []struct {
+		name        string
+		in          any
+		expectPath  string
+		expectError error
+	}{
+		{"Not an object", []any{}, "Sample", ErrInvalidValue{Expected: "Sample", Received: []any{}}},
+		{"Unknown field", map[string]any{"Bogus": 1}, "Sample.Bogus", ErrUnknownField{Field: "Bogus"}},
+		{"String as number", map[string]any{"Count": "5"}, "Sample.Count", ErrInvalidValue{Expected: "int32", Received: "5"}},
+		{"Fractional integer", map[string]any{"Count": 1.5}, "Sample.Count", ErrInvalidValue{Expected: "int32", Received: 1.5}},
+		{"Integer out of range", map[string]any{"Flags": json.Number("256")}, "Sample.Flags", ErrInvalidValue{Expected: "byte", Received: json.Number("256")}},
+		{"Float out of range", map[string]any{"Ratio": 1e39}, "Sample.Ratio", ErrInvalidValue{Expected: "float32", Received: 1e39}},
+		{"Bad datetime", map[string]any{"At": "yesterday"}, "Sample.At", ErrInvalidValue{Expected: "RFC3339 datetime", Received: "yesterday"}},
+		{"Bad uuid", map[string]any{"ID": "nope"}, "Sample.ID", ErrInvalidValue{Expected: "uuid", Received: "nope"}},
+		{"Unknown enum value", map[string]any{"Level": "Medium"}, "Sample.Level", ErrInvalidValue{Expected: "Level", Received: "Medium"}},
+		{"Wrong array length", map[string]any{"Grid": []any{}}, "Sample.Grid", ErrInvalidLength{Expected: 2, Received: 0}},
+		{"Bad element", map[string]any{"Grid": []any{[]any{}, []any{1, true}}}, "Sample.Grid[1][1]", ErrInvalidValue{Expected: "int16", Received: true}},
+		{"Bad map value", map[string]any{"Levels": map[string]any{"a": 1}}, `Sample.Levels["a"]`, ErrInvalidValue{Expected: "Level", Received: 1}},
+		{"Union with two variants", map[string]any{"Shape": map[string]any{"Label": "x", "Point": nil}}, "Sample.Shape", ErrInvalidValue{Expected: "Shape", Received: map[string]any{"Label": "x", "Point": nil}}},
+		{"Unknown variant", map[string]any{"Shape": map[string]any{"Circle": 1}}, "Sample.Shape", ErrUnknownVariant{Union: "Shape", Variant: "Circle"}},
+		{"Bad nested field", map[string]any{"Shape": map[string]any{"Point": map[string]any{"X": "1"}}}, "Sample.Shape.Point.X", ErrInvalidValue{Expected: "float64", Received: "1"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := typ.Convert(test.in)
+
+			ee, ok := err.(ErrEncode)
+			if !ok {
+				t.Fatalf("expected ErrEncode, received %#v", err)
+			}
+
+			if ee.Path() != test.expectPath {
+				t.Errorf("expected path %q, received %q", test.expectPath, ee.Path())
+			}
+
+			if !reflect.DeepEqual(test.expectError, ee.Err) {
+				t.Errorf("expected %#v, received %#v", test.expectError, ee.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package dynamic
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// newMessage returns a Message of type at with each field set to its
+// default, or its zero value where it has none, with optional fields
+// left absent, as the generated New<Type> function would
+func (s schema) newMessage(at parser.AnnotatedType) (Message, error) {
+	m := make(Message)
+
+	for _, e := range at.Entries {
+		if e.Optional {
+			continue
+		}
+
+		v, err := s.fieldDefault(e)
+		if err != nil {
+			return nil, err
+		}
+
+		m[e.Name] = v
+	}
+
+	return m, nil
+}
+
+// fieldDefault returns the default value of field e, or its zero value
+// where it has no default
+func (s schema) fieldDefault(e parser.AnnotatedEntry) (any, error) {
+	if e.Default == nil {
+		return s.zero(e.DataType)
+	}
+
+	return defaultValue(e.DataType.Scalar.Type, *e.Default)
+}
+
+// zero returns the zero value of data type dt; types are set as per
+// newMessage, and arrays filled with the zero value of their elements
+func (s schema) zero(dt *parser.DataType) (any, error) {
+	switch {
+	case dt.Slice != nil:
+		return []any{}, nil
+
+	case dt.Map != nil:
+		return map[any]any{}, nil
+
+	case dt.FixedSizeSlice != nil:
+		out := make([]any, dt.FixedSizeSlice.Size)
+		for i := range out {
+			v, err := s.zero(dt.FixedSizeSlice.Type)
+			if err != nil {
+				return nil, err
+			}
+
+			out[i] = v
+		}
+
+		return out, nil
+	}
+
+	t := dt.Scalar.Type
+	if _, ok := builtins[t]; ok {
+		return newScalar(t).Value(), nil
+	}
+
+	d, err := s.lookup(t)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case d.enum != nil:
+		return "", nil
+
+	case d.union != nil:
+		return Union{}, nil
+	}
+
+	return d.s.newMessage(*d.typ)
+}
+
+// defaultValue converts v, the default of a field of the builtin scalar
+// or enum called t, to the go type t is represented as; defaults have
+// already been checked by the parser, and so are known to be valid
+func defaultValue(t string, v parser.Value) (any, error) {
+	switch t {
+	case "string":
+		return *v.Quoted, nil
+
+	case "datetime":
+		d, err := time.Parse(time.RFC3339, *v.Quoted)
+
+		return d.UTC(), err
+
+	case "uuid":
+		return uuid.FromString(*v.Quoted)
+
+	case "bool":
+		return *v.Ident == "true", nil
+
+	case "float32":
+		f, err := strconv.ParseFloat(*v.Number, 32)
+
+		return float32(f), err
+
+	case "float64":
+		return strconv.ParseFloat(*v.Number, 64)
+
+	case "int16":
+		i, err := strconv.ParseInt(*v.Number, 10, 16)
+
+		return int16(i), err
+
+	case "int32":
+		i, err := strconv.ParseInt(*v.Number, 10, 32)
+
+		return int32(i), err
+
+	case "int64":
+		return strconv.ParseInt(*v.Number, 10, 64)
+
+	case "byte":
+		i, err := strconv.ParseUint(*v.Number, 10, 8)
+
+		return byte(i), err
+	}
+
+	// Anything else is an enum, the default of which is the name of
+	// one of its values
+	return *v.Ident, nil
+}
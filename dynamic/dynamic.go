@@ -0,0 +1,106 @@
+// Package dynamic marshalls and unmarshalls mint data for schemas which
+// are only known at runtime, such as those loaded by a log viewer or a
+// proxy, without generating any code.
+//
+// Values are represented with the following go types:
+//
+//	| Mint              | Go                                   |
+//	|-------------------|--------------------------------------|
+//	| string            | string                               |
+//	| datetime          | time.Time                            |
+//	| uuid              | uuid.UUID                            |
+//	| int16, int32, ... | int16, int32, ...                    |
+//	| byte              | byte                                 |
+//	| bool              | bool                                 |
+//	| enums             | string, holding the name of a value  |
+//	| types             | Message                              |
+//	| unions            | Union                                |
+//	| slices and arrays | []any                                |
+//	| maps              | map[any]any                          |
+//
+// Optional fields which are absent are left out of a Message entirely.
+package dynamic
+
+import (
+	"io"
+
+	"github.com/vinyl-linux/mint"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// Message is a value of a user defined type, holding the value of
+// each field, keyed by the field's name
+type Message map[string]any
+
+// Union is a value of a union, holding the name of the variant which is
+// set, along with that variant's value
+type Union struct {
+	Variant string
+	Value   any
+}
+
+// Type is a user defined type, from a set of parsed documents, which
+// values may be marshalled as, and unmarshalled from
+type Type struct {
+	s  schema
+	at parser.AnnotatedType
+}
+
+// New returns the Type called name from ast, which may be qualified by the
+// name of a package ast imports, such as geo.Location, returning
+// ErrUnknownType where there is no such type
+func New(ast *parser.AST, name string) (*Type, error) {
+	s := schema{ast: ast}
+
+	d, err := s.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.typ == nil {
+		return nil, ErrUnknownType{Name: name}
+	}
+
+	return &Type{
+		s:  d.s,
+		at: *d.typ,
+	}, nil
+}
+
+// Name returns the name of t, as declared
+func (t *Type) Name() string {
+	return t.at.Name
+}
+
+// AnnotatedType returns the definition of t
+func (t *Type) AnnotatedType() parser.AnnotatedType {
+	return t.at
+}
+
+// New returns a Message of type t with each field set as the generated
+// New<Type> function would; to its default value, or to its zero value
+// where it has none, with optional fields left absent
+func (t *Type) New() (Message, error) {
+	return t.s.newMessage(t.at)
+}
+
+// Marshall writes the mint encoding of m, a value of type t, to w,
+// producing the same bytes as the generated Marshall function would.
+//
+// Fields missing from m are written with their default value, or their
+// zero value where they have none, while absent optional fields are
+// left out. Values which don't match the types of the fields holding
+// them, and fields t doesn't have, return an ErrEncode
+func (t *Type) Marshall(w io.Writer, m Message) error {
+	return t.s.marshallMessage(w, t.at, m)
+}
+
+// Unmarshall reads a value of type t from r, as written by Marshall, or by
+// the generated Marshall function.
+//
+// As with generated Unmarshall functions, limits may be set by passing a
+// mint.DecodeReader as r, and errors are returned as a mint.ErrDecode,
+// recording the path to the value which couldn't be decoded
+func (t *Type) Unmarshall(r io.Reader) (Message, error) {
+	return t.s.unmarshallMessage(mint.AsDecodeReader(r), t.at)
+}
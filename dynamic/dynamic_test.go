@@ -0,0 +1,457 @@
+package dynamic
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/vinyl-linux/mint"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+const sampleDocument = `
+enum Level {
+    Low
+    High
+}
+
+type Point {
+    float64 X = 0;
+    float64 Y = 1;
+}
+
+union Shape {
+    Point Point = 0;
+    string Label = 3;
+}
+
+type Sample {
+    string Name = 0;
+    optional int32 Count = 2;
+    Level Level = 1 [default = High];
+    optional Point Origin = 3;
+    [2][]int16 Grid = 4;
+    map<string, Level> Levels = 5;
+    Shape Shape = 6;
+    uuid ID = 7;
+    datetime At = 8;
+    bool Enabled = 9;
+    byte Flags = 10;
+    int64 Big = 11;
+    float32 Ratio = 12;
+}
+`
+
+func mustParse(t *testing.T, doc string) *parser.AST {
+	t.Helper()
+
+	ast, err := parser.Parse("test.mint", strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ast
+}
+
+func mustNew(t *testing.T, ast *parser.AST, name string) *Type {
+	t.Helper()
+
+	typ, err := New(ast, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return typ
+}
+
+func TestNew(t *testing.T) {
+	sample := mustParse(t, sampleDocument)
+
+	weather, err := parser.ParseDir("testdata/packages/weather")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name        string
+		ast         *parser.AST
+		typ         string
+		expectError error
+	}{
+		{"Type", sample, "Sample", nil},
+		{"Imported type", weather, "geo.Coordinates", nil},
+		{"Enum", sample, "Level", ErrUnknownType{Name: "Level"}},
+		{"Union", sample, "Shape", ErrUnknownType{Name: "Shape"}},
+		{"Missing type", sample, "Nope", ErrUnknownType{Name: "Nope"}},
+		{"Missing package", sample, "geo.Coordinates", ErrUnknownType{Name: "geo.Coordinates"}},
+		{"Missing imported type", weather, "geo.Nope", ErrUnknownType{Name: "geo.Nope"}},
+		{"Nil AST", nil, "Sample", ErrUnknownType{Name: "Sample"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.ast, test.typ)
+			if err != test.expectError {
+				t.Errorf("expected %#v, received %#v", test.expectError, err)
+			}
+		})
+	}
+}
+
+func TestType_New(t *testing.T) {
+	m, err := mustNew(t, mustParse(t, sampleDocument), "Sample").New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Message{
+		"Name":    "",
+		"Level":   "High",
+		"Grid":    []any{[]any{}, []any{}},
+		"Levels":  map[any]any{},
+		"Shape":   Union{},
+		"ID":      uuid.UUID{},
+		"At":      time.Time{},
+		"Enabled": false,
+		"Flags":   byte(0),
+		"Big":     int64(0),
+		"Ratio":   float32(0),
+	}
+
+	if !reflect.DeepEqual(expect, m) {
+		t.Errorf("expected\n%#v\nreceived\n%#v", expect, m)
+	}
+}
+
+func TestType_Marshall(t *testing.T) {
+	typ := mustNew(t, mustParse(t, sampleDocument), "Sample")
+
+	id := uuid.Must(uuid.NewV4())
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	m := Message{
+		"Name":  "jo",
+		"Count": int32(5),
+		"Level": "Low",
+		"Grid": []any{
+			[]any{int16(1), int16(2)},
+			[]any{},
+		},
+		"Levels": map[any]any{
+			"b": "High",
+			"a": "Low",
+		},
+		"Shape":   Union{Variant: "Label", Value: "x"},
+		"ID":      id,
+		"At":      at,
+		"Enabled": true,
+		"Flags":   byte(7),
+		"Big":     int64(-1),
+		"Ratio":   float32(0.5),
+	}
+
+	// Presence bitmap of Count and Origin, then each field in tag order
+	expect := mint.AppendByte(nil, 0b01)
+	expect = mint.AppendString(expect, "jo")
+	expect = mint.AppendByte(expect, 1)
+	expect = mint.AppendInt32(expect, 5)
+	expect = mint.AppendUint32(expect, 2)
+	expect = mint.AppendInt16(expect, 1)
+	expect = mint.AppendInt16(expect, 2)
+	expect = mint.AppendUint32(expect, 0)
+	expect = mint.AppendUint32(expect, 4)
+	expect = mint.AppendString(expect, "a")
+	expect = mint.AppendByte(expect, 1)
+	expect = mint.AppendString(expect, "b")
+	expect = mint.AppendByte(expect, 2)
+	expect = mint.AppendByte(expect, 3)
+	expect = mint.AppendString(expect, "x")
+	expect = mint.AppendUuid(expect, id)
+	expect = mint.AppendDatetime(expect, at)
+	expect = mint.AppendBool(expect, true)
+	expect = mint.AppendByte(expect, 7)
+	expect = mint.AppendInt64(expect, -1)
+	expect = mint.AppendFloat32(expect, 0.5)
+
+	buf := new(bytes.Buffer)
+
+	err := typ.Marshall(buf, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(expect, buf.Bytes()) {
+		t.Fatalf("expected\n%v\nreceived\n%v", expect, buf.Bytes())
+	}
+
+	received, err := typ.Unmarshall(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !received["At"].(time.Time).Equal(at) {
+		t.Errorf("expected %v, received %v", at, received["At"])
+	}
+
+	received["At"] = at
+
+	if !reflect.DeepEqual(m, received) {
+		t.Errorf("expected\n%#v\nreceived\n%#v", m, received)
+	}
+}
+
+func TestType_Marshall_Defaults(t *testing.T) {
+	typ := mustNew(t, mustParse(t, sampleDocument), "Sample")
+
+	empty := new(bytes.Buffer)
+
+	err := typ.Marshall(empty, Message{"Shape": Union{Variant: "Label", Value: ""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := typ.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m["Shape"] = Union{Variant: "Label", Value: ""}
+
+	defaults := new(bytes.Buffer)
+
+	err = typ.Marshall(defaults, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(defaults.Bytes(), empty.Bytes()) {
+		t.Errorf("expected\n%v\nreceived\n%v", defaults.Bytes(), empty.Bytes())
+	}
+}
+
+func TestType_Marshall_Errors(t *testing.T) {
+	typ := mustNew(t, mustParse(t, sampleDocument), "Sample")
+
+	shape := Union{Variant: "Label", Value: "x"}
+
+	for _, test := range []struct {
+		name        string
+		m           Message
+		expectPath  string
+		expectError error
+	}{
+		{"Unknown field", Message{"Shape": shape, "Bogus": 1}, "Sample.Bogus", ErrUnknownField{Field: "Bogus"}},
+		{"Wrong scalar type", Message{"Shape": shape, "Count": 5}, "Sample.Count", ErrInvalidValue{Expected: "int32", Received: 5}},
+		{"Unknown enum value", Message{"Shape": shape, "Level": "Medium"}, "Sample.Level", ErrInvalidValue{Expected: "Level", Received: "Medium"}},
+		{"Wrong array length", Message{"Shape": shape, "Grid": []any{}}, "Sample.Grid", ErrInvalidLength{Expected: 2, Received: 0}},
+		{"Wrong element type", Message{"Shape": shape, "Grid": []any{[]any{int16(1), "2"}, []any{}}}, "Sample.Grid[0][1]", ErrInvalidValue{Expected: "int16", Received: "2"}},
+		{"Wrong map value", Message{"Shape": shape, "Levels": map[any]any{"a": "Medium"}}, `Sample.Levels["a"]`, ErrInvalidValue{Expected: "Level", Received: "Medium"}},
+		{"Wrong map key", Message{"Shape": shape, "Levels": map[any]any{1: "Low"}}, "Sample.Levels[1]", ErrInvalidValue{Expected: "string", Received: 1}},
+		{"Wrong nested field", Message{"Shape": shape, "Origin": Message{"X": 1.0, "Y": 2}}, "Sample.Origin.Y", ErrInvalidValue{Expected: "float64", Received: 2}},
+		{"Unknown nested field", Message{"Shape": shape, "Origin": Message{"Z": 1.0}}, "Sample.Origin.Z", ErrUnknownField{Field: "Z"}},
+		{"Not a message", Message{"Shape": shape, "Origin": map[string]any{}}, "Sample.Origin", ErrInvalidValue{Expected: "Point", Received: map[string]any{}}},
+		{"Unset union", Message{}, "Sample.Shape", ErrUnknownVariant{Union: "Shape"}},
+		{"Unknown variant", Message{"Shape": Union{Variant: "Circle"}}, "Sample.Shape", ErrUnknownVariant{Union: "Shape", Variant: "Circle"}},
+		{"Wrong variant value", Message{"Shape": Union{Variant: "Point", Value: "x"}}, "Sample.Shape.Point", ErrInvalidValue{Expected: "Point", Received: "x"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := typ.Marshall(new(bytes.Buffer), test.m)
+
+			var ee ErrEncode
+			if !errors.As(err, &ee) {
+				t.Fatalf("expected ErrEncode, received %#v", err)
+			}
+
+			if ee.Path() != test.expectPath {
+				t.Errorf("expected path %q, received %q", test.expectPath, ee.Path())
+			}
+
+			if !reflect.DeepEqual(test.expectError, ee.Err) {
+				t.Errorf("expected %#v, received %#v", test.expectError, ee.Err)
+			}
+		})
+	}
+}
+
+func TestType_Unmarshall_Errors(t *testing.T) {
+	typ := mustNew(t, mustParse(t, sampleDocument), "Sample")
+
+	// Everything up to, and including, Levels with an empty Grid
+	prefix := mint.AppendByte(nil, 0)
+	prefix = mint.AppendString(prefix, "jo")
+	prefix = mint.AppendByte(prefix, 1)
+	prefix = mint.AppendUint32(prefix, 0)
+	prefix = mint.AppendUint32(prefix, 0)
+
+	invalidEnum := mint.AppendString(mint.AppendByte(nil, 0), "jo")
+	invalidEnum = mint.AppendByte(invalidEnum, 9)
+
+	for _, test := range []struct {
+		name        string
+		b           []byte
+		expectField string
+		expectError string
+	}{
+		{"Invalid enum", invalidEnum, "Level", "invalid value for type Level"},
+		{"Invalid variant", mint.AppendByte(mint.AppendUint32(prefix, 0), 1), "Shape", "invalid variant for union Shape"},
+		{"Truncated", prefix[:3], "Name", "input truncated"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := typ.Unmarshall(bytes.NewReader(test.b))
+
+			var de mint.ErrDecode
+			if !errors.As(err, &de) {
+				t.Fatalf("expected mint.ErrDecode, received %#v", err)
+			}
+
+			if !strings.HasPrefix(de.Field, test.expectField) {
+				t.Errorf("expected field %q, received %q", test.expectField, de.Field)
+			}
+
+			if !strings.Contains(err.Error(), test.expectError) {
+				t.Errorf("expected %q in %q", test.expectError, err.Error())
+			}
+		})
+	}
+}
+
+func TestType_Unmarshall_LargeDeclaredCount(t *testing.T) {
+	typ := mustNew(t, mustParse(t, sampleDocument), "Sample")
+
+	// A Grid whose first slice declares far more elements than follow
+	b := mint.AppendByte(nil, 0)
+	b = mint.AppendString(b, "jo")
+	b = mint.AppendByte(b, 1)
+	b = mint.AppendUint32(b, 4<<20)
+	b = mint.AppendInt16(b, 1)
+
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+	_, err := typ.Unmarshall(bytes.NewReader(b))
+	runtime.ReadMemStats(&after)
+
+	var te mint.ErrTruncated
+	if !errors.As(err, &te) {
+		t.Errorf("expected mint.ErrTruncated, received %#v", err)
+	}
+
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("expected at most 1MiB to be allocated, received %d bytes", allocated)
+	}
+}
+
+func TestType_Evolvable(t *testing.T) {
+	v1 := mustNew(t, mustParse(t, `
+type Person [evolvable] {
+    string Name = 0;
+    optional int32 Age = 1;
+}
+`), "Person")
+
+	v2 := mustNew(t, mustParse(t, `
+enum Level {
+    Low
+    High
+}
+
+type Person [evolvable] {
+    string Name = 0;
+    optional int32 Age = 1;
+    Level Level = 2 [default = High];
+    optional string Nick = 3;
+}
+`), "Person")
+
+	for _, test := range []struct {
+		name   string
+		writer *Type
+		reader *Type
+		m      Message
+		expect Message
+	}{
+		{"Same version", v2, v2,
+			Message{"Name": "jo", "Age": int32(3), "Level": "Low", "Nick": "j"},
+			Message{"Name": "jo", "Age": int32(3), "Level": "Low", "Nick": "j"},
+		},
+		{"Newer writer", v2, v1,
+			Message{"Name": "jo", "Age": int32(3), "Level": "Low", "Nick": "j"},
+			Message{"Name": "jo", "Age": int32(3)},
+		},
+		{"Older writer", v1, v2,
+			Message{"Name": "jo"},
+			Message{"Name": "jo", "Level": "High"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+
+			err := test.writer.Marshall(buf, test.m)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Trailing bytes ensure the reader consumes exactly the
+			// body it was written
+			buf.WriteString("trailing")
+
+			r := bytes.NewReader(buf.Bytes())
+
+			received, err := test.reader.Unmarshall(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(test.expect, received) {
+				t.Errorf("expected\n%#v\nreceived\n%#v", test.expect, received)
+			}
+
+			if r.Len() != len("trailing") {
+				t.Errorf("expected %d bytes remaining, received %d", len("trailing"), r.Len())
+			}
+		})
+	}
+}
+
+func TestType_Imports(t *testing.T) {
+	ast, err := parser.ParseDir("testdata/packages/weather")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typ := mustNew(t, ast, "Report")
+
+	m, err := typ.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m["Terrain"] != "Sea" {
+		t.Errorf("expected default Sea, received %#v", m["Terrain"])
+	}
+
+	m["Route"] = []any{
+		Message{"Latitude": 1.5, "Longitude": -2.5},
+	}
+	m["Notes"] = map[any]any{"Land": "dry"}
+	m["Destination"] = Message{"Latitude": 3.0, "Longitude": 4.0}
+
+	buf := new(bytes.Buffer)
+
+	err = typ.Marshall(buf, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received, err := typ.Unmarshall(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(m, received) {
+		t.Errorf("expected\n%#v\nreceived\n%#v", m, received)
+	}
+}
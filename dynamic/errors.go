@@ -0,0 +1,154 @@
+package dynamic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownType is returned when a type, enum, or union can't be found
+// in the documents being used
+type ErrUnknownType struct {
+	Name string
+}
+
+func (e ErrUnknownType) Error() string {
+	return fmt.Sprintf("unknown type %s", e.Name)
+}
+
+// ErrUnknownField is returned when marshalling a Message which holds a
+// field its type doesn't have
+type ErrUnknownField struct {
+	Field string
+}
+
+func (e ErrUnknownField) Error() string {
+	return fmt.Sprintf("unknown field %s", e.Field)
+}
+
+// ErrInvalidValue is returned when marshalling a value which doesn't
+// match the type it's being marshalled as, such as an int where an int32
+// is expected, or the name of a value an enum doesn't have
+type ErrInvalidValue struct {
+	Expected string
+	Received any
+}
+
+func (e ErrInvalidValue) Error() string {
+	if e.Received == nil {
+		return fmt.Sprintf("expected %s, received nothing", e.Expected)
+	}
+
+	return fmt.Sprintf("expected %s, received %#v (%T)", e.Expected, e.Received, e.Received)
+}
+
+// ErrInvalidLength is returned when marshalling an array which holds
+// more, or fewer, elements than its type does
+type ErrInvalidLength struct {
+	Expected int
+	Received int
+}
+
+func (e ErrInvalidLength) Error() string {
+	return fmt.Sprintf("expected %d elements, received %d", e.Expected, e.Received)
+}
+
+// ErrUnknownVariant is returned when marshalling a Union which doesn't
+// set one of its union's variants
+type ErrUnknownVariant struct {
+	Union   string
+	Variant string
+}
+
+func (e ErrUnknownVariant) Error() string {
+	if e.Variant == "" {
+		return fmt.Sprintf("no variant of union %s is set", e.Union)
+	}
+
+	return fmt.Sprintf("union %s has no variant %s", e.Union, e.Variant)
+}
+
+// ErrUnknownFunction is returned when transforming or validating a Message
+// of a type annotated with a builtin transform or validator which mint
+// doesn't provide, such as +mint:validate:nonsuch
+type ErrUnknownFunction struct {
+	Function string
+}
+
+func (e ErrUnknownFunction) Error() string {
+	return fmt.Sprintf("unknown builtin function %s", e.Function)
+}
+
+// ErrEncode wraps an error encountered while marshalling, recording
+// where in a message the error occurred, much as mint.ErrDecode does
+// while unmarshalling.
+//
+// Type is the outermost type being marshalled, and Field the path from
+// that type to the failing value, such as Location.Tags[3]
+type ErrEncode struct {
+	Type  string
+	Field string
+	Err   error
+}
+
+// Path returns the full path to the failing value, such as
+// WeatherForecast.Location.Tags[3]
+func (e ErrEncode) Path() string {
+	if e.Type == "" {
+		return e.Field
+	}
+
+	return joinPath(e.Type, e.Field)
+}
+
+func (e ErrEncode) Error() string {
+	return fmt.Sprintf("error encoding %s: %v", e.Path(), e.Err)
+}
+
+func (e ErrEncode) Unwrap() error {
+	return e.Err
+}
+
+// wrapEncodeError adds context to an error returned while marshalling
+// field (or element) f of type t, building a full path as errors are
+// returned up through nested types, as mint.WrapDecodeError does.
+// Collections pass an empty t.
+//
+// wrapEncodeError returns nil when err is nil
+func wrapEncodeError(t, f string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ee, ok := err.(ErrEncode); ok {
+		if t != "" {
+			ee.Type = t
+		}
+
+		ee.Field = joinPath(f, ee.Field)
+
+		return ee
+	}
+
+	return ErrEncode{
+		Type:  t,
+		Field: f,
+		Err:   err,
+	}
+}
+
+// joinPath joins two path segments, eliding the separator where the
+// latter is an index, such as Tags[3]
+func joinPath(a, b string) string {
+	switch {
+	case a == "":
+		return b
+
+	case b == "":
+		return a
+
+	case strings.HasPrefix(b, "["):
+		return a + b
+	}
+
+	return a + "." + b
+}
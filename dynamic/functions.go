@@ -0,0 +1,253 @@
+package dynamic
+
+import (
+	"github.com/vinyl-linux/mint"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// Transform runs the builtin transforms of t, and of any types nested
+// within it, over m, replacing the transformed values in place, as the
+// generated Marshall function would before encoding m.
+//
+// Custom transforms can't be run without generated code, and so are
+// skipped
+func (t *Type) Transform(m Message) error {
+	return t.s.transformMessage(t.at, m)
+}
+
+// transformMessage runs the builtin transforms of at over m
+func (s schema) transformMessage(at parser.AnnotatedType, m Message) (err error) {
+	for _, e := range at.Entries {
+		v, ok := m[e.Name]
+
+		switch {
+		case e.Optional && v == nil:
+			continue
+
+		case !ok:
+			v, err = s.fieldDefault(e)
+			if err != nil {
+				return
+			}
+		}
+
+		for _, f := range e.Transformations {
+			if f.IsCustom {
+				continue
+			}
+
+			fn, ok := mint.BuiltinTransforms[f.Function]
+			if !ok {
+				return wrapEncodeError(at.Name, e.Name, ErrUnknownFunction{Function: f.Function})
+			}
+
+			v, err = fn(v)
+			if err != nil {
+				return wrapEncodeError(at.Name, e.Name, err)
+			}
+
+			m[e.Name] = v
+		}
+
+		err = s.transformValue(e.DataType, v)
+		if err != nil {
+			return wrapEncodeError(at.Name, e.Name, err)
+		}
+	}
+
+	return
+}
+
+// transformValue runs the builtin transforms of any types nested within
+// v, a value of data type dt
+func (s schema) transformValue(dt *parser.DataType, v any) error {
+	// values which don't match dt are left for Marshall to report
+	if dt == nil {
+		return nil
+	}
+
+	switch v := v.(type) {
+	case []any:
+		for i, e := range v {
+			err := s.transformValue(dt.Elem(), e)
+			if err != nil {
+				return wrapEncodeError("", mint.IndexSegment(i), err)
+			}
+		}
+
+	case map[any]any:
+		if dt.Map == nil {
+			return nil
+		}
+
+		for _, k := range sortedKeys(v) {
+			err := s.transformValue(dt.Map.Value, v[k])
+			if err != nil {
+				return wrapEncodeError("", mint.KeySegment(k), err)
+			}
+		}
+
+	case Message, Union:
+		if dt.Scalar == nil {
+			return nil
+		}
+
+		return s.transformScalar(dt.Scalar.Type, v)
+	}
+
+	return nil
+}
+
+// transformScalar runs the builtin transforms of v, a value of the type
+// or union called t
+func (s schema) transformScalar(t string, v any) error {
+	d, err := s.lookup(t)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case Message:
+		if d.typ != nil {
+			return d.s.transformMessage(*d.typ, v)
+		}
+
+	case Union:
+		for _, variant := range d.unionVariants() {
+			if variant.Name == v.Variant {
+				return wrapEncodeError(t, variant.Name, d.s.transformValue(scalarType(variant.Type), v.Value))
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate runs the builtin validators of t, and of any types nested
+// within it, over m, as the generated Marshall function would before
+// encoding m, returning each failure along with the path to the value
+// which failed, such as Location.Tags[3].
+//
+// Custom validators can't be run without generated code, and so are
+// skipped
+func (t *Type) Validate(m Message) error {
+	errs := make([]error, 0)
+
+	err := t.s.validateMessage(t.at, m, "", &errs)
+	if err != nil {
+		return err
+	}
+
+	return mint.ValidationErrors(t.at.Name, errs)
+}
+
+// validateMessage runs the builtin validators of at over m, found at
+// path, appending any failures to errs.
+//
+// The error returned, rather than appended, is for documents referring
+// to validators which don't exist
+func (s schema) validateMessage(at parser.AnnotatedType, m Message, path string, errs *[]error) (err error) {
+	for _, e := range at.Entries {
+		v, ok := m[e.Name]
+
+		switch {
+		case e.Optional && v == nil:
+			continue
+
+		case !ok:
+			v, err = s.fieldDefault(e)
+			if err != nil {
+				return
+			}
+		}
+
+		fieldPath := joinPath(path, e.Name)
+
+		for _, f := range e.Validations {
+			if f.IsCustom {
+				continue
+			}
+
+			fn, ok := mint.BuiltinValidators[f.Function]
+			if !ok {
+				return wrapEncodeError(at.Name, e.Name, ErrUnknownFunction{Function: f.Function})
+			}
+
+			verr := fn(e.Name, v)
+			if verr != nil {
+				*errs = append(*errs, mint.ErrInvalidElement{Path: fieldPath, Err: verr})
+			}
+		}
+
+		err = s.validateValue(e.DataType, v, fieldPath, errs)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// validateValue runs the builtin validators of any types nested within
+// v, a value of data type dt found at path
+func (s schema) validateValue(dt *parser.DataType, v any, path string, errs *[]error) error {
+	if dt == nil {
+		return nil
+	}
+
+	switch v := v.(type) {
+	case []any:
+		for i, e := range v {
+			err := s.validateValue(dt.Elem(), e, path+mint.IndexSegment(i), errs)
+			if err != nil {
+				return err
+			}
+		}
+
+	case map[any]any:
+		if dt.Map == nil {
+			return nil
+		}
+
+		for _, k := range sortedKeys(v) {
+			err := s.validateValue(dt.Map.Value, v[k], path+mint.KeySegment(k), errs)
+			if err != nil {
+				return err
+			}
+		}
+
+	case Message, Union:
+		if dt.Scalar == nil {
+			return nil
+		}
+
+		return s.validateScalar(dt.Scalar.Type, v, path, errs)
+	}
+
+	return nil
+}
+
+// validateScalar runs the builtin validators of v, a value of the type
+// or union called t found at path
+func (s schema) validateScalar(t string, v any, path string, errs *[]error) error {
+	d, err := s.lookup(t)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case Message:
+		if d.typ != nil {
+			return d.s.validateMessage(*d.typ, v, path, errs)
+		}
+
+	case Union:
+		for _, variant := range d.unionVariants() {
+			if variant.Name == v.Variant {
+				return d.s.validateValue(scalarType(variant.Type), v.Value, joinPath(path, variant.Name), errs)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package dynamic
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vinyl-linux/mint"
+)
+
+const functionsDocument = `
+type Tag {
+    +mint:validate:string_not_empty
+    string Name = 0;
+}
+
+union Detail {
+    Tag Tag = 0;
+    string Note = 1;
+}
+
+type Event {
+    +mint:validate:string_not_empty
+    string Name = 0;
+
+    +mint:transform:date_in_utc
+    +mint:validate:date_in_past
+    datetime At = 1;
+
+    []Tag Tags = 2;
+    map<string, Tag> Labels = 3;
+    optional Tag Primary = 4;
+    Detail Detail = 5;
+
+    +custom:validate:anything
+    +custom:transform:anything
+    string Other = 6;
+}
+`
+
+func TestType_Transform(t *testing.T) {
+	typ := mustNew(t, mustParse(t, functionsDocument), "Event")
+
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.FixedZone("BST", 3600))
+
+	m := Message{
+		"Name":  "launch",
+		"At":    at,
+		"Other": "untouched",
+	}
+
+	err := typ.Transform(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m["At"].(time.Time).Location() != time.UTC {
+		t.Errorf("expected UTC, received %v", m["At"].(time.Time).Location())
+	}
+
+	if !m["At"].(time.Time).Equal(at) {
+		t.Errorf("expected %v, received %v", at, m["At"])
+	}
+
+	if m["Other"] != "untouched" {
+		t.Errorf("expected custom transforms to be skipped, received %#v", m["Other"])
+	}
+}
+
+func TestType_Validate(t *testing.T) {
+	typ := mustNew(t, mustParse(t, functionsDocument), "Event")
+
+	past := time.Now().Add(-time.Hour)
+
+	for _, test := range []struct {
+		name        string
+		m           Message
+		expectPaths []string
+	}{
+		{"Valid", Message{"Name": "launch", "At": past, "Detail": Union{Variant: "Note", Value: ""}}, nil},
+		{"Missing fields take their defaults", Message{"At": past}, []string{"Name"}},
+		{"Date in future", Message{"Name": "launch", "At": time.Now().Add(time.Hour)}, []string{"At"}},
+		{"Nested types", Message{
+			"At":      past,
+			"Tags":    []any{Message{"Name": "a"}, Message{}},
+			"Labels":  map[any]any{"b": Message{"Name": ""}, "a": Message{"Name": ""}},
+			"Primary": Message{},
+			"Detail":  Union{Variant: "Tag", Value: Message{}},
+		}, []string{"Name", "Tags[1].Name", `Labels["a"].Name`, `Labels["b"].Name`, "Primary.Name", "Detail.Tag.Name"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := typ.Validate(test.m)
+
+			if test.expectPaths == nil {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+
+				return
+			}
+
+			var ve mint.ErrValidationErrors
+			if !errors.As(err, &ve) {
+				t.Fatalf("expected mint.ErrValidationErrors, received %#v", err)
+			}
+
+			received := make([]string, 0)
+			for _, line := range strings.Split(strings.TrimSpace(err.Error()), "\n")[1:] {
+				path, _, _ := strings.Cut(strings.TrimSpace(line), ": ")
+				received = append(received, path)
+			}
+
+			if !reflect.DeepEqual(test.expectPaths, received) {
+				t.Errorf("expected %#v, received %#v", test.expectPaths, received)
+			}
+		})
+	}
+}
+
+func TestType_Validate_UnknownFunction(t *testing.T) {
+	typ := mustNew(t, mustParse(t, `
+type Broken {
+    +mint:validate:nonsuch
+    string Name = 0;
+}
+`), "Broken")
+
+	err := typ.Validate(Message{})
+
+	expect := ErrEncode{Type: "Broken", Field: "Name", Err: ErrUnknownFunction{Function: "nonsuch"}}
+	if !reflect.DeepEqual(expect, err) {
+		t.Errorf("expected %#v, received %#v", expect, err)
+	}
+}
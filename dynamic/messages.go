@@ -0,0 +1,193 @@
+package dynamic
+
+import (
+	"bytes"
+	"io"
+	"sort"
+
+	"github.com/vinyl-linux/mint"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// marshallMessage writes m, a value of type at, to w; its presence
+// bitmap, should it have one, followed by each field in tag order, all
+// wrapped in a length prefixed body where at is evolvable
+func (s schema) marshallMessage(w io.Writer, at parser.AnnotatedType, m Message) (err error) {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := entry(at, name); !ok {
+			return wrapEncodeError(at.Name, name, ErrUnknownField{Field: name})
+		}
+	}
+
+	entries := at.ByTag()
+
+	var (
+		presence  uint64
+		optionals int
+	)
+
+	for _, e := range entries {
+		if !e.Optional {
+			continue
+		}
+
+		if m[e.Name] != nil {
+			presence |= 1 << optionals
+		}
+
+		optionals++
+	}
+
+	body := w
+
+	var buf *bytes.Buffer
+
+	switch {
+	case at.Evolvable:
+		buf = new(bytes.Buffer)
+		body = buf
+
+		err = mint.WriteSizedPresence(buf, presence, optionals)
+
+	case optionals > 0:
+		err = mint.WritePresence(w, presence, optionals)
+	}
+
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		v, ok := m[e.Name]
+
+		switch {
+		case e.Optional && v == nil:
+			continue
+
+		case !ok:
+			v, err = s.fieldDefault(e)
+			if err != nil {
+				return wrapEncodeError(at.Name, e.Name, err)
+			}
+		}
+
+		err = value{s: s, dt: e.DataType, v: v}.Marshall(body)
+		if err != nil {
+			return wrapEncodeError(at.Name, e.Name, err)
+		}
+	}
+
+	if !at.Evolvable {
+		return
+	}
+
+	err = mint.WriteBodySize(w, buf.Len())
+	if err != nil {
+		return
+	}
+
+	_, err = w.Write(buf.Bytes())
+
+	return
+}
+
+// unmarshallMessage reads a value of type at from dr, as the generated
+// Unmarshall function would
+func (s schema) unmarshallMessage(dr *mint.DecodeReader, at parser.AnnotatedType) (m Message, err error) {
+	err = dr.Enter()
+	if err != nil {
+		return
+	}
+
+	defer dr.Leave()
+
+	// fields missing from the end of the body of an evolvable type,
+	// having been added since it was written, are left with their
+	// defaults
+	m, err = s.newMessage(at)
+	if err != nil {
+		return nil, mint.WrapDecodeError(dr, at.Name, "", err)
+	}
+
+	entries := at.ByTag()
+
+	optionals := 0
+	for _, e := range entries {
+		if e.Optional {
+			optionals++
+		}
+	}
+
+	var presence uint64
+
+	switch {
+	case at.Evolvable:
+		err = dr.EnterBody()
+		if err != nil {
+			return nil, mint.WrapDecodeError(dr, at.Name, "", err)
+		}
+
+		defer dr.LeaveBody()
+
+		presence, err = mint.ReadSizedPresence(dr, optionals)
+
+	case optionals > 0:
+		presence, err = mint.ReadPresence(dr, optionals)
+	}
+
+	if err != nil {
+		return nil, mint.WrapDecodeError(dr, at.Name, "", err)
+	}
+
+	bit := 0
+	for _, e := range entries {
+		if at.Evolvable && !dr.More() {
+			break
+		}
+
+		if e.Optional {
+			bit++
+
+			if presence&(1<<(bit-1)) == 0 {
+				continue
+			}
+		}
+
+		v := s.newValue(e.DataType)
+
+		err = v.Unmarshall(dr)
+		if err != nil {
+			return nil, mint.WrapDecodeError(dr, at.Name, e.Name, err)
+		}
+
+		m[e.Name] = v.Value()
+	}
+
+	if at.Evolvable {
+		err = dr.SkipBody()
+		if err != nil {
+			return nil, mint.WrapDecodeError(dr, at.Name, "", err)
+		}
+	}
+
+	return
+}
+
+// entry returns the field of at called name, and whether at has such a
+// field
+func entry(at parser.AnnotatedType, name string) (parser.AnnotatedEntry, bool) {
+	for _, e := range at.Entries {
+		if e.Name == name {
+			return e, true
+		}
+	}
+
+	return parser.AnnotatedEntry{}, false
+}
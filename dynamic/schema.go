@@ -0,0 +1,93 @@
+package dynamic
+
+import (
+	"strings"
+
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// schema is a set of parsed documents, against which the names of types,
+// enums, and unions are resolved
+type schema struct {
+	ast *parser.AST
+}
+
+// definition is the type, enum, or union a name refers to, along with
+// the schema in which the names it refers to are, in turn, resolved
+type definition struct {
+	s schema
+
+	typ   *parser.AnnotatedType
+	enum  *parser.Enum
+	union *parser.Union
+}
+
+// lookup finds the type, enum, or union called name, which may be
+// qualified by the name of an imported package, such as geo.Location.
+//
+// Imported types refer to their own package's types without qualifying
+// them, and so these are resolved against the imported package
+func (s schema) lookup(name string) (d definition, err error) {
+	if s.ast == nil {
+		return d, ErrUnknownType{Name: name}
+	}
+
+	if alias, local, ok := strings.Cut(name, "."); ok {
+		imported, ok := s.ast.Imports[alias]
+		if !ok {
+			return d, ErrUnknownType{Name: name}
+		}
+
+		d, err = schema{ast: imported}.lookup(local)
+		if err != nil {
+			return d, ErrUnknownType{Name: name}
+		}
+
+		return
+	}
+
+	d.s = s
+
+	for i := range s.ast.Types {
+		if s.ast.Types[i].Name == name {
+			d.typ = &s.ast.Types[i]
+
+			return
+		}
+	}
+
+	for i := range s.ast.Enums {
+		if s.ast.Enums[i].Name == name {
+			d.enum = &s.ast.Enums[i]
+
+			return
+		}
+	}
+
+	for i := range s.ast.Unions {
+		if s.ast.Unions[i].Name == name {
+			d.union = &s.ast.Unions[i]
+
+			return
+		}
+	}
+
+	return d, ErrUnknownType{Name: name}
+}
+
+// unionVariants returns the variants of d, where d is a union
+func (d definition) unionVariants() []*parser.UnionVariant {
+	if d.union == nil {
+		return nil
+	}
+
+	return d.union.Variants
+}
+
+// scalarType returns a DataType for the scalar, type, enum, or union
+// called name, such as the key of a map, or a variant of a union
+func scalarType(name string) *parser.DataType {
+	return &parser.DataType{
+		Scalar: &parser.Scalar{Type: name},
+	}
+}
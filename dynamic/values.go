@@ -0,0 +1,488 @@
+package dynamic
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/vinyl-linux/mint"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// value is a single value of data type dt, wrapped such that it can be
+// marshalled and unmarshalled by mint's scalars and collections.
+//
+// Where a value is an element of a collection, seg holds the path segment
+// describing it, such as [3], with which encoding errors are wrapped
+type value struct {
+	s   schema
+	dt  *parser.DataType
+	seg string
+	v   any
+}
+
+// newValue returns a value of data type dt, ready to be unmarshalled into
+func (s schema) newValue(dt *parser.DataType) *value {
+	return &value{
+		s:  s,
+		dt: dt,
+	}
+}
+
+func (v value) Marshall(w io.Writer) (err error) {
+	switch {
+	case v.dt.Scalar != nil:
+		err = v.s.marshallScalar(w, v.dt.Scalar.Type, v.v)
+
+	case v.dt.Map != nil:
+		err = v.s.marshallMap(w, v.dt, v.v)
+
+	default:
+		err = v.s.marshallSlice(w, v.dt, v.v)
+	}
+
+	return wrapEncodeError("", v.seg, err)
+}
+
+func (v *value) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+
+	switch {
+	case v.dt.Scalar != nil:
+		v.v, err = v.s.unmarshallScalar(dr, v.dt.Scalar.Type)
+
+	case v.dt.Map != nil:
+		v.v, err = v.s.unmarshallMap(dr, v.dt)
+
+	default:
+		v.v, err = v.s.unmarshallSlice(dr, v.dt)
+	}
+
+	return
+}
+
+func (v value) Value() any {
+	return v.v
+}
+
+// marshallScalar writes v, a value of the builtin scalar, type, enum, or
+// union called t, to w
+func (s schema) marshallScalar(w io.Writer, t string, v any) error {
+	if _, ok := builtins[t]; ok {
+		m, err := toScalar(t, v)
+		if err != nil {
+			return err
+		}
+
+		return m.Marshall(w)
+	}
+
+	d, err := s.lookup(t)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case d.enum != nil:
+		return marshallEnum(w, *d.enum, v)
+
+	case d.union != nil:
+		return d.s.marshallUnion(w, *d.union, v)
+	}
+
+	m, ok := v.(Message)
+	if !ok {
+		return ErrInvalidValue{Expected: t, Received: v}
+	}
+
+	return d.s.marshallMessage(w, *d.typ, m)
+}
+
+// unmarshallScalar reads a value of the builtin scalar, type, enum, or
+// union called t from dr
+func (s schema) unmarshallScalar(dr *mint.DecodeReader, t string) (any, error) {
+	if _, ok := builtins[t]; ok {
+		m := newScalar(t)
+
+		err := m.Unmarshall(dr)
+		if err != nil {
+			return nil, err
+		}
+
+		return m.Value(), nil
+	}
+
+	d, err := s.lookup(t)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case d.enum != nil:
+		return unmarshallEnum(dr, *d.enum)
+
+	case d.union != nil:
+		return d.s.unmarshallUnion(dr, *d.union)
+	}
+
+	return d.s.unmarshallMessage(dr, *d.typ)
+}
+
+// marshallSlice writes v, a slice or array of data type dt, to w via a
+// mint.SliceCollection
+func (s schema) marshallSlice(w io.Writer, dt *parser.DataType, v any) error {
+	elems, ok := v.([]any)
+	if !ok && v != nil {
+		return ErrInvalidValue{Expected: dt.String(), Received: v}
+	}
+
+	fixed := dt.FixedSizeSlice != nil
+	if fixed && len(elems) != dt.FixedSizeSlice.Size {
+		// a missing array is written as an array of zero values, as
+		// generated code would
+		if v != nil {
+			return ErrInvalidLength{Expected: dt.FixedSizeSlice.Size, Received: len(elems)}
+		}
+
+		zero, err := s.zero(dt)
+		if err != nil {
+			return err
+		}
+
+		elems = zero.([]any)
+	}
+
+	muvs := make([]mint.MarshallerUnmarshallerValuer, len(elems))
+	for i, e := range elems {
+		muvs[i] = &value{
+			s:   s,
+			dt:  dt.Elem(),
+			seg: mint.IndexSegment(i),
+			v:   e,
+		}
+	}
+
+	return mint.NewSliceCollection(muvs, fixed).Marshall(w)
+}
+
+// unmarshallSlice reads a slice or array of data type dt from dr via a
+// mint.SliceCollection
+func (s schema) unmarshallSlice(dr *mint.DecodeReader, dt *parser.DataType) (any, error) {
+	var err error
+
+	if dt.FixedSizeSlice != nil {
+		f := mint.NewSliceCollection(make([]mint.MarshallerUnmarshallerValuer, dt.FixedSizeSlice.Size), true)
+		for i := range f.V {
+			f.V[i] = s.newValue(dt.Elem())
+		}
+
+		err = f.Unmarshall(dr)
+		if err != nil {
+			return nil, err
+		}
+
+		return values(f.V), nil
+	}
+
+	// slices grow as their elements are read, rather than trusting
+	// the declared length up front
+	f := mint.NewSliceCollection(nil, false)
+
+	err = f.ReadSize(dr)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.UnmarshallElements(dr, func() mint.MarshallerUnmarshallerValuer {
+		return s.newValue(dt.Elem())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values(f.V), nil
+}
+
+// values returns the values of each of muvs
+func values(muvs []mint.MarshallerUnmarshallerValuer) []any {
+
+	out := make([]any, len(muvs))
+	for i, e := range muvs {
+		out[i] = e.Value()
+	}
+
+	return out
+}
+
+// marshallMap writes v, a map of data type dt, to w.
+//
+// Entries are written in the order of their encoded keys, rather than
+// in go's random map order, so that the same map always produces the
+// same bytes
+func (s schema) marshallMap(w io.Writer, dt *parser.DataType, v any) error {
+	m, ok := v.(map[any]any)
+	if !ok && v != nil {
+		return ErrInvalidValue{Expected: dt.String(), Received: v}
+	}
+
+	type entry struct {
+		encoded []byte
+		k, v    mint.MarshallerUnmarshallerValuer
+	}
+
+	entries := make([]entry, 0, len(m))
+	for k, mv := range m {
+		seg := mint.KeySegment(k)
+
+		key := &value{s: s, dt: scalarType(dt.Map.Key), seg: seg, v: k}
+
+		buf := new(bytes.Buffer)
+
+		err := key.Marshall(buf)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry{
+			encoded: buf.Bytes(),
+			k:       key,
+			v:       &value{s: s, dt: dt.Map.Value, seg: seg, v: mv},
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].encoded, entries[j].encoded) < 0
+	})
+
+	muvs := make([]mint.MarshallerUnmarshallerValuer, 0, len(entries)*2)
+	for _, e := range entries {
+		muvs = append(muvs, e.k, e.v)
+	}
+
+	return mint.NewSliceCollection(muvs, false).Marshall(w)
+}
+
+// unmarshallMap reads a map of data type dt from dr via a
+// mint.MapCollection
+func (s schema) unmarshallMap(dr *mint.DecodeReader, dt *parser.DataType) (any, error) {
+	f := mint.NewMapCollection(nil)
+
+	err := f.ReadSize(dr)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.UnmarshallEntries(dr,
+		func() mint.MarshallerUnmarshallerValuer { return s.newValue(scalarType(dt.Map.Key)) },
+		func() mint.MarshallerUnmarshallerValuer { return s.newValue(dt.Map.Value) },
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[any]any, len(f.V))
+	for k, v := range f.V {
+		out[k.Value()] = v.Value()
+	}
+
+	return out, nil
+}
+
+// marshallEnum writes v, the name of a value of enum e, to w
+func marshallEnum(w io.Writer, e parser.Enum, v any) error {
+	name, _ := v.(string)
+
+	for i, ev := range e.Values {
+		if ev.Value.Key == name {
+			return mint.NewByteScalar(byte(i + 1)).Marshall(w)
+		}
+	}
+
+	return ErrInvalidValue{Expected: e.Name, Received: v}
+}
+
+// unmarshallEnum reads a value of enum e from dr, returning its name
+func unmarshallEnum(dr *mint.DecodeReader, e parser.Enum) (any, error) {
+	b, err := mint.ReadByte(dr)
+	if err != nil {
+		return nil, err
+	}
+
+	if b < 1 || int(b) > len(e.Values) {
+		return nil, errors.New("invalid value for type " + e.Name)
+	}
+
+	return e.Values[b-1].Value.Key, nil
+}
+
+// marshallUnion writes v, a Union, to w as a value of union u; its
+// discriminator, followed by the value of the variant which is set
+func (s schema) marshallUnion(w io.Writer, u parser.Union, v any) error {
+	uv, ok := v.(Union)
+	if !ok {
+		return ErrInvalidValue{Expected: u.Name, Received: v}
+	}
+
+	for _, variant := range u.Variants {
+		if variant.Name != uv.Variant {
+			continue
+		}
+
+		err := mint.NewByteScalar(byte(variant.Tag)).Marshall(w)
+		if err != nil {
+			return err
+		}
+
+		return wrapEncodeError(u.Name, variant.Name, s.marshallScalar(w, variant.Type, uv.Value))
+	}
+
+	return ErrUnknownVariant{Union: u.Name, Variant: uv.Variant}
+}
+
+// unmarshallUnion reads a value of union u from dr
+func (s schema) unmarshallUnion(dr *mint.DecodeReader, u parser.Union) (any, error) {
+	err := dr.Enter()
+	if err != nil {
+		return nil, err
+	}
+
+	defer dr.Leave()
+
+	b, err := mint.ReadByte(dr)
+	if err != nil {
+		return nil, mint.WrapDecodeError(dr, u.Name, "", err)
+	}
+
+	for _, variant := range u.Variants {
+		if variant.Tag != int(b) {
+			continue
+		}
+
+		v, err := s.unmarshallScalar(dr, variant.Type)
+		if err != nil {
+			return nil, mint.WrapDecodeError(dr, u.Name, variant.Name, err)
+		}
+
+		return Union{Variant: variant.Name, Value: v}, nil
+	}
+
+	return nil, mint.WrapDecodeError(dr, u.Name, "", errors.New("invalid variant for union "+u.Name))
+}
+
+// builtins holds each builtin scalar, as per parser.Scalars, along with
+// the go type it's represented as
+var builtins = map[string]string{
+	"string":   "string",
+	"datetime": "time.Time",
+	"uuid":     "uuid.UUID",
+	"int16":    "int16",
+	"int32":    "int32",
+	"int64":    "int64",
+	"float32":  "float32",
+	"float64":  "float64",
+	"byte":     "byte",
+	"bool":     "bool",
+}
+
+// toScalar wraps v, a value of builtin scalar t, in the corresponding
+// mint scalar, returning ErrInvalidValue where v isn't of the go type t
+// is represented as
+func toScalar(t string, v any) (m mint.MarshallerUnmarshallerValuer, err error) {
+	ok := true
+
+	switch t {
+	case "string":
+		var s string
+		s, ok = v.(string)
+		m = mint.NewStringScalar(s)
+
+	case "datetime":
+		var d time.Time
+		d, ok = v.(time.Time)
+		m = mint.NewDatetimeScalar(d)
+
+	case "uuid":
+		var u uuid.UUID
+		u, ok = v.(uuid.UUID)
+		m = mint.NewUuidScalar(u)
+
+	case "int16":
+		var i int16
+		i, ok = v.(int16)
+		m = mint.NewInt16Scalar(i)
+
+	case "int32":
+		var i int32
+		i, ok = v.(int32)
+		m = mint.NewInt32Scalar(i)
+
+	case "int64":
+		var i int64
+		i, ok = v.(int64)
+		m = mint.NewInt64Scalar(i)
+
+	case "float32":
+		var f float32
+		f, ok = v.(float32)
+		m = mint.NewFloat32Scalar(f)
+
+	case "float64":
+		var f float64
+		f, ok = v.(float64)
+		m = mint.NewFloat64Scalar(f)
+
+	case "byte":
+		var b byte
+		b, ok = v.(byte)
+		m = mint.NewByteScalar(b)
+
+	case "bool":
+		var b bool
+		b, ok = v.(bool)
+		m = mint.NewBoolScalar(b)
+	}
+
+	if !ok {
+		return nil, ErrInvalidValue{Expected: builtins[t], Received: v}
+	}
+
+	return
+}
+
+// newScalar returns the mint scalar for builtin scalar t, ready to be
+// unmarshalled into
+func newScalar(t string) mint.MarshallerUnmarshallerValuer {
+	switch t {
+	case "string":
+		return mint.NewStringScalar("")
+
+	case "datetime":
+		return mint.NewDatetimeScalar(time.Time{})
+
+	case "uuid":
+		return mint.NewUuidScalar(uuid.UUID{})
+
+	case "int16":
+		return mint.NewInt16Scalar(0)
+
+	case "int32":
+		return mint.NewInt32Scalar(0)
+
+	case "int64":
+		return mint.NewInt64Scalar(0)
+
+	case "float32":
+		return mint.NewFloat32Scalar(0)
+
+	case "float64":
+		return mint.NewFloat64Scalar(0)
+
+	case "byte":
+		return mint.NewByteScalar(0)
+	}
+
+	return mint.NewBoolScalar(false)
+}
@@ -0,0 +1,143 @@
+package mint
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferSize is the largest scratch buffer returned to the pool;
+// anything bigger is left for the garbage collector, so that one large
+// message doesn't pin its buffer in memory forever
+const maxPooledBufferSize = 1 << 20
+
+var scratchPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 512)
+
+		return &b
+	},
+}
+
+// Encoder writes a stream of values to an underlying io.Writer,
+// buffering writes and reusing scratch space across values.
+//
+// The first error an Encoder encounters is sticky; once a write has
+// failed every subsequent call returns that same error, and so callers
+// streaming many values need only check the error from Flush
+type Encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewEncoder returns an Encoder writing to w. Writes are buffered, and
+// so callers must call Flush once done
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w: bufio.NewWriter(w),
+	}
+}
+
+// Encode writes m, which may be any generated type, scalar, or
+// collection
+func (e *Encoder) Encode(m Marshaller) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	a, ok := m.(Appender)
+	if !ok {
+		e.err = m.Marshall(e.w)
+
+		return e.err
+	}
+
+	buf := scratchPool.Get().(*[]byte)
+
+	b, err := a.AppendMarshall((*buf)[:0])
+	if err == nil {
+		_, err = e.w.Write(b)
+	}
+
+	if cap(b) <= maxPooledBufferSize {
+		*buf = b[:0]
+		scratchPool.Put(buf)
+	}
+
+	e.err = err
+
+	return err
+}
+
+// Flush writes any buffered data to the underlying io.Writer
+func (e *Encoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	e.err = e.w.Flush()
+
+	return e.err
+}
+
+// Err returns the first error this Encoder encountered, if any
+func (e *Encoder) Err() error {
+	return e.err
+}
+
+// Decoder reads a stream of values from an underlying io.Reader,
+// buffering reads and enforcing DecodeOptions.
+//
+// Limits apply to each value decoded in turn, rather than to the stream
+// as a whole, so that a long lived Decoder isn't cut off by MaxTotalBytes.
+//
+// As with Encoder, the first error a Decoder encounters is sticky; a
+// stream which has failed part way through a value can't be resynchronised
+type Decoder struct {
+	r   *DecodeReader
+	err error
+}
+
+// NewDecoder returns a Decoder reading from r, with DefaultDecodeOptions
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderWithOptions(r, nil)
+}
+
+// NewDecoderWithOptions returns a Decoder reading from r, enforcing the
+// limits in o. A nil o uses DefaultDecodeOptions
+func NewDecoderWithOptions(r io.Reader, o *DecodeOptions) *Decoder {
+	return &Decoder{
+		r: NewDecodeReader(bufio.NewReader(r), o),
+	}
+}
+
+// Decode reads the next value into u, which may be any generated type,
+// scalar, or collection. Once the stream is exhausted, Decode returns
+// io.EOF
+func (d *Decoder) Decode(u Unmarshaller) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	d.r.base = d.r.offset
+	d.err = u.Unmarshall(d.r)
+
+	// A stream which ends cleanly between values is reported as a bare
+	// io.EOF, rather than as a truncated value
+	if d.r.offset == d.r.base && errors.Is(d.err, io.EOF) {
+		d.err = io.EOF
+	}
+
+	return d.err
+}
+
+// Offset returns the number of bytes read from the stream so far
+func (d *Decoder) Offset() int64 {
+	return d.r.Offset()
+}
+
+// Err returns the first error this Decoder encountered, if any
+func (d *Decoder) Err() error {
+	return d.err
+}
@@ -0,0 +1,165 @@
+package mint
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+type failingWriter struct {
+	remaining int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.remaining {
+		n := w.remaining
+		w.remaining = 0
+
+		return n, io.ErrShortWrite
+	}
+
+	w.remaining -= len(p)
+
+	return len(p), nil
+}
+
+func TestEncoderDecoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	enc := NewEncoder(buf)
+
+	for _, m := range []Marshaller{
+		NewStringScalar("Hello, World!"),
+		NewInt64Scalar(12345),
+		&customMUV{foo: "a", bar: 1, baz: true},
+		NewStringScalar(makeLongString()),
+	} {
+		err := enc.Encode(m)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+	}
+
+	err := enc.Flush()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	dec := NewDecoder(buf)
+
+	s := NewStringScalar("")
+	i := NewInt64Scalar(0)
+	c := new(customMUV)
+	l := NewStringScalar("")
+
+	for _, u := range []Unmarshaller{s, i, c, l} {
+		err = dec.Decode(u)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+	}
+
+	if s.Value() != "Hello, World!" {
+		t.Errorf("expected %q, received %q", "Hello, World!", s.Value())
+	}
+
+	if i.Value() != int64(12345) {
+		t.Errorf("expected 12345, received %v", i.Value())
+	}
+
+	if *c != (customMUV{foo: "a", bar: 1, baz: true}) {
+		t.Errorf("unexpected value %#v", c)
+	}
+
+	if l.Value() != makeLongString() {
+		t.Error("long string was not decoded exactly")
+	}
+
+	err = dec.Decode(s)
+	if err != io.EOF {
+		t.Errorf("expected io.EOF, received %#v", err)
+	}
+}
+
+func TestEncoder_StickyErrors(t *testing.T) {
+	enc := NewEncoder(&failingWriter{remaining: 10})
+
+	err := enc.Encode(NewStringScalar(makeLongString()))
+	if err == nil {
+		t.Fatal("expected error, received none")
+	}
+
+	for _, f := range []func() error{
+		func() error { return enc.Encode(NewInt64Scalar(1)) },
+		enc.Flush,
+		enc.Err,
+	} {
+		received := f()
+		if received != err {
+			t.Errorf("expected %#v, received %#v", err, received)
+		}
+	}
+}
+
+func TestDecoder_StickyErrors(t *testing.T) {
+	dec := NewDecoder(bytes.NewReader(AppendString(nil, "Hello, World!")[:10]))
+
+	err := dec.Decode(NewStringScalar(""))
+	if err == nil {
+		t.Fatal("expected error, received none")
+	}
+
+	var truncated ErrTruncated
+	if !errors.As(err, &truncated) {
+		t.Errorf("expected ErrTruncated, received %#v", err)
+	}
+
+	received := dec.Decode(NewInt64Scalar(0))
+	if received != err {
+		t.Errorf("expected %#v, received %#v", err, received)
+	}
+}
+
+func TestDecoder_LimitsPerValue(t *testing.T) {
+	b := AppendString(nil, "abc")
+	b = AppendString(b, "def")
+	b = AppendString(b, "ghijklmnop")
+
+	dec := NewDecoderWithOptions(bytes.NewReader(b), &DecodeOptions{MaxTotalBytes: 12})
+
+	for _, expect := range []string{"abc", "def"} {
+		s := NewStringScalar("")
+
+		err := dec.Decode(s)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if s.Value() != expect {
+			t.Errorf("expected %q, received %q", expect, s.Value())
+		}
+	}
+
+	err := dec.Decode(NewStringScalar(""))
+	if err == nil {
+		t.Fatal("expected error, received none")
+	}
+
+	var limit ErrLimitExceeded
+	if !errors.As(err, &limit) {
+		t.Errorf("expected ErrLimitExceeded, received %#v", err)
+	}
+}
+
+func TestEncoder_Allocations(t *testing.T) {
+	enc := NewEncoder(io.Discard)
+	s := NewStringScalar("Hello, World!")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = enc.Encode(s)
+	})
+
+	if allocs != 0 {
+		t.Errorf("expected no allocations, received %v", allocs)
+	}
+}
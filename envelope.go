@@ -0,0 +1,292 @@
+package mint
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// maxTypeNameBytes bounds the type name read from an envelope, which
+// is far shorter than any string DecodeOptions would otherwise allow
+const maxTypeNameBytes = 1 << 10
+
+// Fingerprinter is implemented by generated types, returning the name of
+// the type, qualified by its package where it has one, along with the
+// fingerprint of the schema it was generated from, which changes
+// whenever the type's encoding does
+type Fingerprinter interface {
+	TypeName() string
+	Fingerprint() uint64
+}
+
+// EnvelopeMarshaller is implemented by generated types, which may be
+// written with WriteEnvelope
+type EnvelopeMarshaller interface {
+	Marshaller
+	Fingerprinter
+}
+
+// EnvelopeUnmarshaller is implemented by pointers to generated types,
+// which may be read with ReadEnvelope
+type EnvelopeUnmarshaller interface {
+	Unmarshaller
+	Fingerprinter
+}
+
+// Flags set in the header of an envelope, describing how its body has
+// been written; the compression algorithm, if any, is held in the bits
+// covered by envelopeCompressionMask
+const (
+	envelopeChecksum        byte = 1 << 0
+	envelopeCompressionMask byte = 0b11 << 1
+
+	envelopeCompressionShift = 1
+	envelopeKnownFlags       = envelopeChecksum | envelopeCompressionMask
+)
+
+// castagnoli is the CRC32C table with which envelope bodies are checksummed
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// EnvelopeOptions sets how the body of an envelope is written
+type EnvelopeOptions struct {
+	// Checksum appends a CRC32C of the body, as written, allowing
+	// ReadEnvelope to detect corruption
+	Checksum bool
+
+	// Compression compresses the body
+	Compression Compression
+}
+
+// flags returns the header flags describing o
+func (o EnvelopeOptions) flags() byte {
+	f := byte(o.Compression) << envelopeCompressionShift
+	if o.Checksum {
+		f |= envelopeChecksum
+	}
+
+	return f
+}
+
+// WriteEnvelope writes m to w, preceded by its type name and fingerprint,
+// so that ReadEnvelope can refuse data written for a different type, or
+// for a different version of the same type.
+//
+// The envelope is written with a single call to Write, and so nothing is
+// written should m fail to marshall
+func WriteEnvelope(w io.Writer, m EnvelopeMarshaller) error {
+	return WriteEnvelopeWithOptions(w, m, nil)
+}
+
+// WriteEnvelopeWithOptions writes m to w as per WriteEnvelope, optionally
+// checksumming and compressing it as per o. A nil o writes the body as
+// is, exactly as WriteEnvelope does
+func WriteEnvelopeWithOptions(w io.Writer, m EnvelopeMarshaller, o *EnvelopeOptions) error {
+	var opts EnvelopeOptions
+	if o != nil {
+		opts = *o
+	}
+
+	flags := opts.flags()
+	if flags&^envelopeKnownFlags != 0 || opts.Compression > Gzip {
+		return ErrUnsupportedEnvelope{
+			Flags: flags,
+		}
+	}
+
+	b := AppendString(nil, m.TypeName())
+	b = binary.LittleEndian.AppendUint64(b, m.Fingerprint())
+	b = AppendByte(b, flags)
+
+	// Bodies without flags are written, and read, as a stream
+	if flags == 0 {
+		b, err := appendMarshall(b, m)
+		if err != nil {
+			return err
+		}
+
+		return writeBytes(w, b)
+	}
+
+	body, err := appendMarshall(nil, m)
+	if err != nil {
+		return err
+	}
+
+	if opts.Compression != NoCompression {
+		body, err = compress(body, opts.Compression)
+		if err != nil {
+			return err
+		}
+	}
+
+	if int64(len(body)) > math.MaxUint32 {
+		return ErrLimitExceeded{
+			Limit:    "envelope body bytes",
+			Max:      math.MaxUint32,
+			Received: int64(len(body)),
+		}
+	}
+
+	b = AppendUint32(b, uint32(len(body)))
+	b = append(b, body...)
+
+	if opts.Checksum {
+		b = AppendUint32(b, crc32.Checksum(body, castagnoli))
+	}
+
+	return writeBytes(w, b)
+}
+
+// ReadEnvelope reads an envelope, as written by WriteEnvelope or
+// WriteEnvelopeWithOptions, from r into u, returning ErrEnvelopeMismatch,
+// without decoding anything further, where the envelope holds a different
+// type, or was written from a different version of the schema u was
+// generated from.
+//
+// Where the envelope is checksummed, its body is read in full and its
+// checksum verified, returning ErrChecksum should it not match, before
+// anything is decoded, and likewise a compressed body is decompressed in
+// full before it's decoded. Either way, the body is held in memory, and
+// so may be no larger than the MaxFrameBytes of the DecodeOptions in
+// force, whether compressed or not, and ErrUnconsumedEnvelope is
+// returned where u doesn't take up the whole of it.
+//
+// As with generated Unmarshall functions, limits may be set by passing
+// a DecodeReader as r
+func ReadEnvelope(r io.Reader, u EnvelopeUnmarshaller) error {
+	dr := AsDecodeReader(r)
+
+	name, fingerprint, err := readEnvelopeHeader(dr)
+	if err != nil {
+		return err
+	}
+
+	if name != u.TypeName() || fingerprint != u.Fingerprint() {
+		return ErrEnvelopeMismatch{
+			ExpectedType:        u.TypeName(),
+			ExpectedFingerprint: u.Fingerprint(),
+			ReceivedType:        name,
+			ReceivedFingerprint: fingerprint,
+		}
+	}
+
+	flags, err := ReadByte(dr)
+	if err != nil {
+		return err
+	}
+
+	if flags == 0 {
+		return u.Unmarshall(dr)
+	}
+
+	body, err := readEnvelopeBody(dr, flags)
+	if err != nil {
+		return err
+	}
+
+	d := NewBytesDecoder(body, &dr.opts)
+
+	if du, ok := u.(DecoderUnmarshaller); ok {
+		err = du.UnmarshallDecoder(d)
+	} else {
+		err = u.Unmarshall(d)
+	}
+
+	if err == nil && d.Remaining() > 0 {
+		err = ErrUnconsumedEnvelope{
+			Size: int64(len(body)),
+			Read: d.Offset(),
+		}
+	}
+
+	return err
+}
+
+// readEnvelopeHeader reads the type name and fingerprint which precede
+// the body of an envelope
+func readEnvelopeHeader(dr *DecodeReader) (name string, fingerprint uint64, err error) {
+	b, err := readScratch(dr, 8)
+	if err != nil {
+		return
+	}
+
+	l := int64(binary.LittleEndian.Uint64(b))
+	if l < 0 || l > maxTypeNameBytes {
+		err = ErrLimitExceeded{
+			Limit:    "type name bytes",
+			Max:      maxTypeNameBytes,
+			Received: l,
+		}
+
+		return
+	}
+
+	name, err = readString(dr, l)
+	if err != nil {
+		return
+	}
+
+	b, err = readScratch(dr, 8)
+	if err != nil {
+		return
+	}
+
+	fingerprint = binary.LittleEndian.Uint64(b)
+
+	return
+}
+
+// readEnvelopeBody reads the body of an envelope with the header flags
+// flags, verifying its checksum and decompressing it, as flags dictate
+func readEnvelopeBody(dr *DecodeReader, flags byte) (body []byte, err error) {
+	compression := Compression((flags & envelopeCompressionMask) >> envelopeCompressionShift)
+	if flags&^envelopeKnownFlags != 0 || compression > Gzip {
+		return nil, ErrUnsupportedEnvelope{
+			Flags: flags,
+		}
+	}
+
+	l, err := ReadUint32(dr)
+	if err != nil {
+		return
+	}
+
+	if int64(l) > dr.opts.MaxFrameBytes {
+		return nil, ErrLimitExceeded{
+			Limit:    "envelope body bytes",
+			Max:      dr.opts.MaxFrameBytes,
+			Received: int64(l),
+		}
+	}
+
+	body = make([]byte, l)
+
+	err = readFull(dr, body)
+	if err != nil {
+		return
+	}
+
+	if flags&envelopeChecksum != 0 {
+		var expect uint32
+
+		expect, err = ReadUint32(dr)
+		if err != nil {
+			return
+		}
+
+		received := crc32.Checksum(body, castagnoli)
+		if received != expect {
+			return nil, ErrChecksum{
+				Expected: expect,
+				Received: received,
+			}
+		}
+	}
+
+	if compression != NoCompression {
+		body, err = decompress(body, compression, dr.opts.MaxFrameBytes)
+	}
+
+	return
+}
@@ -0,0 +1,309 @@
+package mint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// envelopedMUV is a customMUV with the type name and fingerprint which
+// would otherwise be generated
+type envelopedMUV struct {
+	customMUV
+
+	name        string
+	fingerprint uint64
+}
+
+func (e envelopedMUV) TypeName() string {
+	return e.name
+}
+
+func (e envelopedMUV) Fingerprint() uint64 {
+	return e.fingerprint
+}
+
+func TestWriteEnvelope(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	in := envelopedMUV{
+		customMUV:   customMUV{foo: "a", bar: 1, baz: true},
+		name:        "test.Custom",
+		fingerprint: 0x0123456789abcdef,
+	}
+
+	err := WriteEnvelope(buf, in)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expect := AppendString(nil, "test.Custom")
+	expect = append(expect, 0xef, 0xcd, 0xab, 0x89, 0x67, 0x45, 0x23, 0x01)
+
+	if !bytes.HasPrefix(buf.Bytes(), expect) {
+		t.Errorf("expected header %#v, received %#v", expect, buf.Bytes()[:len(expect)])
+	}
+
+	out := &envelopedMUV{name: in.name, fingerprint: in.fingerprint}
+
+	err = ReadEnvelope(buf, out)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if out.customMUV != in.customMUV {
+		t.Errorf("expected %#v, received %#v", in.customMUV, out.customMUV)
+	}
+}
+
+func TestReadEnvelope_Errors(t *testing.T) {
+	in := envelopedMUV{
+		customMUV:   customMUV{foo: "a", bar: 1, baz: true},
+		name:        "test.Custom",
+		fingerprint: 1,
+	}
+
+	buf := new(bytes.Buffer)
+
+	err := WriteEnvelope(buf, in)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	valid := buf.Bytes()
+
+	for _, test := range []struct {
+		name        string
+		input       []byte
+		typeName    string
+		fingerprint uint64
+		expect      any
+		expectMsg   string
+	}{
+		{"Different type", valid, "test.Other", 1, new(ErrEnvelopeMismatch), "envelope holds test.Custom, expected test.Other"},
+		{"Different fingerprint", valid, "test.Custom", 2, new(ErrEnvelopeMismatch), "envelope holds test.Custom with fingerprint 0x0000000000000001, expected fingerprint 0x0000000000000002"},
+		{"Empty input", nil, "test.Custom", 1, new(ErrTruncated), ""},
+		{"Truncated type name", valid[:10], "test.Custom", 1, new(ErrTruncated), ""},
+		{"Truncated fingerprint", valid[:22], "test.Custom", 1, new(ErrTruncated), ""},
+		{"Truncated flags", valid[:27], "test.Custom", 1, new(ErrTruncated), ""},
+		{"Oversized type name", binary.LittleEndian.AppendUint64(nil, maxTypeNameBytes+1), "test.Custom", 1, new(ErrLimitExceeded), ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			out := &envelopedMUV{name: test.typeName, fingerprint: test.fingerprint}
+
+			err := ReadEnvelope(bytes.NewReader(test.input), out)
+
+			if !errors.As(err, test.expect) {
+				t.Fatalf("expected %T, received %#v", test.expect, err)
+			}
+
+			if test.expectMsg != "" && err.Error() != test.expectMsg {
+				t.Errorf("expected %q, received %q", test.expectMsg, err.Error())
+			}
+
+			// Nothing is decoded from an envelope which doesn't match
+			if out.customMUV != (customMUV{}) {
+				t.Errorf("expected nothing to be decoded, received %#v", out.customMUV)
+			}
+		})
+	}
+}
+
+func TestWriteEnvelopeWithOptions(t *testing.T) {
+	in := envelopedMUV{
+		customMUV:   customMUV{foo: makeLongString(), bar: 1, baz: true},
+		name:        "test.Custom",
+		fingerprint: 1,
+	}
+
+	uncompressed := new(bytes.Buffer)
+
+	err := WriteEnvelope(uncompressed, in)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	for _, test := range []struct {
+		name        string
+		opts        *EnvelopeOptions
+		expectFlags byte
+	}{
+		{"No options", nil, 0},
+		{"Checksum", &EnvelopeOptions{Checksum: true}, 0b001},
+		{"Flate", &EnvelopeOptions{Compression: Flate}, 0b010},
+		{"Gzip", &EnvelopeOptions{Compression: Gzip}, 0b100},
+		{"Checksummed gzip", &EnvelopeOptions{Checksum: true, Compression: Gzip}, 0b101},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+
+			err := WriteEnvelopeWithOptions(buf, in, test.opts)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			// The flags follow the 19 byte type name, and 8 byte
+			// fingerprint
+			if buf.Bytes()[27] != test.expectFlags {
+				t.Errorf("expected flags %#02x, received %#02x", test.expectFlags, buf.Bytes()[27])
+			}
+
+			if test.opts != nil && test.opts.Compression != NoCompression && buf.Len() >= uncompressed.Len() {
+				t.Errorf("expected compressed envelope to be smaller than %d bytes, received %d", uncompressed.Len(), buf.Len())
+			}
+
+			out := &envelopedMUV{name: in.name, fingerprint: in.fingerprint}
+
+			err = ReadEnvelope(buf, out)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if out.customMUV != in.customMUV {
+				t.Error("envelope was not decoded exactly")
+			}
+		})
+	}
+}
+
+func TestReadEnvelope_Integrity(t *testing.T) {
+	in := envelopedMUV{
+		customMUV:   customMUV{foo: "Hello, World!", bar: 1, baz: true},
+		name:        "test.Custom",
+		fingerprint: 1,
+	}
+
+	write := func(m envelopedMUV, o *EnvelopeOptions) []byte {
+		buf := new(bytes.Buffer)
+
+		err := WriteEnvelopeWithOptions(buf, m, o)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		return buf.Bytes()
+	}
+
+	// corrupt flips a bit in the byte at i, which is counted from
+	// the end of b where negative
+	corrupt := func(b []byte, i int) []byte {
+		b = bytes.Clone(b)
+		if i < 0 {
+			i += len(b)
+		}
+
+		b[i] ^= 0x10
+
+		return b
+	}
+
+	checksummed := write(in, &EnvelopeOptions{Checksum: true})
+	compressed := write(in, &EnvelopeOptions{Checksum: true, Compression: Flate})
+
+	// long compresses down to a fraction of its size
+	long := in
+	long.foo = makeLongString()
+	bomb := write(long, &EnvelopeOptions{Compression: Gzip})
+
+	for _, test := range []struct {
+		name   string
+		input  []byte
+		opts   *DecodeOptions
+		expect any
+		limit  string
+	}{
+		{"Corrupt body", corrupt(checksummed, 40), nil, new(ErrChecksum), ""},
+		{"Corrupt checksum", corrupt(checksummed, -1), nil, new(ErrChecksum), ""},
+		{"Corrupt compressed body", corrupt(compressed, 34), nil, new(ErrChecksum), ""},
+		{"Truncated body", checksummed[:40], nil, new(ErrTruncated), ""},
+		{"Truncated checksum", checksummed[:len(checksummed)-2], nil, new(ErrTruncated), ""},
+		{"Unknown flags", corrupt(checksummed, 27), nil, new(ErrUnsupportedEnvelope), ""},
+		{"Unknown compression", append(bytes.Clone(checksummed[:27]), 0b110), nil, new(ErrUnsupportedEnvelope), ""},
+		{"Body too large", checksummed, &DecodeOptions{MaxFrameBytes: 8}, new(ErrLimitExceeded), "envelope body bytes"},
+		{"Body too large once decompressed", bomb, &DecodeOptions{MaxFrameBytes: 64 << 10}, new(ErrLimitExceeded), "decompressed bytes"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			out := &envelopedMUV{name: in.name, fingerprint: in.fingerprint}
+
+			err := ReadEnvelope(NewDecodeReader(bytes.NewReader(test.input), test.opts), out)
+			if !errors.As(err, test.expect) {
+				t.Fatalf("expected %T, received %#v", test.expect, err)
+			}
+
+			if le, ok := test.expect.(*ErrLimitExceeded); ok && le.Limit != test.limit {
+				t.Errorf("expected %q limit to be exceeded, received %q", test.limit, le.Limit)
+			}
+
+			if out.customMUV != (customMUV{}) {
+				t.Errorf("expected nothing to be decoded, received %#v", out.customMUV)
+			}
+		})
+	}
+}
+
+// paddedMUV is an envelopedMUV which writes more than it reads
+type paddedMUV struct {
+	envelopedMUV
+}
+
+func (p paddedMUV) Marshall(w io.Writer) error {
+	err := p.customMUV.Marshall(w)
+	if err != nil {
+		return err
+	}
+
+	return NewStringScalar("trailing").Marshall(w)
+}
+
+func TestReadEnvelope_Unconsumed(t *testing.T) {
+	in := paddedMUV{
+		envelopedMUV: envelopedMUV{
+			customMUV:   customMUV{foo: "Hello, World!", bar: 1, baz: true},
+			name:        "test.Custom",
+			fingerprint: 1,
+		},
+	}
+
+	for _, test := range []struct {
+		name string
+		opts *EnvelopeOptions
+	}{
+		{"Checksummed", &EnvelopeOptions{Checksum: true}},
+		{"Compressed", &EnvelopeOptions{Compression: Flate}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+
+			err := WriteEnvelopeWithOptions(buf, in, test.opts)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			err = ReadEnvelope(buf, &envelopedMUV{name: in.name, fingerprint: in.fingerprint})
+
+			var ue ErrUnconsumedEnvelope
+			if !errors.As(err, &ue) {
+				t.Fatalf("expected ErrUnconsumedEnvelope, received %#v", err)
+			}
+
+			if ue.Size-ue.Read != 16 {
+				t.Errorf("expected 16 bytes to be left over, received %d of %d", ue.Size-ue.Read, ue.Size)
+			}
+		})
+	}
+}
+
+func TestWriteEnvelopeWithOptions_UnknownCompression(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	err := WriteEnvelopeWithOptions(buf, envelopedMUV{}, &EnvelopeOptions{Compression: 7})
+	if !errors.As(err, new(ErrUnsupportedEnvelope)) {
+		t.Errorf("expected ErrUnsupportedEnvelope, received %#v", err)
+	}
+
+	if buf.Len() > 0 {
+		t.Errorf("expected nothing to be written, received %#v", buf.Bytes())
+	}
+}
@@ -1,6 +1,8 @@
 package mint
 
 import (
+	"bytes"
+	"errors"
 	"testing"
 	"time"
 )
@@ -30,3 +32,80 @@ func TestValidationErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestWrapDecodeError(t *testing.T) {
+	base := errors.New("some error")
+
+	for _, test := range []struct {
+		name         string
+		err          func() error
+		expectPath   string
+		expectOffset int64
+	}{
+		{"nil error stays nil", func() error { return WrapDecodeError(nil, "Foo", "Bar", nil) }, "", 0},
+		{"plain error is wrapped", func() error { return WrapDecodeError(nil, "Foo", "Bar", base) }, "Foo.Bar", 0},
+		{"nested types build a path", func() error {
+			return WrapDecodeError(nil, "Outer", "Inner", WrapDecodeError(nil, "Inner", "Field", base))
+		}, "Outer.Inner.Field", 0},
+		{"collections index fields", func() error {
+			return WrapDecodeError(nil, "Foo", "Tags", WrapDecodeError(nil, "", "[3]", base))
+		}, "Foo.Tags[3]", 0},
+		{"collections of types index fields", func() error {
+			return WrapDecodeError(nil, "Foo", "Bars", WrapDecodeError(nil, "", "[3]", WrapDecodeError(nil, "Bar", "Baz", base)))
+		}, "Foo.Bars[3].Baz", 0},
+		{"offsets are taken from decode readers", func() error {
+			dr := NewDecodeReader(bytes.NewReader(make([]byte, 10)), nil)
+			_, _ = dr.Read(make([]byte, 6))
+
+			return WrapDecodeError(dr, "Foo", "Bar", base)
+		}, "Foo.Bar", 6},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.err()
+			if test.expectPath == "" {
+				if err != nil {
+					t.Errorf("unexpected error %#v", err)
+				}
+
+				return
+			}
+
+			var de ErrDecode
+			if !errors.As(err, &de) {
+				t.Fatalf("expected ErrDecode, received %#v", err)
+			}
+
+			if test.expectPath != de.Path() {
+				t.Errorf("expected %q, received %q", test.expectPath, de.Path())
+			}
+
+			if test.expectOffset != de.Offset {
+				t.Errorf("expected %d, received %d", test.expectOffset, de.Offset)
+			}
+
+			if !errors.Is(err, base) {
+				t.Errorf("expected wrapped error to be retained")
+			}
+
+			t.Log(err)
+		})
+	}
+}
+
+func TestSortInvalidElements(t *testing.T) {
+	errs := []error{
+		ErrInvalidElement{Path: `Foo["c"]`, Err: errors.New("key")},
+		ErrInvalidElement{Path: `Foo["a"]`, Err: errors.New("a")},
+		ErrInvalidElement{Path: `Foo["c"]`, Err: errors.New("value")},
+		ErrInvalidElement{Path: `Foo["b"]`, Err: errors.New("b")},
+	}
+
+	SortInvalidElements(errs)
+
+	expect := []string{`Foo["a"]: a`, `Foo["b"]: b`, `Foo["c"]: key`, `Foo["c"]: value`}
+	for i, err := range errs {
+		if err.Error() != expect[i] {
+			t.Errorf("%d: expected %q, received %q", i, expect[i], err.Error())
+		}
+	}
+}
@@ -2,6 +2,8 @@ package mint
 
 import (
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 )
 
@@ -33,3 +35,254 @@ func ValidationErrors(t string, errs []error) error {
 		errs: errs,
 	}
 }
+
+// ErrInvalidElement wraps an error returned when validating an element
+// of a collection, recording the path to that element, such as Points[3]
+type ErrInvalidElement struct {
+	Path string
+	Err  error
+}
+
+func (e ErrInvalidElement) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e ErrInvalidElement) Unwrap() error {
+	return e.Err
+}
+
+// SortInvalidElements sorts errs, the errors returned when validating
+// the elements of a map, by the path of each ErrInvalidElement, so that
+// validating the same map always returns errors in the same order
+func SortInvalidElements(errs []error) {
+	if len(errs) < 2 {
+		return
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return invalidElementPath(errs[i]) < invalidElementPath(errs[j])
+	})
+}
+
+// invalidElementPath returns the path of err where it is an
+// ErrInvalidElement, or else its message
+func invalidElementPath(err error) string {
+	if ie, ok := err.(ErrInvalidElement); ok {
+		return ie.Path
+	}
+
+	return err.Error()
+}
+
+// ErrLimitExceeded is returned when decoding input which would breach
+// one of the limits set in DecodeOptions
+type ErrLimitExceeded struct {
+	Limit    string
+	Max      int64
+	Received int64
+}
+
+func (e ErrLimitExceeded) Error() string {
+	return fmt.Sprintf("%s limit exceeded: received %d, maximum is %d", e.Limit, e.Received, e.Max)
+}
+
+// ErrTruncated is returned when input runs out part way through a value.
+//
+// Offset is the position at which data ran out; when decoding through a
+// DecodeReader (as generated code does) this is the offset into the
+// stream, otherwise it is relative to the start of the value being read.
+//
+// ErrTruncated wraps io.EOF where no bytes of the value could be read, and
+// io.ErrUnexpectedEOF otherwise
+type ErrTruncated struct {
+	Offset   int64
+	Read     int64
+	Expected int64
+
+	err error
+}
+
+func (e ErrTruncated) Error() string {
+	return fmt.Sprintf("input truncated at offset %d: expected %d bytes, received %d", e.Offset, e.Expected, e.Read)
+}
+
+func (e ErrTruncated) Unwrap() error {
+	return e.err
+}
+
+// ErrInvalidPresence is returned when a presence bitmap marks fields
+// as present which the type being decoded doesn't have, which is
+// usually a sign of corrupt input, or of decoding the wrong type
+type ErrInvalidPresence struct {
+	Fields int
+	Bitmap uint64
+}
+
+func (e ErrInvalidPresence) Error() string {
+	return fmt.Sprintf("invalid presence bitmap %#x: type has %d optional fields", e.Bitmap, e.Fields)
+}
+
+// ErrUnconsumedFrame is returned when a value decoded from a frame
+// doesn't take up the whole of that frame, which is usually a sign of
+// decoding the wrong type
+type ErrUnconsumedFrame struct {
+	Size int64
+	Read int64
+}
+
+func (e ErrUnconsumedFrame) Error() string {
+	return fmt.Sprintf("frame of %d bytes not fully decoded: read %d bytes", e.Size, e.Read)
+}
+
+// ErrUnconsumedEnvelope is returned when a value decoded from the
+// checksummed or compressed body of an envelope doesn't take up the
+// whole of that body, which is usually a sign of decoding the wrong type
+type ErrUnconsumedEnvelope struct {
+	Size int64
+	Read int64
+}
+
+func (e ErrUnconsumedEnvelope) Error() string {
+	return fmt.Sprintf("envelope body of %d bytes not fully decoded: read %d bytes", e.Size, e.Read)
+}
+
+// ErrEnvelopeMismatch is returned when an envelope holds a different
+// type to the one being read, or the same type written from a different
+// version of its schema
+type ErrEnvelopeMismatch struct {
+	ExpectedType        string
+	ExpectedFingerprint uint64
+	ReceivedType        string
+	ReceivedFingerprint uint64
+}
+
+func (e ErrEnvelopeMismatch) Error() string {
+	if e.ExpectedType != e.ReceivedType {
+		return fmt.Sprintf("envelope holds %s, expected %s", e.ReceivedType, e.ExpectedType)
+	}
+
+	return fmt.Sprintf("envelope holds %s with fingerprint %#016x, expected fingerprint %#016x", e.ReceivedType, e.ReceivedFingerprint, e.ExpectedFingerprint)
+}
+
+// ErrUnsupportedEnvelope is returned when the header of an envelope
+// sets flags which aren't understood, such as an unknown compression
+// algorithm, which usually means it was written by a newer version of
+// mint, or is corrupt
+type ErrUnsupportedEnvelope struct {
+	Flags byte
+}
+
+func (e ErrUnsupportedEnvelope) Error() string {
+	return fmt.Sprintf("envelope flags %#02x are unsupported", e.Flags)
+}
+
+// ErrChecksum is returned when the body of an envelope doesn't match
+// the checksum written alongside it, which means it has been corrupted
+// since it was written.
+//
+// Expected is the checksum written in the envelope, and Received that
+// of the body as read
+type ErrChecksum struct {
+	Expected uint32
+	Received uint32
+}
+
+func (e ErrChecksum) Error() string {
+	return fmt.Sprintf("checksum mismatch: expected %#08x, received %#08x", e.Expected, e.Received)
+}
+
+// ErrDecode wraps an error encountered while decoding, recording where
+// in a message the error occurred.
+//
+// Type is the outermost type being decoded, and Field the path from that
+// type to the failing value, such as Location.Tags[3]
+type ErrDecode struct {
+	Type   string
+	Field  string
+	Offset int64
+	Err    error
+}
+
+// Path returns the full path to the failing value, such as
+// WeatherForecast.Location.Tags[3]
+func (e ErrDecode) Path() string {
+	if e.Type == "" {
+		return e.Field
+	}
+
+	return joinFieldPath(e.Type, e.Field)
+}
+
+func (e ErrDecode) Error() string {
+	return fmt.Sprintf("error decoding %s at offset %d: %v", e.Path(), e.Offset, e.Err)
+}
+
+func (e ErrDecode) Unwrap() error {
+	return e.Err
+}
+
+// WrapDecodeError adds context to an error returned while decoding
+// field (or element) f of type t from r.
+//
+// Where err is already an ErrDecode, f is prepended to its path and t
+// replaces its type, thus building a full path as errors are returned
+// up through nested types. Collections pass an empty t.
+//
+// WrapDecodeError returns nil when err is nil
+func WrapDecodeError(r io.Reader, t, f string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if de, ok := err.(ErrDecode); ok {
+		de.Type = t
+		de.Field = joinFieldPath(f, de.Field)
+
+		return de
+	}
+
+	var offset int64
+	if o, ok := r.(offsetter); ok {
+		offset = o.Offset()
+	}
+
+	return ErrDecode{
+		Type:   t,
+		Field:  f,
+		Offset: offset,
+		Err:    err,
+	}
+}
+
+// joinFieldPath joins two path segments, eliding the separator where
+// the latter is an index, such as Tags[3]
+func joinFieldPath(a, b string) string {
+	switch {
+	case a == "":
+		return b
+
+	case b == "":
+		return a
+
+	case strings.HasPrefix(b, "["):
+		return a + b
+	}
+
+	return a + "." + b
+}
+
+// IndexSegment describes element i of a slice or array as a path
+// segment for WrapDecodeError, such as [3]
+func IndexSegment(i int) string {
+	return fmt.Sprintf("[%d]", i)
+}
+
+// KeySegment describes the map entry with key k as a path segment
+// for WrapDecodeError, such as ["some-key"]
+func KeySegment(k any) string {
+	if s, ok := k.(string); ok {
+		return fmt.Sprintf("[%q]", s)
+	}
+
+	return fmt.Sprintf("[%v]", k)
+}
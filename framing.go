@@ -0,0 +1,194 @@
+package mint
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// FrameHeaderSize is the size of the length which precedes each frame
+const FrameHeaderSize = 4
+
+// FrameWriter writes values to an underlying io.Writer, each in a frame
+// of its own, prefixed with its length, so that a stream of values can
+// be split back up without decoding them; see FrameReader.
+//
+// Each frame is written with a single call to Write. A FrameWriter is not
+// safe for concurrent use
+type FrameWriter struct {
+	w   io.Writer
+	max int64
+	buf []byte
+}
+
+// NewFrameWriter returns a FrameWriter writing to w, refusing to write
+// frames larger than max bytes. A max of zero uses the MaxFrameBytes of
+// DefaultDecodeOptions, so that frames are never larger than a FrameReader
+// accepts by default, and a max larger than a frame header can describe
+// is lowered to math.MaxUint32
+func NewFrameWriter(w io.Writer, max int64) *FrameWriter {
+	switch {
+	case max == 0:
+		max = DefaultDecodeOptions.MaxFrameBytes
+
+	case max > math.MaxUint32:
+		max = math.MaxUint32
+	}
+
+	return &FrameWriter{
+		w:   w,
+		max: max,
+	}
+}
+
+// WriteFrame writes m, which may be any generated type, scalar, or
+// collection, as a single frame.
+//
+// Should m fail to marshall, or be larger than the maximum frame size,
+// nothing is written, and the FrameWriter may carry on being used
+func (f *FrameWriter) WriteFrame(m Marshaller) (err error) {
+	b, err := appendMarshall(AppendUint32(f.buf[:0], 0), m)
+	if err != nil {
+		return
+	}
+
+	// keep hold of the buffer for the next frame, unless it has grown
+	// so large that it'd pin a lot of memory
+	if cap(b) <= maxPooledBufferSize {
+		f.buf = b[:0]
+	}
+
+	l := int64(len(b) - FrameHeaderSize)
+	if l > f.max {
+		return ErrLimitExceeded{
+			Limit:    "frame bytes",
+			Max:      f.max,
+			Received: l,
+		}
+	}
+
+	binary.LittleEndian.PutUint32(b, uint32(l))
+
+	return writeBytes(f.w, b)
+}
+
+// FrameReader reads values, each in a frame of its own, as written by
+// a FrameWriter, from an underlying io.Reader.
+//
+// Each frame is read in full before it's decoded, and so a value which
+// fails to decode, or to validate, doesn't stop the FrameReader from
+// reading the frames which follow it. Where the stream itself is broken,
+// such as a frame being truncated or too large, that error is sticky,
+// and every subsequent call returns it. A FrameReader is not safe for
+// concurrent use
+type FrameReader struct {
+	r    io.Reader
+	opts DecodeOptions
+	buf  []byte
+	err  error
+}
+
+// NewFrameReader returns a FrameReader reading from r, with
+// DefaultDecodeOptions
+func NewFrameReader(r io.Reader) *FrameReader {
+	return NewFrameReaderWithOptions(r, nil)
+}
+
+// NewFrameReaderWithOptions returns a FrameReader reading from r,
+// enforcing the limits in o, including MaxFrameBytes, to each frame.
+// A nil o uses DefaultDecodeOptions
+func NewFrameReaderWithOptions(r io.Reader, o *DecodeOptions) *FrameReader {
+	opts := DefaultDecodeOptions
+	if o != nil {
+		opts = o.withDefaults()
+	}
+
+	return &FrameReader{
+		r:    r,
+		opts: opts,
+	}
+}
+
+// ReadFrame reads the next frame into u, which may be any generated type,
+// scalar, or collection, returning ErrUnconsumedFrame where u doesn't
+// take up the whole frame. Once the stream is exhausted, ReadFrame
+// returns io.EOF
+func (f *FrameReader) ReadFrame(u Unmarshaller) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	b, err := f.next()
+	if err != nil {
+		f.err = err
+
+		return err
+	}
+
+	d := NewBytesDecoder(b, &f.opts)
+
+	if du, ok := u.(DecoderUnmarshaller); ok {
+		err = du.UnmarshallDecoder(d)
+	} else {
+		err = u.Unmarshall(d)
+	}
+
+	if err == nil && d.Remaining() > 0 {
+		err = ErrUnconsumedFrame{
+			Size: int64(len(b)),
+			Read: d.Offset(),
+		}
+	}
+
+	return err
+}
+
+// next reads the next frame, returning its contents in a buffer
+// which is only valid until the next call
+func (f *FrameReader) next() ([]byte, error) {
+	var h [FrameHeaderSize]byte
+
+	n, err := io.ReadFull(f.r, h[:])
+	if err != nil {
+		// A stream which ends cleanly between frames is reported
+		// as a bare io.EOF, rather than as a truncated frame
+		if n == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+
+		return nil, truncatedErr(f.r, err, int64(n), FrameHeaderSize)
+	}
+
+	l := int64(binary.LittleEndian.Uint32(h[:]))
+	if l > f.opts.MaxFrameBytes {
+		return nil, ErrLimitExceeded{
+			Limit:    "frame bytes",
+			Max:      f.opts.MaxFrameBytes,
+			Received: l,
+		}
+	}
+
+	// as with FrameWriter, only buffers of a reasonable size are kept
+	// for the next frame
+	b := f.buf
+	if int64(cap(b)) < l {
+		b = make([]byte, l)
+
+		if l <= maxPooledBufferSize {
+			f.buf = b
+		}
+	}
+
+	b = b[:l]
+
+	n, err = io.ReadFull(f.r, b)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, truncatedErr(f.r, err, int64(n), l)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,154 @@
+package mint
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestFrameWriterReader(t *testing.T) {
+	buf := new(bytes.Buffer)
+	fw := NewFrameWriter(buf, 0)
+
+	for _, m := range []Marshaller{
+		NewStringScalar("Hello, World!"),
+		NewInt64Scalar(12345),
+		&customMUV{foo: "a", bar: 1, baz: true},
+		NewStringScalar(makeLongString()),
+	} {
+		err := fw.WriteFrame(m)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+	}
+
+	// The first frame holds the 21 byte encoding of "Hello, World!"
+	if !bytes.Equal([]byte{21, 0, 0, 0}, buf.Bytes()[:FrameHeaderSize]) {
+		t.Errorf("unexpected frame header %#v", buf.Bytes()[:FrameHeaderSize])
+	}
+
+	fr := NewFrameReader(buf)
+
+	s := NewStringScalar("")
+	i := NewInt64Scalar(0)
+	c := new(customMUV)
+	l := NewStringScalar("")
+
+	for _, u := range []Unmarshaller{s, i, c, l} {
+		err := fr.ReadFrame(u)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+	}
+
+	if s.Value() != "Hello, World!" {
+		t.Errorf("expected %q, received %q", "Hello, World!", s.Value())
+	}
+
+	if i.Value() != int64(12345) {
+		t.Errorf("expected 12345, received %v", i.Value())
+	}
+
+	if *c != (customMUV{foo: "a", bar: 1, baz: true}) {
+		t.Errorf("unexpected value %#v", c)
+	}
+
+	if l.Value() != makeLongString() {
+		t.Error("long string was not decoded exactly")
+	}
+
+	err := fr.ReadFrame(s)
+	if err != io.EOF {
+		t.Errorf("expected io.EOF, received %#v", err)
+	}
+}
+
+func TestFrameWriter_MaxFrameBytes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	fw := NewFrameWriter(buf, 8)
+
+	err := fw.WriteFrame(NewStringScalar("Hello, World!"))
+	if !errors.As(err, new(ErrLimitExceeded)) {
+		t.Errorf("expected ErrLimitExceeded, received %#v", err)
+	}
+
+	if buf.Len() > 0 {
+		t.Errorf("expected nothing to be written, received %#v", buf.Bytes())
+	}
+
+	// Frames which fit may still be written afterwards
+	err = fw.WriteFrame(NewInt64Scalar(1))
+	if err != nil {
+		t.Errorf("unexpected error %#v", err)
+	}
+}
+
+func TestNewFrameWriter_Max(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		max    int64
+		expect int64
+	}{
+		{"Zero uses the default", 0, DefaultDecodeOptions.MaxFrameBytes},
+		{"Small max is kept", 8, 8},
+		{"Largest header is kept", math.MaxUint32, math.MaxUint32},
+		{"Larger than a header can hold is clamped", math.MaxUint32 + 1, math.MaxUint32},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fw := NewFrameWriter(io.Discard, test.max)
+			if fw.max != test.expect {
+				t.Errorf("expected %d, received %d", test.expect, fw.max)
+			}
+		})
+	}
+}
+
+func TestFrameReader_Errors(t *testing.T) {
+	frame := func(b []byte) []byte {
+		return append(AppendUint32(nil, uint32(len(b))), b...)
+	}
+
+	for _, test := range []struct {
+		name   string
+		input  []byte
+		sticky bool
+		expect any
+	}{
+		{"Truncated header", []byte{1, 0}, true, new(ErrTruncated)},
+		{"Truncated frame", frame(AppendInt64(nil, 1))[:8], true, new(ErrTruncated)},
+		{"Frame too large", AppendUint32(nil, 1<<30), true, new(ErrLimitExceeded)},
+		{"Frame too short for value", frame(AppendInt32(nil, 1)), false, new(ErrTruncated)},
+		{"Frame longer than value", frame(AppendInt64(AppendInt64(nil, 1), 2)), false, new(ErrUnconsumedFrame)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// Where the error isn't sticky, the input is followed by a
+			// valid frame, which must still be readable
+			input := test.input
+			if !test.sticky {
+				input = append(input, frame(AppendInt64(nil, 2))...)
+			}
+			fr := NewFrameReaderWithOptions(bytes.NewReader(input), &DecodeOptions{MaxFrameBytes: 1 << 20})
+
+			err := fr.ReadFrame(NewInt64Scalar(0))
+			if !errors.As(err, test.expect) {
+				t.Fatalf("expected %T, received %#v", test.expect, err)
+			}
+
+			i := NewInt64Scalar(0)
+			received := fr.ReadFrame(i)
+
+			switch {
+			case test.sticky && received != err:
+				t.Errorf("expected %#v, received %#v", err, received)
+
+			case !test.sticky && received != nil:
+				t.Errorf("unexpected error %#v", received)
+
+			case !test.sticky && i.Value() != int64(2):
+				t.Errorf("expected 2, received %v", i.Value())
+			}
+		})
+	}
+}
@@ -23,3 +23,33 @@ func StringNotEmpty(name, s string) error {
 	}
 	return nil
 }
+
+// BuiltinValidators holds each of mint's builtin validators, keyed by
+// the name annotations refer to them by, such as string_not_empty, for
+// callers without generated code, such as package dynamic.
+//
+// Each validates v, the value of the field called name; a v of the
+// wrong type is validated as the zero value of the right one
+var BuiltinValidators = map[string]func(name string, v any) error{
+	"string_not_empty": func(name string, v any) error {
+		s, _ := v.(string)
+
+		return StringNotEmpty(name, s)
+	},
+	"date_in_past": func(name string, v any) error {
+		t, _ := v.(time.Time)
+
+		return DateInPast(name, t)
+	},
+}
+
+// BuiltinTransforms holds each of mint's builtin transforms, keyed by
+// the name annotations refer to them by, such as date_in_utc, much as
+// BuiltinValidators does for validators
+var BuiltinTransforms = map[string]func(v any) (any, error){
+	"date_in_utc": func(v any) (any, error) {
+		t, _ := v.(time.Time)
+
+		return DateInUtc(t)
+	},
+}
@@ -59,3 +59,40 @@ func TestStringNotEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestBuiltinValidators(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		validator string
+		v         any
+		expectErr bool
+	}{
+		{"Empty string", "string_not_empty", "", true},
+		{"Non-empty string", "string_not_empty", "hello, world!", false},
+		{"Wrong type is validated as zero value", "string_not_empty", 1, true},
+		{"Future date", "date_in_past", time.Now().Add(time.Hour), true},
+		{"Past date", "date_in_past", time.Now().Add(-time.Hour), false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := BuiltinValidators[test.validator]("Field", test.v)
+			if err == nil && test.expectErr {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectErr {
+				t.Errorf("unexpected error %#v", err)
+			}
+		})
+	}
+}
+
+func TestBuiltinTransforms(t *testing.T) {
+	in := time.Now().In(time.FixedZone("Seoul", 9*60*60))
+
+	out, err := BuiltinTransforms["date_in_utc"](in)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if s, _ := out.(time.Time).Zone(); s != "UTC" {
+		t.Errorf("expected UTC, received %s", s)
+	}
+}
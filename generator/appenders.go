@@ -0,0 +1,222 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// generateAppender will:
+//  1. Create an append function per collection field
+//  2. Create an implementation of the mint.Appender interface for this type
+//
+// Unlike generateMarshaller, values are appended directly rather than
+// being wrapped in scalars, allowing callers to encode into a reused
+// buffer without allocating
+func (g *Generator) generateAppender(at parser.AnnotatedType) (j []jen.Code) {
+	functionCalls := []jen.Code{
+		jen.Id("b").Op("=").Id("in"),
+		callErrorable("Transform"),
+		callErrorable("Validate"),
+	}
+
+	j = make([]jen.Code, 0)
+
+	_, optionals := presenceBits(at)
+
+	switch {
+	// the length of an evolvable type's body is filled in once
+	// the body has been appended
+	case at.Evolvable:
+		functionCalls = append(functionCalls,
+			jen.List(jen.Id("b"), jen.Id("body")).Op(":=").Qual(mintPath, "AppendBodyHeader").Call(jen.Id("b")),
+			jen.Id("b").Op("=").Qual(mintPath, "AppendSizedPresence").Call(jen.Id("b"), presenceArg(optionals), jen.Lit(optionals)),
+		)
+
+	case optionals > 0:
+		functionCalls = append(functionCalls,
+			jen.Id("b").Op("=").Qual(mintPath, "AppendPresence").Call(jen.Id("b"), presenceArg(optionals), jen.Lit(optionals)),
+		)
+	}
+
+	for _, e := range at.ByTag() {
+		switch {
+		case e.DataType.Scalar != nil:
+			functionCalls = append(functionCalls, ifPresent(e, appendValue(e.DataType.Scalar.Type, fieldValue(e)))...)
+
+		case e.DataType.Slice != nil ||
+			e.DataType.FixedSizeSlice != nil:
+			j = append(j, g.appendSliceArray(at.Name, e))
+			functionCalls = append(functionCalls, callAppender(appenderFuncName(e.Name)))
+
+		case e.DataType.Map != nil:
+			j = append(j, g.appendMap(at.Name, e))
+			functionCalls = append(functionCalls, callAppender(appenderFuncName(e.Name)))
+
+		default:
+			continue
+		}
+	}
+
+	if at.Evolvable {
+		functionCalls = append(functionCalls, jen.Qual(mintPath, "PutBodySize").Call(jen.Id("b"), jen.Id("body")))
+	}
+
+	functionCalls = append(functionCalls, jen.Return())
+
+	return append(j, appenderFunc(at.Name, "AppendMarshall", functionCalls...))
+}
+
+func (g Generator) appendSliceArray(t string, e parser.AnnotatedEntry) jen.Code {
+	var (
+		dt      *parser.DataType
+		prelude jen.Code
+	)
+
+	if e.Field.DataType == nil {
+		return jen.Null()
+	}
+
+	switch {
+	case e.Field.DataType.Slice != nil:
+		dt = e.Field.DataType.Slice.Type
+		prelude = jen.Id("b").Op("=").Qual(mintPath, "AppendUint32").Call(jen.Id("in"), jen.Id("uint32").Call(jen.Id("len").Call(jen.Id("sf").Dot(e.Name))))
+
+	case e.Field.DataType.FixedSizeSlice != nil:
+		dt = e.Field.DataType.FixedSizeSlice.Type
+		prelude = jen.Id("b").Op("=").Id("in")
+
+	default:
+		return jen.Null()
+	}
+
+	return appenderFunc(t, appenderFuncName(e.Name),
+		prelude,
+		jen.For(jen.List(jen.Id("_"), jen.Id("v")).Op(":=").Range().Id("sf").Dot(e.Name)).Block(
+			appendElement(dt, jen.Id("v"), 1)...,
+		),
+		jen.Return(),
+	)
+}
+
+func (g Generator) appendMap(t string, e parser.AnnotatedEntry) jen.Code {
+	return appenderFunc(t, appenderFuncName(e.Name),
+		jen.Id("b").Op("=").Qual(mintPath, "AppendUint32").Call(jen.Id("in"), jen.Id("uint32").Call(jen.Id("len").Call(jen.Id("sf").Dot(e.Name)).Op("*").Lit(2))),
+		jen.For(jen.List(jen.Id("k"), jen.Id("v")).Op(":=").Range().Id("sf").Dot(e.Name)).Block(
+			append([]jen.Code{
+				appendValue(e.DataType.Map.Key, jen.Id("k")),
+			}, appendElement(e.DataType.Map.Value, jen.Id("v"), 1)...)...,
+		),
+		jen.Return(),
+	)
+}
+
+func (g Generator) appendEnum(e parser.Enum) jen.Code {
+	return jen.Func().Params(jen.Id("sf").Id(e.Name)).Id("AppendMarshall").Params(jen.Id("b").Index().Byte()).Params(jen.Index().Byte(), jen.Id("error")).
+		Block(
+			jen.If(jen.Id("sf").Op("<").Lit(1).Op("||").Id("sf").Op(">").Lit(len(e.Values))).Block(
+				jen.Return(jen.Id("b"), jen.Qual("errors", "New").Call(jen.Lit("invalid value for type "+e.Name))),
+			),
+			jen.Return(jen.Qual(mintPath, "AppendByte").Call(jen.Id("b"), jen.Id("byte").Call(jen.Id("sf"))), jen.Id("nil")),
+		)
+}
+
+// appenderFunc wraps body in a function with the signature of
+// mint.Appender.AppendMarshall, named fn, on type t
+func appenderFunc(t, fn string, body ...jen.Code) jen.Code {
+	return jen.Func().Params(jen.Id("sf").Id(t)).Id(fn).Params(jen.Id("in").Index().Byte()).Params(jen.Id("b").Index().Byte(), jen.Id("err").Id("error")).
+		Block(body...)
+}
+
+// callAppender calls the append function fn, returning on error
+func callAppender(fn string) jen.Code {
+	return jen.If(jen.List(jen.Id("b"), jen.Id("err")).Op("=").Id("sf").Dot(fn).Call(jen.Id("b")).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return())
+}
+
+// appendValue appends v, of mint type dt, to b; scalars are appended
+// with the relevant mint.AppendX function, and anything else via
+// its own AppendMarshall
+func appendValue(dt string, v *jen.Statement) jen.Code {
+	if f := scalarToAppendJen(dt); f != nil {
+		return jen.Id("b").Op("=").Add(f).Call(jen.Id("b"), v)
+	}
+
+	return jen.If(jen.List(jen.Id("b"), jen.Id("err")).Op("=").Add(v).Dot("AppendMarshall").Call(jen.Id("b")).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return())
+}
+
+// appendElement appends v, an element of a collection of mint type dt,
+// to b. Where v is itself a collection it's appended as a field would be,
+// ranging over its own elements with variables named for depth
+func appendElement(dt *parser.DataType, v *jen.Statement, depth int) []jen.Code {
+	switch {
+	case dt.Slice != nil:
+		return []jen.Code{
+			jen.Id("b").Op("=").Qual(mintPath, "AppendUint32").Call(jen.Id("b"), jen.Id("uint32").Call(jen.Id("len").Call(v))),
+			jen.For(jen.List(jen.Id("_"), elemId("v", depth)).Op(":=").Range().Add(v)).Block(
+				appendElement(dt.Slice.Type, elemId("v", depth), depth+1)...,
+			),
+		}
+
+	case dt.FixedSizeSlice != nil:
+		return []jen.Code{
+			jen.For(jen.List(jen.Id("_"), elemId("v", depth)).Op(":=").Range().Add(v)).Block(
+				appendElement(dt.FixedSizeSlice.Type, elemId("v", depth), depth+1)...,
+			),
+		}
+
+	case dt.Map != nil:
+		return []jen.Code{
+			jen.Id("b").Op("=").Qual(mintPath, "AppendUint32").Call(jen.Id("b"), jen.Id("uint32").Call(jen.Id("len").Call(v).Op("*").Lit(2))),
+			jen.For(jen.List(elemId("k", depth), elemId("v", depth)).Op(":=").Range().Add(v)).Block(
+				append([]jen.Code{
+					appendValue(dt.Map.Key, elemId("k", depth)),
+				}, appendElement(dt.Map.Value, elemId("v", depth), depth+1)...)...,
+			),
+		}
+	}
+
+	return []jen.Code{appendValue(dt.Scalar.Type, v)}
+}
+
+// scalarToAppendJen returns the mint.AppendX function for scalar
+// type ts, or nil where ts isn't a scalar
+func scalarToAppendJen(ts string) jen.Code {
+	switch ts {
+	case "string":
+		return jen.Qual(mintPath, "AppendString")
+
+	case "datetime":
+		return jen.Qual(mintPath, "AppendDatetime")
+
+	case "uuid":
+		return jen.Qual(mintPath, "AppendUuid")
+
+	case "uint32":
+		return jen.Qual(mintPath, "AppendUint32")
+
+	case "int16":
+		return jen.Qual(mintPath, "AppendInt16")
+
+	case "int32":
+		return jen.Qual(mintPath, "AppendInt32")
+
+	case "int64":
+		return jen.Qual(mintPath, "AppendInt64")
+
+	case "float32":
+		return jen.Qual(mintPath, "AppendFloat32")
+
+	case "float64":
+		return jen.Qual(mintPath, "AppendFloat64")
+
+	case "bool":
+		return jen.Qual(mintPath, "AppendBool")
+
+	case "byte", "uint8":
+		return jen.Qual(mintPath, "AppendByte")
+
+	case "uint16":
+		return jen.Qual(mintPath, "AppendUint16")
+	}
+
+	return nil
+}
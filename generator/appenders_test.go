@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/vinyl-linux/mint/parser"
+)
+
+func TestGenerator_generateAppender(t *testing.T) {
+	g := new(Generator)
+
+	expect := `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf SomeTestType) appendSomeStringSlice(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.SomeStringSlice)))
+	for _, v := range sf.SomeStringSlice {
+		b = mint.AppendString(b, v)
+	}
+	return
+}
+func (sf SomeTestType) appendSomeStringSlice(in []byte) (b []byte, err error) {
+	b = in
+	for _, v := range sf.SomeStringSlice {
+		b = mint.AppendString(b, v)
+	}
+	return
+}
+func (sf SomeTestType) appendSomeStringSlice(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.SomeStringSlice)*2))
+	for k, v := range sf.SomeStringSlice {
+		b = mint.AppendString(b, k)
+		b = mint.AppendInt64(b, v)
+	}
+	return
+}
+func (sf SomeTestType) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if b, err = sf.appendSomeStringSlice(b); err != nil {
+		return
+	}
+	if b, err = sf.appendSomeStringSlice(b); err != nil {
+		return
+	}
+	if b, err = sf.appendSomeStringSlice(b); err != nil {
+		return
+	}
+	b = mint.AppendUuid(b, sf.ATypeOfSomeType)
+	if b, err = sf.Thingy.AppendMarshall(b); err != nil {
+		return
+	}
+	return
+}
+`
+	received := codeSliceToFile(g.generateAppender(simpleType))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_appendSliceArray(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		ae     parser.AnnotatedEntry
+		expect string
+	}{
+		{"Bad input does nothing", parser.AnnotatedEntry{}, ""},
+		{"Non-slice returns nothing", parser.AnnotatedEntry{Field: parser.Field{DataType: &parser.DataType{}}}, ""},
+		{"Complex types append themselves", userDefinedSliceEntry, `func (sf TestType) appendThingy(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.Thingy)))
+	for _, v := range sf.Thingy {
+		if b, err = v.AppendMarshall(b); err != nil {
+			return
+		}
+	}
+	return
+}`},
+		{"Nested collections append each length", nestedSliceEntry, `func (sf TestType) appendMatrix(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.Matrix)))
+	for _, v := range sf.Matrix {
+		b = mint.AppendUint32(b, uint32(len(v)))
+		for _, v1 := range v {
+			b = mint.AppendFloat64(b, v1)
+		}
+	}
+	return
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g := new(Generator)
+			received := codeToString(g.appendSliceArray("TestType", test.ae))
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
+
+func TestGenerator_appendMap(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf TestType) appendThingy(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.Thingy)*2))
+	for k, v := range sf.Thingy {
+		if b, err = k.AppendMarshall(b); err != nil {
+			return
+		}
+		b = mint.AppendBool(b, v)
+	}
+	return
+}`
+	received := codeToString(g.appendMap("TestType", complexToBuiltinMap))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_appendMap_Nested(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf TestType) appendGroups(in []byte) (b []byte, err error) {
+	b = mint.AppendUint32(in, uint32(len(sf.Groups)*2))
+	for k, v := range sf.Groups {
+		b = mint.AppendString(b, k)
+		b = mint.AppendUint32(b, uint32(len(v)))
+		for _, v1 := range v {
+			if b, err = v1.AppendMarshall(b); err != nil {
+				return
+			}
+		}
+	}
+	return
+}`
+	received := codeToString(g.appendMap("TestType", nestedMapEntry))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_appendEnum(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf TestEnum) AppendMarshall(b []byte) ([]byte, error) {
+	if sf < 1 || sf > 2 {
+		return b, errors.New("invalid value for type TestEnum")
+	}
+	return mint.AppendByte(b, byte(sf)), nil
+}`
+	received := codeToString(g.appendEnum(parser.Enum{
+		Name:   "TestEnum",
+		Values: []*parser.EnumEntry{{}, {}},
+	}))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
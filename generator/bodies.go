@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// enterBody reads the length of the body of evolvable type t from reader,
+// which is either a mint.DecodeReader or a mint.BytesDecoder, and resets
+// each field to its default, so that fields missing from the end of the
+// body, having been added since it was written, are left with them
+func enterBody(t, reader string) []jen.Code {
+	return []jen.Code{
+		jen.If(jen.Id("err").Op("=").Id(reader).Dot("EnterBody").Call().Id(";").Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(jen.Qual(mintPath, "WrapDecodeError").Call(jen.Id(reader), jen.Lit(t), jen.Lit(""), jen.Id("err"))),
+		),
+		jen.Defer().Id(reader).Dot("LeaveBody").Call(),
+		jen.Op("*").Id("sf").Op("=").Op("*").Id(constructorName(t)).Call(),
+	}
+}
+
+// skipBody discards whatever remains of the body of evolvable type t,
+// which holds any fields added since this code was generated
+func skipBody(t, reader string) jen.Code {
+	return jen.If(jen.Id("err").Op("=").Id(reader).Dot("SkipBody").Call().Id(";").Id("err").Op("!=").Id("nil")).Block(
+		jen.Return(jen.Qual(mintPath, "WrapDecodeError").Call(jen.Id(reader), jen.Lit(t), jen.Lit(""), jen.Id("err"))),
+	)
+}
+
+// ifMore wraps the decoding of a field of at in a check that its body
+// hasn't run out, where at is evolvable
+func ifMore(at parser.AnnotatedType, reader string, body ...jen.Code) []jen.Code {
+	if !at.Evolvable {
+		return body
+	}
+
+	return []jen.Code{
+		jen.If(jen.Id(reader).Dot("More").Call()).Block(body...),
+	}
+}
@@ -0,0 +1,219 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestGenerator_Evolvable(t *testing.T) {
+	g := new(Generator)
+
+	d := new(Generator)
+	d.DirectEncoding = true
+
+	for _, test := range []struct {
+		name   string
+		f      func() string
+		expect string
+	}{
+		{"generateMarshaller", func() string { return codeSliceToFile(g.generateMarshaller(evolvableType)) }, `package test
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+func (sf EvolvableType) Marshall(w io.Writer) (err error) {
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if err = mint.WriteBodySize(w, sf.MarshalledSize()-mint.BodyHeaderSize); err != nil {
+		return
+	}
+	if err = mint.WriteSizedPresence(w, sf.presence(), 1); err != nil {
+		return
+	}
+	if err = mint.NewStringScalar(sf.Region).Marshall(w); err != nil {
+		return
+	}
+	if sf.Nickname != nil {
+		if err = mint.NewStringScalar(*sf.Nickname).Marshall(w); err != nil {
+			return
+		}
+	}
+	return
+}
+`},
+		{"generateMarshaller, direct, without optional fields", func() string { return codeSliceToFile(d.generateMarshaller(evolvableNoOptionalsType)) }, `package test
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+func (sf EvolvableNoOptionalsType) Marshall(w io.Writer) (err error) {
+	var b []byte
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if err = mint.WriteBodySize(w, sf.MarshalledSize()-mint.BodyHeaderSize); err != nil {
+		return
+	}
+	if err = mint.WriteSizedPresence(w, 0, 0); err != nil {
+		return
+	}
+	b = mint.AppendString(b[:0], sf.Region)
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	return
+}
+`},
+		{"generateUnmarshaller, without optional fields", func() string { return codeSliceToFile(g.generateUnmarshaller(evolvableNoOptionalsType)) }, `package test
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+func (sf *EvolvableNoOptionalsType) unmarshallRegion(r io.Reader) (err error) {
+	f := mint.NewStringScalar("")
+	err = f.Unmarshall(r)
+	if err != nil {
+		return
+	}
+	sf.Region = f.Value().(string)
+	return
+}
+func (sf *EvolvableNoOptionalsType) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	if err = dr.EnterBody(); err != nil {
+		return mint.WrapDecodeError(dr, "EvolvableNoOptionalsType", "", err)
+	}
+	defer dr.LeaveBody()
+	*sf = *NewEvolvableNoOptionalsType()
+	_, err = mint.ReadSizedPresence(dr, 0)
+	if err != nil {
+		return mint.WrapDecodeError(dr, "EvolvableNoOptionalsType", "", err)
+	}
+	if dr.More() {
+		if err = sf.unmarshallRegion(dr); err != nil {
+			return mint.WrapDecodeError(dr, "EvolvableNoOptionalsType", "Region", err)
+		}
+	}
+	if err = dr.SkipBody(); err != nil {
+		return mint.WrapDecodeError(dr, "EvolvableNoOptionalsType", "", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+`},
+		{"generateAppender", func() string { return codeSliceToFile(g.generateAppender(evolvableType)) }, `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf EvolvableType) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	b, body := mint.AppendBodyHeader(b)
+	b = mint.AppendSizedPresence(b, sf.presence(), 1)
+	b = mint.AppendString(b, sf.Region)
+	if sf.Nickname != nil {
+		b = mint.AppendString(b, *sf.Nickname)
+	}
+	mint.PutBodySize(b, body)
+	return
+}
+`},
+		{"generateBytesUnmarshaller", func() string { return codeSliceToFile(g.generateBytesUnmarshaller(evolvableType)) }, `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf *EvolvableType) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	if err = d.EnterBody(); err != nil {
+		return mint.WrapDecodeError(d, "EvolvableType", "", err)
+	}
+	defer d.LeaveBody()
+	*sf = *NewEvolvableType()
+	p, err := d.ReadSizedPresence(1)
+	if err != nil {
+		return mint.WrapDecodeError(d, "EvolvableType", "", err)
+	}
+	if d.More() {
+		if sf.Region, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "EvolvableType", "Region", err)
+		}
+	}
+	if d.More() {
+		sf.Nickname = nil
+		if p&(1<<0) != 0 {
+			sf.Nickname = new(string)
+			if *sf.Nickname, err = d.ReadString(); err != nil {
+				return mint.WrapDecodeError(d, "EvolvableType", "Nickname", err)
+			}
+		}
+	}
+	if err = d.SkipBody(); err != nil {
+		return mint.WrapDecodeError(d, "EvolvableType", "", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf *EvolvableType) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+`},
+		{"generateSizer", func() string { return codeSliceToFile(g.generateSizer(evolvableType)) }, `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf EvolvableType) MarshalledSize() (n int) {
+	n += mint.BodyHeaderSize
+	n += mint.SizedPresenceSize(1)
+	n += mint.StringSize(sf.Region)
+	if sf.Nickname != nil {
+		n += mint.StringSize(*sf.Nickname)
+	}
+	return
+}
+`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			received := test.f()
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
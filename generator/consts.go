@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"path/filepath"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+const (
+	constsFile = "constants.go"
+)
+
+// generateForConsts creates a single file holding each const defined
+// across a set of documents
+func (g *Generator) generateForConsts(consts []parser.Const) (err error) {
+	ret := jen.NewFile(g.PackageName)
+
+	ret.Add(g.generateConsts(consts))
+
+	return ret.Save(filepath.Join(g.Directory, constsFile))
+}
+
+// generateConsts creates a const block, typing each const as per its
+// declaration, such that MaxTags int32 = 16 remains an int32
+func (g *Generator) generateConsts(consts []parser.Const) jen.Code {
+	defs := make([]jen.Code, len(consts))
+	for i, c := range consts {
+		defs[i] = jen.Id(c.Name).Add(toJenElemType(c.Type)).Op("=").Add(valueLiteral(*c.Value))
+	}
+
+	return jen.Null().Const().Defs(defs...)
+}
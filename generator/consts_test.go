@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/vinyl-linux/mint/parser"
+)
+
+func TestGenerator_generateConsts(t *testing.T) {
+	g := new(Generator)
+
+	expect := `const (
+	MaxTags  int32   = 16
+	Region   string  = "eu-west-1"
+	Ratio    float32 = 0.5
+	Strict   bool    = true
+	Negative int64   = -3
+)`
+	received := codeToString(g.generateConsts([]parser.Const{
+		{Name: "MaxTags", Type: "int32", Value: &parser.Value{Number: ptr("16")}},
+		{Name: "Region", Type: "string", Value: &parser.Value{Quoted: ptr("eu-west-1")}},
+		{Name: "Ratio", Type: "float32", Value: &parser.Value{Number: ptr("0.5")}},
+		{Name: "Strict", Type: "bool", Value: &parser.Value{Ident: ptr("true")}},
+		{Name: "Negative", Type: "int64", Value: &parser.Value{Number: ptr("-3")}},
+	}))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
@@ -0,0 +1,316 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+var (
+	bytesDecoderType = jen.Op("*").Qual(mintPath, "BytesDecoder")
+)
+
+// generateBytesUnmarshaller will:
+//  1. Create a decode function per collection field
+//  2. Create an implementation of the mint.DecoderUnmarshaller interface for this type
+//  3. Create an implementation of the mint.BytesUnmarshaller interface for this type
+//
+// These read directly from a mint.BytesDecoder, rather than via an io.Reader
+// and a scalar per value, for data which is already in memory
+func (g *Generator) generateBytesUnmarshaller(at parser.AnnotatedType) (j []jen.Code) {
+	functionCalls := []jen.Code{
+		jen.If(jen.Id("err").Op("=").Id("d").Dot("Enter").Call().Id(";").Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(),
+		),
+		jen.Defer().Id("d").Dot("Leave").Call(),
+	}
+
+	j = make([]jen.Code, 0)
+
+	if at.Evolvable {
+		functionCalls = append(functionCalls, enterBody(at.Name, "d")...)
+	}
+
+	bits, optionals := presenceBits(at)
+	if optionals > 0 || at.Evolvable {
+		functionCalls = append(functionCalls, readPresence(at, "d")...)
+	}
+
+	for _, e := range at.ByTag() {
+		wrapped := jen.Return(jen.Qual(mintPath, "WrapDecodeError").Call(jen.Id("d"), jen.Lit(at.Name), jen.Lit(e.Name), jen.Id("err")))
+
+		switch {
+		case e.DataType.Scalar != nil && e.Optional:
+			functionCalls = append(functionCalls, ifMore(at, "d", ifPresentBit(e, bits[e.Name],
+				jen.Id("sf").Dot(e.Name).Op("=").New(toJenElemType(e.DataType.Scalar.Type)),
+				decodeValue(e.DataType.Scalar.Type, fieldValue(e), wrapped),
+			)...)...)
+
+		case e.DataType.Scalar != nil:
+			functionCalls = append(functionCalls, ifMore(at, "d", decodeValue(e.DataType.Scalar.Type, jen.Id("sf").Dot(e.Name), wrapped))...)
+
+		case e.DataType.Slice != nil ||
+			e.DataType.FixedSizeSlice != nil:
+			j = append(j, g.decodeSliceArray(at.Name, e))
+			functionCalls = append(functionCalls, ifMore(at, "d", callDecoder(decoderFuncName(e.Name), wrapped))...)
+
+		case e.DataType.Map != nil:
+			j = append(j, g.decodeMap(at.Name, e))
+			functionCalls = append(functionCalls, ifMore(at, "d", callDecoder(decoderFuncName(e.Name), wrapped))...)
+
+		default:
+			continue
+		}
+	}
+
+	if at.Evolvable {
+		functionCalls = append(functionCalls, skipBody(at.Name, "d"))
+	}
+
+	functionCalls = append(functionCalls,
+		callErrorable("Transform"),
+		callErrorable("Validate"),
+		jen.Return(),
+	)
+
+	return append(j,
+		jen.Func().Params(jen.Id("sf").Op("*").Id(at.Name)).Id("UnmarshallDecoder").Params(jen.Id("d").Add(bytesDecoderType)).Params(jen.Id("err").Id("error")).
+			Block(functionCalls...),
+		unmarshallBytes(at.Name),
+	)
+}
+
+func (g Generator) decodeSliceArray(t string, e parser.AnnotatedEntry) jen.Code {
+	var (
+		prelude []jen.Code
+		loop    jen.Code
+	)
+
+	if e.Field.DataType == nil {
+		return jen.Null()
+	}
+
+	switch {
+	case e.Field.DataType.Slice != nil:
+		dt := e.Field.DataType.Slice.Type
+		prelude = []jen.Code{
+			jen.List(jen.Id("l"), jen.Id("err")).Op(":=").Id("d").Dot("ReadSliceLen").Call(),
+			jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
+				jen.Return(),
+			),
+			jen.If(jen.Id("l").Op("==").Lit(0)).Block(
+				jen.Id("sf").Dot(e.Name).Op("=").Id("nil"),
+				jen.Return(),
+			),
+			jen.Id("sf").Dot(e.Name).Op("=").Id("make").Call(jen.Index().Add(toJenDataType(dt)), jen.Lit(0), collectionCap(jen.Id("l"))),
+		}
+		loop = appendElements(decodeElement, dt, jen.Id("sf").Dot(e.Name), jen.Id("l"), 0, nil)
+
+	case e.Field.DataType.FixedSizeSlice != nil:
+		loop = jen.For(jen.Id("i").Op(":=").Range().Id("sf").Dot(e.Name)).Block(
+			decodeElement(e.Field.DataType.FixedSizeSlice.Type, jen.Id("sf").Dot(e.Name).Index(jen.Id("i")), 1, indexSegment(jen.Id("i")))...,
+		)
+
+	default:
+		return jen.Null()
+	}
+
+	prelude = append(prelude, enterCollection("d", nil)...)
+
+	return decoderFunc(t, decoderFuncName(e.Name), append(prelude,
+		loop,
+		jen.Return(),
+	)...)
+}
+
+func (g Generator) decodeMap(t string, e parser.AnnotatedEntry) jen.Code {
+	prelude := append([]jen.Code{
+		jen.List(jen.Id("l"), jen.Id("err")).Op(":=").Id("d").Dot("ReadMapLen").Call(),
+		jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(),
+		),
+		jen.If(jen.Id("l").Op("==").Lit(0)).Block(
+			jen.Id("sf").Dot(e.Name).Op("=").Id("nil"),
+			jen.Return(),
+		),
+		jen.Id("sf").Dot(e.Name).Op("=").Id("make").Call(toJenDataType(e.DataType), collectionCap(jen.Id("l"))),
+	}, enterCollection("d", nil)...)
+
+	return decoderFunc(t, decoderFuncName(e.Name), append(prelude,
+		jen.For(jen.Id("i").Op(":=").Lit(0), jen.Id("i").Op("<").Id("l"), jen.Id("i").Op("++")).Block(
+			append([]jen.Code{
+				jen.Var().Id("k").Add(toJenElemType(e.DataType.Map.Key)),
+				jen.Var().Id("v").Add(toJenDataType(e.DataType.Map.Value)),
+				decodeValue(e.DataType.Map.Key, jen.Id("k"), wrapElementError(indexSegment(jen.Id("i")))),
+			}, append(
+				decodeElement(e.DataType.Map.Value, jen.Id("v"), 1, keySegment(jen.Id("k"))),
+				jen.Id("sf").Dot(e.Name).Index(jen.Id("k")).Op("=").Id("v"),
+			)...)...,
+		),
+		jen.Return(),
+	)...)
+}
+
+func (g Generator) decodeEnum(e parser.Enum) []jen.Code {
+	return []jen.Code{
+		jen.Func().Params(jen.Id("sf").Op("*").Id(e.Name)).Id("UnmarshallDecoder").Params(jen.Id("d").Add(bytesDecoderType)).Params(jen.Id("err").Id("error")).
+			Block(
+				jen.List(jen.Id("b"), jen.Id("err")).Op(":=").Id("d").Dot("ReadByte").Call(),
+				jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
+					jen.Return(),
+				),
+				jen.Id("*sf").Op("=").Id(e.Name).Call(jen.Id("b")),
+				jen.If(jen.Id("*sf").Op("<").Lit(1).Op("||").Id("*sf").Op(">").Lit(len(e.Values))).Block(
+					jen.Return(jen.Qual("errors", "New").Call(jen.Lit("invalid value for type "+e.Name))),
+				),
+				jen.Return(),
+			),
+		unmarshallBytes(e.Name),
+	}
+}
+
+// unmarshallBytes creates an implementation of the mint.BytesUnmarshaller
+// interface for type t, which must already implement mint.DecoderUnmarshaller
+func unmarshallBytes(t string) jen.Code {
+	return jen.Func().Params(jen.Id("sf").Op("*").Id(t)).Id("UnmarshallBytes").Params(jen.Id("b").Index().Byte()).Params(jen.Id("n").Int(), jen.Id("err").Id("error")).
+		Block(
+			jen.Id("d").Op(":=").Qual(mintPath, "NewBytesDecoder").Call(jen.Id("b"), jen.Id("nil")),
+			jen.Id("err").Op("=").Id("sf").Dot("UnmarshallDecoder").Call(jen.Id("d")),
+			jen.Return(jen.Int().Call(jen.Id("d").Dot("Offset").Call()), jen.Id("err")),
+		)
+}
+
+// decoderFunc wraps body in a function which decodes part of type t
+// from a mint.BytesDecoder
+func decoderFunc(t, fn string, body ...jen.Code) jen.Code {
+	return jen.Func().Params(jen.Id("sf").Op("*").Id(t)).Id(fn).Params(jen.Id("d").Add(bytesDecoderType)).Params(jen.Id("err").Id("error")).
+		Block(body...)
+}
+
+// callDecoder calls the decode function fn, running onErr on error
+func callDecoder(fn string, onErr jen.Code) jen.Code {
+	return jen.If(jen.Id("err").Op("=").Id("sf").Dot(fn).Call(jen.Id("d")).Id(";").Id("err").Op("!=").Id("nil")).Block(onErr)
+}
+
+// wrapElementError returns from a collection decoder, wrapping err with
+// the path segments segs, outermost first
+func wrapElementError(segs ...jen.Code) jen.Code {
+	return wrapSegments("d", segs)
+}
+
+// decodeElement decodes v, an element of a collection of mint type dt
+// found at the path segments segs, from d. Where v is itself a collection
+// it's decoded as a field would be, ranging over its own elements with
+// variables named for depth, and with depth-1 nested collections already
+// entered
+func decodeElement(dt *parser.DataType, v *jen.Statement, depth int, segs ...jen.Code) []jen.Code {
+	l := elemId("l", depth)
+	i := elemId("i", depth)
+	onErr := leaveElements("d", "d", depth-1, segs)
+
+	// cap segs, so that appending to it for each element copies
+	// rather than sharing a backing array between siblings
+	segs = segs[:len(segs):len(segs)]
+
+	switch {
+	case dt.Slice != nil:
+		return []jen.Code{
+			jen.List(l, jen.Id("err")).Op(":=").Id("d").Dot("ReadSliceLen").Call(),
+			jen.If(jen.Id("err").Op("!=").Id("nil")).Block(onErr...),
+			jen.If(jen.Add(l).Op("==").Lit(0)).Block(
+				jen.Add(v).Op("=").Id("nil"),
+			).Else().Block(
+				append([]jen.Code{jen.Add(v).Op("=").Id("make").Call(toJenDataType(dt), jen.Lit(0), collectionCap(l))},
+					enterElement("d", onErr, appendElements(decodeElement, dt.Slice.Type, v, l, depth, segs))...,
+				)...,
+			),
+		}
+
+	case dt.FixedSizeSlice != nil:
+		return enterElement("d", onErr, jen.For(jen.Add(i).Op(":=").Range().Add(v)).Block(
+			decodeElement(dt.FixedSizeSlice.Type, jen.Add(v).Index(i), depth+1, append(segs, indexSegment(i))...)...,
+		))
+
+	case dt.Map != nil:
+		k := elemId("k", depth)
+		mv := elemId("v", depth)
+
+		return []jen.Code{
+			jen.List(l, jen.Id("err")).Op(":=").Id("d").Dot("ReadMapLen").Call(),
+			jen.If(jen.Id("err").Op("!=").Id("nil")).Block(onErr...),
+			jen.If(jen.Add(l).Op("==").Lit(0)).Block(
+				jen.Add(v).Op("=").Id("nil"),
+			).Else().Block(
+				append([]jen.Code{jen.Add(v).Op("=").Id("make").Call(toJenDataType(dt), collectionCap(l))},
+					enterElement("d", onErr, jen.For(jen.Add(i).Op(":=").Lit(0), jen.Add(i).Op("<").Add(l), jen.Add(i).Op("++")).Block(
+						append([]jen.Code{
+							jen.Var().Add(k).Add(toJenElemType(dt.Map.Key)),
+							jen.Var().Add(mv).Add(toJenDataType(dt.Map.Value)),
+							decodeValue(dt.Map.Key, k, leaveElements("d", "d", depth, append(segs, indexSegment(i)))...),
+						}, append(
+							decodeElement(dt.Map.Value, mv, depth+1, append(segs, keySegment(k))...),
+							jen.Add(v).Index(k).Op("=").Add(mv),
+						)...)...,
+					))...,
+				)...,
+			),
+		}
+	}
+
+	return []jen.Code{decodeValue(dt.Scalar.Type, v, onErr...)}
+}
+
+// decodeValue decodes v, of mint type dt, from d; scalars are read with
+// the relevant BytesDecoder function, and anything else via its own
+// UnmarshallDecoder
+func decodeValue(dt string, v *jen.Statement, onErr ...jen.Code) jen.Code {
+	if f := scalarToDecoderFunc(dt); f != "" {
+		return jen.If(jen.List(v, jen.Id("err")).Op("=").Id("d").Dot(f).Call().Id(";").Id("err").Op("!=").Id("nil")).Block(onErr...)
+	}
+
+	return jen.If(jen.Id("err").Op("=").Add(v).Dot("UnmarshallDecoder").Call(jen.Id("d")).Id(";").Id("err").Op("!=").Id("nil")).Block(onErr...)
+}
+
+// scalarToDecoderFunc returns the name of the BytesDecoder function, and
+// of the equivalent mint package function, for reading scalar type ts,
+// or an empty string where ts isn't a scalar
+func scalarToDecoderFunc(ts string) string {
+	switch ts {
+	case "string":
+		return "ReadString"
+
+	case "datetime":
+		return "ReadDatetime"
+
+	case "uuid":
+		return "ReadUuid"
+
+	case "uint32":
+		return "ReadUint32"
+
+	case "int16":
+		return "ReadInt16"
+
+	case "int32":
+		return "ReadInt32"
+
+	case "int64":
+		return "ReadInt64"
+
+	case "float32":
+		return "ReadFloat32"
+
+	case "float64":
+		return "ReadFloat64"
+
+	case "bool":
+		return "ReadBool"
+
+	case "byte", "uint8":
+		return "ReadByte"
+
+	case "uint16":
+		return "ReadUint16"
+	}
+
+	return ""
+}
@@ -0,0 +1,319 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/vinyl-linux/mint/parser"
+)
+
+func TestGenerator_generateBytesUnmarshaller(t *testing.T) {
+	g := new(Generator)
+
+	expect := `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf *SomeTestType) decodeSomeStringSlice(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadSliceLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.SomeStringSlice = nil
+		return
+	}
+	sf.SomeStringSlice = make([]string, 0, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var e string
+		if e, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		sf.SomeStringSlice = append(sf.SomeStringSlice, e)
+	}
+	return
+}
+func (sf *SomeTestType) decodeSomeStringSlice(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := range sf.SomeStringSlice {
+		if sf.SomeStringSlice[i], err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+	}
+	return
+}
+func (sf *SomeTestType) decodeSomeStringSlice(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadMapLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.SomeStringSlice = nil
+		return
+	}
+	sf.SomeStringSlice = make(map[string]int64, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var k string
+		var v int64
+		if k, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		if v, err = d.ReadInt64(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.KeySegment(k), err)
+		}
+		sf.SomeStringSlice[k] = v
+	}
+	return
+}
+func (sf *SomeTestType) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	if err = sf.decodeSomeStringSlice(d); err != nil {
+		return mint.WrapDecodeError(d, "SomeTestType", "SomeStringSlice", err)
+	}
+	if err = sf.decodeSomeStringSlice(d); err != nil {
+		return mint.WrapDecodeError(d, "SomeTestType", "SomeStringSlice", err)
+	}
+	if err = sf.decodeSomeStringSlice(d); err != nil {
+		return mint.WrapDecodeError(d, "SomeTestType", "SomeStringSlice", err)
+	}
+	if sf.ATypeOfSomeType, err = d.ReadUuid(); err != nil {
+		return mint.WrapDecodeError(d, "SomeTestType", "ATypeOfSomeType", err)
+	}
+	if err = sf.Thingy.UnmarshallDecoder(d); err != nil {
+		return mint.WrapDecodeError(d, "SomeTestType", "Thingy", err)
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf *SomeTestType) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+`
+	received := codeSliceToFile(g.generateBytesUnmarshaller(simpleType))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_decodeSliceArray(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		ae     parser.AnnotatedEntry
+		expect string
+	}{
+		{"Bad input does nothing", parser.AnnotatedEntry{}, ""},
+		{"Non-slice returns nothing", parser.AnnotatedEntry{Field: parser.Field{DataType: &parser.DataType{}}}, ""},
+		{"Complex types decode themselves", userDefinedSliceEntry, `func (sf *TestType) decodeThingy(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadSliceLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Thingy = nil
+		return
+	}
+	sf.Thingy = make([]BlahType, 0, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var e BlahType
+		if err = e.UnmarshallDecoder(d); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		sf.Thingy = append(sf.Thingy, e)
+	}
+	return
+}`},
+		{"Arrays of slices decode each length", nestedArrayEntry, `func (sf *TestType) decodeHalves(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := range sf.Halves {
+		l1, err := d.ReadSliceLen()
+		if err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		if l1 == 0 {
+			sf.Halves[i] = nil
+		} else {
+			sf.Halves[i] = make([]string, 0, mint.CollectionCap(l1))
+			if err = d.Enter(); err != nil {
+				return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+			}
+			for i1 := 0; i1 < l1; i1++ {
+				var e1 string
+				if e1, err = d.ReadString(); err != nil {
+					d.Leave()
+					return mint.WrapDecodeError(d, "", mint.IndexSegment(i), mint.WrapDecodeError(d, "", mint.IndexSegment(i1), err))
+				}
+				sf.Halves[i] = append(sf.Halves[i], e1)
+			}
+			d.Leave()
+		}
+	}
+	return
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g := new(Generator)
+			received := codeToString(g.decodeSliceArray("TestType", test.ae))
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
+
+func TestGenerator_decodeMap(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf *TestType) decodeThingy(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadMapLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Thingy = nil
+		return
+	}
+	sf.Thingy = make(map[BlahType]bool, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var k BlahType
+		var v bool
+		if err = k.UnmarshallDecoder(d); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		if v, err = d.ReadBool(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.KeySegment(k), err)
+		}
+		sf.Thingy[k] = v
+	}
+	return
+}`
+	received := codeToString(g.decodeMap("TestType", complexToBuiltinMap))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_decodeMap_Nested(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf *TestType) decodeGroups(d *mint.BytesDecoder) (err error) {
+	l, err := d.ReadMapLen()
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Groups = nil
+		return
+	}
+	sf.Groups = make(map[string][]BlahType, mint.CollectionCap(l))
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	for i := 0; i < l; i++ {
+		var k string
+		var v []BlahType
+		if k, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "", mint.IndexSegment(i), err)
+		}
+		l1, err := d.ReadSliceLen()
+		if err != nil {
+			return mint.WrapDecodeError(d, "", mint.KeySegment(k), err)
+		}
+		if l1 == 0 {
+			v = nil
+		} else {
+			v = make([]BlahType, 0, mint.CollectionCap(l1))
+			if err = d.Enter(); err != nil {
+				return mint.WrapDecodeError(d, "", mint.KeySegment(k), err)
+			}
+			for i1 := 0; i1 < l1; i1++ {
+				var e1 BlahType
+				if err = e1.UnmarshallDecoder(d); err != nil {
+					d.Leave()
+					return mint.WrapDecodeError(d, "", mint.KeySegment(k), mint.WrapDecodeError(d, "", mint.IndexSegment(i1), err))
+				}
+				v = append(v, e1)
+			}
+			d.Leave()
+		}
+		sf.Groups[k] = v
+	}
+	return
+}`
+	received := codeToString(g.decodeMap("TestType", nestedMapEntry))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_decodeEnum(t *testing.T) {
+	g := new(Generator)
+
+	expect := `package test
+
+import (
+	"errors"
+	mint "github.com/vinyl-linux/mint"
+)
+
+func (sf *TestEnum) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	b, err := d.ReadByte()
+	if err != nil {
+		return
+	}
+	*sf = TestEnum(b)
+	if *sf < 1 || *sf > 2 {
+		return errors.New("invalid value for type TestEnum")
+	}
+	return
+}
+func (sf *TestEnum) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+`
+	received := codeSliceToFile(g.decodeEnum(parser.Enum{
+		Name:   "TestEnum",
+		Values: []*parser.EnumEntry{{}, {}},
+	}))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
@@ -0,0 +1,411 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// The functions in this file generate marshallers and unmarshallers for
+// GeneratorOptions.DirectEncoding, which read and write native go values
+// directly rather than boxing each one in a scalar. The wire format is
+// identical either way.
+
+func (g Generator) marshallScalarDirect(e parser.AnnotatedEntry) []jen.Code {
+	return writeValue(e.DataType.Scalar.Type, fieldValue(e))
+}
+
+func (g Generator) marshallSliceArrayDirect(t string, e parser.AnnotatedEntry) jen.Code {
+	var (
+		dt      *parser.DataType
+		prelude []jen.Code
+	)
+
+	if e.Field.DataType == nil {
+		return jen.Null()
+	}
+
+	switch {
+	case e.Field.DataType.Slice != nil:
+		dt = e.Field.DataType.Slice.Type
+		prelude = writeScratch(jen.Qual(mintPath, "AppendUint32").Call(jen.Id("nil"), jen.Id("uint32").Call(jen.Id("len").Call(jen.Id("sf").Dot(e.Name)))), true)
+
+	case e.Field.DataType.FixedSizeSlice != nil:
+		dt = e.Field.DataType.FixedSizeSlice.Type
+
+		// b is only needed where there are scalars, or the lengths
+		// of nested collections, to append to it
+		if needsScratch(dt) {
+			prelude = []jen.Code{jen.Var().Id("b").Index().Byte()}
+		}
+
+	default:
+		return jen.Null()
+	}
+
+	return marshallerFunc(t, marshallerFuncName(e.Name), append(prelude,
+		jen.For(jen.List(jen.Id("_"), jen.Id("v")).Op(":=").Range().Id("sf").Dot(e.Name)).Block(
+			writeElement(dt, jen.Id("v"), 1)...,
+		),
+		jen.Return(),
+	)...)
+}
+
+func (g Generator) marshallMapDirect(t string, e parser.AnnotatedEntry) jen.Code {
+	return marshallerFunc(t, marshallerFuncName(e.Name), append(
+		writeScratch(jen.Qual(mintPath, "AppendUint32").Call(jen.Id("nil"), jen.Id("uint32").Call(jen.Id("len").Call(jen.Id("sf").Dot(e.Name)).Op("*").Lit(2))), true),
+		jen.For(jen.List(jen.Id("k"), jen.Id("v")).Op(":=").Range().Id("sf").Dot(e.Name)).Block(
+			append(
+				writeValue(e.DataType.Map.Key, jen.Id("k")),
+				writeElement(e.DataType.Map.Value, jen.Id("v"), 1)...,
+			)...,
+		),
+		jen.Return(),
+	)...)
+}
+
+func (g Generator) unmarshallScalarDirect(t string, e parser.AnnotatedEntry) jen.Code {
+	body := []jen.Code{
+		readValue(e.DataType.Scalar.Type, fieldValue(e), jen.Return()),
+		jen.Return(),
+	}
+
+	// optional fields are allocated before being read into
+	if e.Optional {
+		body = append([]jen.Code{
+			jen.Id("sf").Dot(e.Name).Op("=").New(toJenElemType(e.DataType.Scalar.Type)),
+		}, body...)
+	}
+
+	return unmarshallerFunc(t, unmarshallerFuncName(e.Name), body...)
+}
+
+func (g Generator) unmarshallSliceArrayDirect(t string, e parser.AnnotatedEntry) jen.Code {
+	var (
+		prelude []jen.Code
+		loop    jen.Code
+	)
+
+	if e.Field.DataType == nil {
+		return jen.Null()
+	}
+
+	switch {
+	case e.Field.DataType.Slice != nil:
+		dt := e.Field.DataType.Slice.Type
+		prelude = []jen.Code{
+			jen.List(jen.Id("l"), jen.Id("err")).Op(":=").Qual(mintPath, "ReadSliceLen").Call(jen.Id("r")),
+			jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
+				jen.Return(),
+			),
+			jen.If(jen.Id("l").Op("==").Lit(0)).Block(
+				jen.Id("sf").Dot(e.Name).Op("=").Id("nil"),
+				jen.Return(),
+			),
+			jen.Id("sf").Dot(e.Name).Op("=").Id("make").Call(jen.Index().Add(toJenDataType(dt)), jen.Lit(0), collectionCap(jen.Id("l"))),
+		}
+		loop = appendElements(readElement, dt, jen.Id("sf").Dot(e.Name), jen.Id("l"), 0, nil)
+
+	case e.Field.DataType.FixedSizeSlice != nil:
+		loop = jen.For(jen.Id("i").Op(":=").Range().Id("sf").Dot(e.Name)).Block(
+			readElement(e.Field.DataType.FixedSizeSlice.Type, jen.Id("sf").Dot(e.Name).Index(jen.Id("i")), 1, indexSegment(jen.Id("i")))...,
+		)
+
+	default:
+		return jen.Null()
+	}
+
+	prelude = append(prelude, enterCollection("dr", jen.Qual(mintPath, "AsDecodeReader").Call(jen.Id("r")))...)
+
+	return unmarshallerFunc(t, unmarshallerFuncName(e.Name), append(prelude,
+		loop,
+		jen.Return(),
+	)...)
+}
+
+func (g Generator) unmarshallMapDirect(t string, e parser.AnnotatedEntry) jen.Code {
+	prelude := append([]jen.Code{
+		jen.List(jen.Id("l"), jen.Id("err")).Op(":=").Qual(mintPath, "ReadMapLen").Call(jen.Id("r")),
+		jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(),
+		),
+		jen.If(jen.Id("l").Op("==").Lit(0)).Block(
+			jen.Id("sf").Dot(e.Name).Op("=").Id("nil"),
+			jen.Return(),
+		),
+		jen.Id("sf").Dot(e.Name).Op("=").Id("make").Call(toJenDataType(e.DataType), collectionCap(jen.Id("l"))),
+	}, enterCollection("dr", jen.Qual(mintPath, "AsDecodeReader").Call(jen.Id("r")))...)
+
+	return unmarshallerFunc(t, unmarshallerFuncName(e.Name), append(prelude,
+		jen.For(jen.Id("i").Op(":=").Lit(0), jen.Id("i").Op("<").Id("l"), jen.Id("i").Op("++")).Block(
+			append([]jen.Code{
+				jen.Var().Id("k").Add(toJenElemType(e.DataType.Map.Key)),
+				jen.Var().Id("v").Add(toJenDataType(e.DataType.Map.Value)),
+				readValue(e.DataType.Map.Key, jen.Id("k"), wrapReaderElementError(indexSegment(jen.Id("i")))),
+			}, append(
+				readElement(e.DataType.Map.Value, jen.Id("v"), 1, keySegment(jen.Id("k"))),
+				jen.Id("sf").Dot(e.Name).Index(jen.Id("k")).Op("=").Id("v"),
+			)...)...,
+		),
+		jen.Return(),
+	)...)
+}
+
+// enterCollection records, against the decoder named reader, that
+// decoding has descended into a collection field, leaving it again once
+// the field is decoded. Where from is non-nil, reader is declared as
+// from first. Collections are entered once their length is known to be
+// non-zero, as mint.SliceCollection and mint.MapCollection do, so that
+// both modes count depth the same way
+func enterCollection(reader string, from jen.Code) []jen.Code {
+	var code []jen.Code
+	if from != nil {
+		code = append(code, jen.Id(reader).Op(":=").Add(from))
+	}
+
+	return append(code,
+		jen.If(jen.Id("err").Op("=").Id(reader).Dot("Enter").Call().Id(";").Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(),
+		),
+		jen.Defer().Id(reader).Dot("Leave").Call(),
+	)
+}
+
+// enterElement wraps loop, which decodes the elements of a collection
+// nested within another, in calls to Enter and Leave on the decoder
+// named reader, running onErr should the collection be too deep.
+//
+// Leave can't be deferred from within a loop, and so each error loop
+// returns must leave this collection first; see leaveElements
+func enterElement(reader string, onErr []jen.Code, loop jen.Code) []jen.Code {
+	return []jen.Code{
+		jen.If(jen.Id("err").Op("=").Id(reader).Dot("Enter").Call().Id(";").Id("err").Op("!=").Id("nil")).Block(onErr...),
+		loop,
+		jen.Id(reader).Dot("Leave").Call(),
+	}
+}
+
+// leaveElements returns from a collection decoder from within leaves
+// nested collections entered with enterElement, leaving each of them
+// on the decoder named reader, and wrapping err with the path segments
+// segs, outermost first, against the offset of offsetter
+func leaveElements(reader, offsetter string, leaves int, segs []jen.Code) []jen.Code {
+	code := make([]jen.Code, 0, leaves+1)
+	for range leaves {
+		code = append(code, jen.Id(reader).Dot("Leave").Call())
+	}
+
+	return append(code, wrapSegments(offsetter, segs))
+}
+
+// collectionCap returns the capacity to allocate for a collection of
+// length l, which is capped so that a large declared length is only
+// allocated for as its elements actually arrive
+func collectionCap(l jen.Code) jen.Code {
+	return jen.Qual(mintPath, "CollectionCap").Call(l)
+}
+
+// elementReader reads an element of a collection, as readElement and
+// decodeElement do
+type elementReader func(dt *parser.DataType, v *jen.Statement, depth int, segs ...jen.Code) []jen.Code
+
+// appendElements reads l elements of mint type dt, at depth+1, with
+// read, and appends each to the slice v as it's read. segs are the path
+// segments of v itself
+func appendElements(read elementReader, dt *parser.DataType, v *jen.Statement, l jen.Code, depth int, segs []jen.Code) jen.Code {
+	i := elemId("i", depth)
+	e := elemId("e", depth)
+
+	return jen.For(jen.Add(i).Op(":=").Lit(0), jen.Add(i).Op("<").Add(l), jen.Add(i).Op("++")).Block(
+		append([]jen.Code{jen.Var().Add(e).Add(toJenDataType(dt))},
+			append(
+				read(dt, e, depth+1, append(segs, indexSegment(i))...),
+				jen.Add(v).Op("=").Append(v, e),
+			)...,
+		)...,
+	)
+}
+
+// marshallerFunc wraps body in a function which marshalls part of
+// type t to an io.Writer
+func marshallerFunc(t, fn string, body ...jen.Code) jen.Code {
+	return jen.Func().Params(jen.Id("sf").Id(t)).Id(fn).Params(jen.Id("w").Qual("io", "Writer")).Params(jen.Id("err").Id("error")).
+		Block(body...)
+}
+
+// unmarshallerFunc wraps body in a function which unmarshalls part of
+// type t from an io.Reader
+func unmarshallerFunc(t, fn string, body ...jen.Code) jen.Code {
+	return jen.Func().Params(jen.Id("sf").Op("*").Id(t)).Id(fn).Params(jen.Id("r").Qual("io", "Reader")).Params(jen.Id("err").Id("error")).
+		Block(body...)
+}
+
+// writeScratch sets the scratch buffer b to the output of appender, and
+// writes it to w. Where declare is true, b is declared here too
+func writeScratch(appender jen.Code, declare bool) []jen.Code {
+	op := "="
+	if declare {
+		op = ":="
+	}
+
+	return []jen.Code{
+		jen.Id("b").Op(op).Add(appender),
+		jen.If(jen.List(jen.Id("_"), jen.Id("err")).Op("=").Id("w").Dot("Write").Call(jen.Id("b")).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return()),
+	}
+}
+
+// writeValue writes v, of mint type dt, to w; scalars are appended to
+// the scratch buffer b and written from there, and anything else via
+// its own Marshall
+func writeValue(dt string, v *jen.Statement) []jen.Code {
+	if f := scalarToAppendJen(dt); f != nil {
+		return writeScratch(jen.Add(f).Call(jen.Id("b").Index(jen.Empty(), jen.Lit(0)), v), false)
+	}
+
+	return []jen.Code{
+		jen.If(jen.Id("err").Op("=").Add(v).Dot("Marshall").Call(jen.Id("w")).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return()),
+	}
+}
+
+// readValue reads v, of mint type dt, from r; scalars are read with
+// the relevant mint.ReadX function, and anything else via its own
+// Unmarshall
+func readValue(dt string, v *jen.Statement, onErr ...jen.Code) jen.Code {
+	return readValueFrom("r", dt, v, onErr...)
+}
+
+// readValueFrom is readValue for a reader named reader, rather than r
+func readValueFrom(reader, dt string, v *jen.Statement, onErr ...jen.Code) jen.Code {
+	if f := scalarToDecoderFunc(dt); f != "" {
+		return jen.If(jen.List(v, jen.Id("err")).Op("=").Qual(mintPath, f).Call(jen.Id(reader)).Id(";").Id("err").Op("!=").Id("nil")).Block(onErr...)
+	}
+
+	return jen.If(jen.Id("err").Op("=").Add(v).Dot("Unmarshall").Call(jen.Id(reader)).Id(";").Id("err").Op("!=").Id("nil")).Block(onErr...)
+}
+
+// wrapReaderElementError returns from a collection unmarshaller, wrapping
+// err with the path segments segs, outermost first
+func wrapReaderElementError(segs ...jen.Code) jen.Code {
+	return wrapSegments("r", segs)
+}
+
+// wrapSegments returns err, wrapped with each of the path segments segs,
+// outermost first, against the offset of reader
+func wrapSegments(reader string, segs []jen.Code) jen.Code {
+	var err jen.Code = jen.Id("err")
+	for i := len(segs) - 1; i >= 0; i-- {
+		err = jen.Qual(mintPath, "WrapDecodeError").Call(jen.Id(reader), jen.Lit(""), segs[i], err)
+	}
+
+	return jen.Return(err)
+}
+
+// needsScratch returns true where writing a value of mint type dt
+// appends to the scratch buffer b; that is, where dt is a scalar, or
+// contains scalars or collections with a length prefix
+func needsScratch(dt *parser.DataType) bool {
+	switch {
+	case dt.Scalar != nil:
+		return scalarToAppendJen(dt.Scalar.Type) != nil
+
+	case dt.FixedSizeSlice != nil:
+		return needsScratch(dt.FixedSizeSlice.Type)
+	}
+
+	return true
+}
+
+// writeElement writes v, an element of a collection of mint type dt, to
+// w. Where v is itself a collection it's written as a field would be,
+// ranging over its own elements with variables named for depth
+func writeElement(dt *parser.DataType, v *jen.Statement, depth int) []jen.Code {
+	switch {
+	case dt.Slice != nil:
+		return append(
+			writeScratch(jen.Qual(mintPath, "AppendUint32").Call(jen.Id("b").Index(jen.Empty(), jen.Lit(0)), jen.Id("uint32").Call(jen.Id("len").Call(v))), false),
+			jen.For(jen.List(jen.Id("_"), elemId("v", depth)).Op(":=").Range().Add(v)).Block(
+				writeElement(dt.Slice.Type, elemId("v", depth), depth+1)...,
+			),
+		)
+
+	case dt.FixedSizeSlice != nil:
+		return []jen.Code{
+			jen.For(jen.List(jen.Id("_"), elemId("v", depth)).Op(":=").Range().Add(v)).Block(
+				writeElement(dt.FixedSizeSlice.Type, elemId("v", depth), depth+1)...,
+			),
+		}
+
+	case dt.Map != nil:
+		return append(
+			writeScratch(jen.Qual(mintPath, "AppendUint32").Call(jen.Id("b").Index(jen.Empty(), jen.Lit(0)), jen.Id("uint32").Call(jen.Id("len").Call(v).Op("*").Lit(2))), false),
+			jen.For(jen.List(elemId("k", depth), elemId("v", depth)).Op(":=").Range().Add(v)).Block(
+				append(
+					writeValue(dt.Map.Key, elemId("k", depth)),
+					writeElement(dt.Map.Value, elemId("v", depth), depth+1)...,
+				)...,
+			),
+		)
+	}
+
+	return writeValue(dt.Scalar.Type, v)
+}
+
+// readElement reads v, an element of a collection of mint type dt found
+// at the path segments segs, from r. Where v is itself a collection it's
+// read as a field would be, ranging over its own elements with variables
+// named for depth, and with depth-1 nested collections already entered
+func readElement(dt *parser.DataType, v *jen.Statement, depth int, segs ...jen.Code) []jen.Code {
+	l := elemId("l", depth)
+	i := elemId("i", depth)
+	onErr := leaveElements("dr", "r", depth-1, segs)
+
+	// cap segs, so that appending to it for each element copies
+	// rather than sharing a backing array between siblings
+	segs = segs[:len(segs):len(segs)]
+
+	switch {
+	case dt.Slice != nil:
+		return []jen.Code{
+			jen.List(l, jen.Id("err")).Op(":=").Qual(mintPath, "ReadSliceLen").Call(jen.Id("r")),
+			jen.If(jen.Id("err").Op("!=").Id("nil")).Block(onErr...),
+			jen.If(jen.Add(l).Op("==").Lit(0)).Block(
+				jen.Add(v).Op("=").Id("nil"),
+			).Else().Block(
+				append([]jen.Code{jen.Add(v).Op("=").Id("make").Call(toJenDataType(dt), jen.Lit(0), collectionCap(l))},
+					enterElement("dr", onErr, appendElements(readElement, dt.Slice.Type, v, l, depth, segs))...,
+				)...,
+			),
+		}
+
+	case dt.FixedSizeSlice != nil:
+		return enterElement("dr", onErr, jen.For(jen.Add(i).Op(":=").Range().Add(v)).Block(
+			readElement(dt.FixedSizeSlice.Type, jen.Add(v).Index(i), depth+1, append(segs, indexSegment(i))...)...,
+		))
+
+	case dt.Map != nil:
+		k := elemId("k", depth)
+		mv := elemId("v", depth)
+
+		return []jen.Code{
+			jen.List(l, jen.Id("err")).Op(":=").Qual(mintPath, "ReadMapLen").Call(jen.Id("r")),
+			jen.If(jen.Id("err").Op("!=").Id("nil")).Block(onErr...),
+			jen.If(jen.Add(l).Op("==").Lit(0)).Block(
+				jen.Add(v).Op("=").Id("nil"),
+			).Else().Block(
+				append([]jen.Code{jen.Add(v).Op("=").Id("make").Call(toJenDataType(dt), collectionCap(l))},
+					enterElement("dr", onErr, jen.For(jen.Add(i).Op(":=").Lit(0), jen.Add(i).Op("<").Add(l), jen.Add(i).Op("++")).Block(
+						append([]jen.Code{
+							jen.Var().Add(k).Add(toJenElemType(dt.Map.Key)),
+							jen.Var().Add(mv).Add(toJenDataType(dt.Map.Value)),
+							readValue(dt.Map.Key, k, leaveElements("dr", "r", depth, append(segs, indexSegment(i)))...),
+						}, append(
+							readElement(dt.Map.Value, mv, depth+1, append(segs, keySegment(k))...),
+							jen.Add(v).Index(k).Op("=").Add(mv),
+						)...)...,
+					))...,
+				)...,
+			),
+		}
+	}
+
+	return []jen.Code{readValue(dt.Scalar.Type, v, onErr...)}
+}
@@ -0,0 +1,314 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/vinyl-linux/mint/parser"
+)
+
+var directGenerator = &Generator{GeneratorOptions: GeneratorOptions{DirectEncoding: true}}
+
+func TestGenerator_generateMarshaller_Direct(t *testing.T) {
+	expect := `package test
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+func (sf SomeTestType) marshallSomeStringSlice(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.SomeStringSlice)))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for _, v := range sf.SomeStringSlice {
+		b = mint.AppendString(b[:0], v)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf SomeTestType) marshallSomeStringSlice(w io.Writer) (err error) {
+	var b []byte
+	for _, v := range sf.SomeStringSlice {
+		b = mint.AppendString(b[:0], v)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf SomeTestType) marshallSomeStringSlice(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.SomeStringSlice)*2))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for k, v := range sf.SomeStringSlice {
+		b = mint.AppendString(b[:0], k)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+		b = mint.AppendInt64(b[:0], v)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+	}
+	return
+}
+func (sf SomeTestType) Marshall(w io.Writer) (err error) {
+	var b []byte
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if err = sf.marshallSomeStringSlice(w); err != nil {
+		return
+	}
+	if err = sf.marshallSomeStringSlice(w); err != nil {
+		return
+	}
+	if err = sf.marshallSomeStringSlice(w); err != nil {
+		return
+	}
+	b = mint.AppendUuid(b[:0], sf.ATypeOfSomeType)
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	if err = sf.Thingy.Marshall(w); err != nil {
+		return
+	}
+	return
+}
+`
+	received := codeSliceToFile(directGenerator.generateMarshaller(simpleType))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_marshallSliceArray_Direct(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		ae     parser.AnnotatedEntry
+		expect string
+	}{
+		{"Bad input does nothing", parser.AnnotatedEntry{}, ""},
+		{"Non-slice returns nothing", parser.AnnotatedEntry{Field: parser.Field{DataType: &parser.DataType{}}}, ""},
+		{"Complex types marshall themselves", userDefinedSliceEntry, `func (sf TestType) marshallThingy(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.Thingy)))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for _, v := range sf.Thingy {
+		if err = v.Marshall(w); err != nil {
+			return
+		}
+	}
+	return
+}`},
+		{"Arrays of slices write each length", nestedArrayEntry, `func (sf TestType) marshallHalves(w io.Writer) (err error) {
+	var b []byte
+	for _, v := range sf.Halves {
+		b = mint.AppendUint32(b[:0], uint32(len(v)))
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+		for _, v1 := range v {
+			b = mint.AppendString(b[:0], v1)
+			if _, err = w.Write(b); err != nil {
+				return
+			}
+		}
+	}
+	return
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			received := codeToString(directGenerator.marshallSliceArray("TestType", test.ae))
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
+
+func TestGenerator_marshallMap_Direct(t *testing.T) {
+	expect := `func (sf TestType) marshallThingy(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.Thingy)*2))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for k, v := range sf.Thingy {
+		if err = k.Marshall(w); err != nil {
+			return
+		}
+		b = mint.AppendBool(b[:0], v)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+	}
+	return
+}`
+	received := codeToString(directGenerator.marshallMap("TestType", complexToBuiltinMap))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_unmarshallSliceArray_Direct(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		ae     parser.AnnotatedEntry
+		expect string
+	}{
+		{"Bad input does nothing", parser.AnnotatedEntry{}, ""},
+		{"Non-slice returns nothing", parser.AnnotatedEntry{Field: parser.Field{DataType: &parser.DataType{}}}, ""},
+		{"Non-fixed length slice reads length", nonFixedLengthSlice, `func (sf *TestType) unmarshallSomeStringSlice(r io.Reader) (err error) {
+	l, err := mint.ReadSliceLen(r)
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.SomeStringSlice = nil
+		return
+	}
+	sf.SomeStringSlice = make([]string, 0, mint.CollectionCap(l))
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := 0; i < l; i++ {
+		var e string
+		if e, err = mint.ReadString(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		sf.SomeStringSlice = append(sf.SomeStringSlice, e)
+	}
+	return
+}`},
+		{"Fixed length slice reads straight into array", fixedLengthSlice, `func (sf *TestType) unmarshallSomeStringSlice(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := range sf.SomeStringSlice {
+		if sf.SomeStringSlice[i], err = mint.ReadString(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+	}
+	return
+}`},
+		{"Arrays of slices read each length", nestedArrayEntry, `func (sf *TestType) unmarshallHalves(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := range sf.Halves {
+		l1, err := mint.ReadSliceLen(r)
+		if err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		if l1 == 0 {
+			sf.Halves[i] = nil
+		} else {
+			sf.Halves[i] = make([]string, 0, mint.CollectionCap(l1))
+			if err = dr.Enter(); err != nil {
+				return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+			}
+			for i1 := 0; i1 < l1; i1++ {
+				var e1 string
+				if e1, err = mint.ReadString(r); err != nil {
+					dr.Leave()
+					return mint.WrapDecodeError(r, "", mint.IndexSegment(i), mint.WrapDecodeError(r, "", mint.IndexSegment(i1), err))
+				}
+				sf.Halves[i] = append(sf.Halves[i], e1)
+			}
+			dr.Leave()
+		}
+	}
+	return
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			received := codeToString(directGenerator.unmarshallSliceArray("TestType", test.ae))
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
+
+func TestGenerator_unmarshallMap_Direct(t *testing.T) {
+	expect := `func (sf *TestType) unmarshallThingy(r io.Reader) (err error) {
+	l, err := mint.ReadMapLen(r)
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Thingy = nil
+		return
+	}
+	sf.Thingy = make(map[BlahType]bool, mint.CollectionCap(l))
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := 0; i < l; i++ {
+		var k BlahType
+		var v bool
+		if err = k.Unmarshall(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		if v, err = mint.ReadBool(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.KeySegment(k), err)
+		}
+		sf.Thingy[k] = v
+	}
+	return
+}`
+	received := codeToString(directGenerator.unmarshallMap("TestType", complexToBuiltinMap))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_unmarshallScalar_Direct(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		ae     parser.AnnotatedEntry
+		expect string
+	}{
+		{"Builtin scalars are read directly", scalarEntry, `func (sf *TestType) unmarshallATypeOfSomeType(r io.Reader) (err error) {
+	if sf.ATypeOfSomeType, err = mint.ReadUuid(r); err != nil {
+		return
+	}
+	return
+}`},
+		{"Complex types unmarshall themselves", userDefinedScalarEntry, `func (sf *TestType) unmarshallThingy(r io.Reader) (err error) {
+	if err = sf.Thingy.Unmarshall(r); err != nil {
+		return
+	}
+	return
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			received := codeToString(directGenerator.unmarshallScalar("TestType", test.ae))
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
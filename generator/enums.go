@@ -18,9 +18,17 @@ func (g *Generator) generateForEnum(t parser.Enum) (err error) {
 	// Create values
 	ret.Add(g.generateEnumValues(t))
 
-	// Create marshaller, unmarshaller, valuer
+	// Create validator, marshaller, appender, sizer, unmarshallers, valuer
+	ret.Add(g.validateEnum(t))
 	ret.Add(g.marshallEnum(t))
+	ret.Add(g.appendEnum(t))
+	ret.Add(g.sizeEnum(t))
 	ret.Add(g.unmarshallEnum(t))
+
+	for _, d := range g.decodeEnum(t) {
+		ret.Add(d)
+	}
+
 	ret.Add(g.generateValuer(t.Name))
 
 	return ret.Save(filepath.Join(g.Directory, strings.Join([]string{strings.ToLower(t.Name), "go"}, ".")))
@@ -42,6 +50,18 @@ func (g *Generator) generateEnumValues(t parser.Enum) jen.Code {
 	return jen.Null().Const().Defs(consts...)
 }
 
+// validateEnum creates an implementation of the mint.Validator interface,
+// ensuring a value is one of those defined for the enum
+func (g *Generator) validateEnum(e parser.Enum) jen.Code {
+	return jen.Func().Params(jen.Id("sf").Id(e.Name)).Id("Validate").Params().Params(jen.Id("error")).
+		Block(
+			jen.If(jen.Id("sf").Op("<").Lit(1).Op("||").Id("sf").Op(">").Lit(len(e.Values))).Block(
+				jen.Return(jen.Qual("errors", "New").Call(jen.Lit("invalid value for type "+e.Name))),
+			),
+			jen.Return(jen.Id("nil")),
+		)
+}
+
 func enumValue(en string, ee *parser.EnumEntry) string {
 	return enumValueString(en, ee.Value.Key)
 }
@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// generateFingerprint creates a const holding the fingerprint of at,
+// along with an implementation of the mint.Fingerprinter interface,
+// allowing at to be written to, and read from, envelopes
+func (g *Generator) generateFingerprint(at parser.AnnotatedType) []jen.Code {
+	name := fingerprintName(at.Name)
+
+	return []jen.Code{
+		jen.Null().Comment(name + " identifies the encoding of " + at.Name + ", changing whenever the name, tag, or type of any of its fields, or anything those fields refer to, does").Line().
+			Const().Id(name).Uint64().Op("=").Op(fmt.Sprintf("%#016x", at.Fingerprint)),
+
+		jen.Func().Params(jen.Id("sf").Id(at.Name)).Id("TypeName").Params().Params(jen.String()).Block(
+			jen.Return(jen.Lit(g.typeName(at.Name))),
+		),
+
+		jen.Func().Params(jen.Id("sf").Id(at.Name)).Id("Fingerprint").Params().Params(jen.Uint64()).Block(
+			jen.Return(jen.Id(name)),
+		),
+	}
+}
+
+// fingerprintName returns the name of the const holding the fingerprint
+// of type t
+func fingerprintName(t string) string {
+	return t + "Fingerprint"
+}
+
+// typeName returns the name of type t as written in envelopes, which is
+// qualified by the package t belongs to, where it belongs to one, such
+// as geo.Location
+func (g *Generator) typeName(t string) string {
+	if g.ast == nil || g.ast.Package == "" {
+		return t
+	}
+
+	return g.ast.Package + "." + t
+}
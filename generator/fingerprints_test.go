@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/vinyl-linux/mint/parser"
+)
+
+func TestGenerator_generateFingerprint(t *testing.T) {
+	at := parser.AnnotatedType{
+		Name:        "TestType",
+		Fingerprint: 0x00c0ffee,
+	}
+
+	for _, test := range []struct {
+		name   string
+		ast    *parser.AST
+		expect string
+	}{
+		{"Without a package", nil, `package test
+
+// TestTypeFingerprint identifies the encoding of TestType, changing whenever the name, tag, or type of any of its fields, or anything those fields refer to, does
+const TestTypeFingerprint uint64 = 0x0000000000c0ffee
+
+func (sf TestType) TypeName() string {
+	return "TestType"
+}
+func (sf TestType) Fingerprint() uint64 {
+	return TestTypeFingerprint
+}
+`},
+		{"With a package", &parser.AST{Package: "weather"}, `package test
+
+// TestTypeFingerprint identifies the encoding of TestType, changing whenever the name, tag, or type of any of its fields, or anything those fields refer to, does
+const TestTypeFingerprint uint64 = 0x0000000000c0ffee
+
+func (sf TestType) TypeName() string {
+	return "weather.TestType"
+}
+func (sf TestType) Fingerprint() uint64 {
+	return TestTypeFingerprint
+}
+`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g := new(Generator)
+			g.ast = test.ast
+
+			received := codeSliceToFile(g.generateFingerprint(at))
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
@@ -31,3 +31,15 @@ func marshallerFuncName(s string) string {
 func unmarshallerFuncName(s string) string {
 	return fmt.Sprintf("unmarshall%s", s)
 }
+
+func appenderFuncName(s string) string {
+	return fmt.Sprintf("append%s", s)
+}
+
+func decoderFuncName(s string) string {
+	return fmt.Sprintf("decode%s", s)
+}
+
+func sizerFuncName(s string) string {
+	return fmt.Sprintf("size%s", s)
+}
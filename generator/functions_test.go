@@ -40,3 +40,30 @@ func TestUnmarshallerFuncName(t *testing.T) {
 		t.Errorf("expected %q, received %q", expect, received)
 	}
 }
+
+func TestAppenderFuncName(t *testing.T) {
+	expect := "appendField"
+	received := appenderFuncName("Field")
+
+	if expect != received {
+		t.Errorf("expected %q, received %q", expect, received)
+	}
+}
+
+func TestDecoderFuncName(t *testing.T) {
+	expect := "decodeField"
+	received := decoderFuncName("Field")
+
+	if expect != received {
+		t.Errorf("expected %q, received %q", expect, received)
+	}
+}
+
+func TestSizerFuncName(t *testing.T) {
+	expect := "sizeField"
+	received := sizerFuncName("Field")
+
+	if expect != received {
+		t.Errorf("expected %q, received %q", expect, received)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/dave/jennifer/jen"
@@ -12,6 +13,7 @@ import (
 
 const (
 	mintPath = "github.com/vinyl-linux/mint"
+	uuidPath = "github.com/gofrs/uuid/v5"
 )
 
 var (
@@ -24,6 +26,11 @@ type GeneratorOptions struct {
 	Directory               string
 	CustomFunctionSkeletons bool
 	Clobber                 bool
+
+	// DirectEncoding generates marshallers and unmarshallers which
+	// read and write native go values directly, rather than boxing
+	// each value (and each element of a collection) in a scalar
+	DirectEncoding bool
 }
 
 type Generator struct {
@@ -33,10 +40,19 @@ type Generator struct {
 	customFunctions []jen.Code
 }
 
+// New returns a Generator for doc.
+//
+// Types imported from other packages are referred to by the go_package
+// option of those packages, and so New returns an error where an imported
+// package doesn't set one
 func New(doc *parser.AST, options *GeneratorOptions) (g *Generator, err error) {
 	g = new(Generator)
 	g.GeneratorOptions = *options
-	g.ast = doc
+
+	g.ast, err = qualifyImports(doc)
+	if err != nil {
+		return nil, err
+	}
 
 	return
 }
@@ -45,12 +61,14 @@ func New(doc *parser.AST, options *GeneratorOptions) (g *Generator, err error) {
 //
 //  1. Type definitions
 //  2. Enums
-//  3. Validations
-//  4. Transforms
-//  5. Unmarshallers; and
-//  6. Marshallers
+//  3. Unions
+//  4. Validations
+//  5. Transforms
+//  6. Unmarshallers; and
+//  7. Marshallers
 //
-// For each type defined in an AST
+// For each type defined in an AST, along with a const block holding
+// any consts
 func (g *Generator) Generate() (err error) {
 	if g.MakeDirectory {
 		err = os.MkdirAll(g.Directory, 0750)
@@ -59,6 +77,13 @@ func (g *Generator) Generate() (err error) {
 		}
 	}
 
+	if len(g.ast.Consts) > 0 {
+		err = g.generateForConsts(g.ast.Consts)
+		if err != nil {
+			return
+		}
+	}
+
 	for _, t := range g.ast.Types {
 		err = g.generateForType(t)
 		if err != nil {
@@ -73,6 +98,13 @@ func (g *Generator) Generate() (err error) {
 		}
 	}
 
+	for _, u := range g.ast.Unions {
+		err = g.generateForUnion(u)
+		if err != nil {
+			return
+		}
+	}
+
 	return
 }
 
@@ -100,44 +132,224 @@ func (g Generator) writeSkeletons(tn string) (err error) {
 // generateValidations creates calls to both mint and custom
 // validations, additionally templating custom validations were
 // requested
+//
+// Optional fields are only validated when set, and so their validations
+// are collected separately, behind a nil check.
+//
+// Collections of user defined types, enums, and unions additionally have
+// each of their elements validated
 func (g *Generator) generateValidations(at parser.AnnotatedType) (c jen.Code) {
 	functionCalls := make([]jen.Code, 0)
+	optionalCalls := make([]jen.Code, 0)
+	elementCalls := make([]jen.Code, 0)
+
 	for _, e := range at.Entries {
+		elementCalls = append(elementCalls, validateElements(e)...)
+
+		calls := make([]jen.Code, 0)
+
 		for _, f := range e.Validations {
 			fn := toGoFuncName(f.IsCustom, f.Function)
 
 			if g.CustomFunctionSkeletons && f.IsCustom {
-				g.customFunctions = append(g.customFunctions, g.generateSkeletonValidation(at.Name, f.Function))
+				g.customFunctions = append(g.customFunctions, g.generateSkeletonValidation(at.Name, f.Function, len(f.Args)))
+			}
+
+			args := []jen.Code{jen.Lit(e.Name), optionalValue(e)}
+			for _, a := range f.Args {
+				args = append(args, valueLiteral(*a))
 			}
 
-			functionCalls = append(functionCalls, fn.Call(jen.Lit(e.Name), jen.Id("sf").Dot(e.Name)))
+			calls = append(calls, fn.Call(args...))
+		}
+
+		switch {
+		case len(calls) == 0:
+			continue
+
+		case e.Optional:
+			optionalCalls = append(optionalCalls, ifPresent(e, validationLoop(calls...))...)
+
+		default:
+			functionCalls = append(functionCalls, calls...)
 		}
 	}
 
+	body := []jen.Code{
+		jen.Id("errors").Op(":=").Id("make").Call(jen.Index().Id("error"), jen.Lit(0)),
+		validationLoop(functionCalls...),
+	}
+
+	body = append(body, optionalCalls...)
+	body = append(body, elementCalls...)
+	body = append(body, jen.Return().Qual(mintPath, "ValidationErrors").Call(jen.Lit(at.Name), jen.Id("errors")))
+
 	return jen.Func().Params(jen.Id("sf").Id(at.Name)).
 		Id("Validate").Params().Params(jen.Id("error")).
+		Block(body...)
+}
+
+// validateElements validates each element of collection e which is of
+// a user defined type, enum, or union, by calling its Validate function.
+//
+// Elements have already been transformed, by Transform, which runs
+// first. Where e holds a map, which is ranged over in no particular
+// order, the errors found are sorted by path, so that they're returned
+// in the same order each time
+func validateElements(e parser.AnnotatedEntry) []jen.Code {
+	loops := validateCollection(e.DataType, jen.Id("sf").Dot(e.Name), jen.Lit(e.Name), 0)
+	if len(loops) == 0 || !hasMap(e.DataType) {
+		return loops
+	}
+
+	body := []jen.Code{jen.Id("from").Op(":=").Len(jen.Id("errors"))}
+	body = append(body, loops...)
+	body = append(body, jen.Qual(mintPath, "SortInvalidElements").Call(jen.Id("errors").Index(jen.Id("from").Op(":"))))
+
+	return []jen.Code{jen.Block(body...)}
+}
+
+// hasMap returns true where dt is, or holds, a map
+func hasMap(dt *parser.DataType) bool {
+	for ; dt != nil; dt = dt.Elem() {
+		if dt.Map != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateCollection ranges over v, a collection of mint type dt found at
+// path, validating each element, and recursing into nested collections.
+//
+// Slices and arrays are indexed, rather than ranged over by value, so
+// that elements aren't copied, and paths are only built for elements
+// which fail validation, so that valid collections are validated
+// without allocating
+func validateCollection(dt *parser.DataType, v, path jen.Code, depth int) []jen.Code {
+	elem := dt.Elem()
+	if elem == nil {
+		return nil
+	}
+
+	var (
+		key    string
+		index  = elemId("i", depth)
+		seg    = indexSegment
+		elemV  = jen.Add(v).Index(index)
+		isMap  = dt.Map != nil
+		header jen.Code
+	)
+
+	if isMap {
+		key = dt.Map.Key
+		index = elemId("k", depth)
+		seg = keySegment
+		elemV = elemId("v", depth)
+	}
+
+	elemPath := jen.Add(path).Op("+").Add(seg(index))
+
+	var valueBody []jen.Code
+	switch {
+	case elem.IsCollection():
+		valueBody = validateCollection(elem, elemV, elemPath, depth+1)
+
+	case isUserType(elem.Scalar.Type):
+		valueBody = []jen.Code{validateElement(elemPath, elemV)}
+	}
+
+	body := make([]jen.Code, 0)
+	if isUserType(key) {
+		body = append(body, validateElement(elemPath, index))
+	}
+
+	body = append(body, valueBody...)
+
+	switch {
+	case len(body) == 0:
+		return nil
+
+	case isMap && len(valueBody) > 0:
+		header = jen.List(index, elemId("v", depth)).Op(":=").Range().Add(v)
+
+	default:
+		header = jen.Add(index).Op(":=").Range().Add(v)
+	}
+
+	return []jen.Code{jen.For(header).Block(body...)}
+}
+
+// validateElement validates element v, found at path, building path only
+// where v is invalid
+func validateElement(path, v jen.Code) jen.Code {
+	return jen.If(
+		jen.Id("err").Op(":=").Add(v).Dot("Validate").Call(),
+		jen.Id("err").Op("!=").Id("nil"),
+	).Block(
+		jen.Id("errors").Op("=").Id("append").Call(jen.Id("errors"), invalidElement(path)),
+	)
+}
+
+// invalidElement wraps err, returned validating the element found at
+// path, in a mint.ErrInvalidElement
+func invalidElement(path jen.Code) jen.Code {
+	return jen.Qual(mintPath, "ErrInvalidElement").Values(
+		jen.Id("Path").Op(":").Add(path),
+		jen.Id("Err").Op(":").Id("err"),
+	)
+}
+
+// indexSegment describes element i of a slice or array as a path segment
+func indexSegment(i jen.Code) jen.Code {
+	return jen.Qual(mintPath, "IndexSegment").Call(i)
+}
+
+// keySegment describes the entry with key k of a map as a path segment
+func keySegment(k jen.Code) jen.Code {
+	return jen.Qual(mintPath, "KeySegment").Call(k)
+}
+
+// isUserType returns true where t is a user defined type, enum, or union,
+// rather than a builtin scalar
+func isUserType(t string) bool {
+	if t == "" {
+		return false
+	}
+
+	_, ok := parser.Scalars[t]
+
+	return !ok
+}
+
+// validationLoop runs each of functionCalls, appending any errors they
+// return to errors
+func validationLoop(functionCalls ...jen.Code) jen.Code {
+	return jen.For(jen.List(jen.Id("_"), jen.Id("err")).Op(":=").Range().Index().Id("error").Values(
+		functionCalls...,
+	)).
 		Block(
-			jen.Id("errors").Op(":=").Id("make").Call(jen.Index().Id("error"), jen.Lit(0)),
-			jen.For(jen.List(jen.Id("_"), jen.Id("err")).Op(":=").Range().Index().Id("error").Values(
-				functionCalls...,
-			)).
-				Block(
-					jen.If(
-						jen.Id("err").Op("!=").Id("nil")).Block(
-						jen.Id("errors").Op("=").Id("append").Call(
-							jen.Id("errors"), jen.Id("err")),
-					),
-				),
-			jen.Return().Qual(mintPath, "ValidationErrors").Call(jen.Lit(at.Name), jen.Id("errors")),
+			jen.If(
+				jen.Id("err").Op("!=").Id("nil")).Block(
+				jen.Id("errors").Op("=").Id("append").Call(
+					jen.Id("errors"), jen.Id("err")),
+			),
 		)
 }
 
 // generateTransformations creates calls to both mint and custom
 // transformations, additionally templating custom transformations were
-// requested
+// requested.
+//
+// Fields of user defined types, and collections of them, additionally
+// have each of their values transformed, once the field itself is, so
+// that Validate and MarshalledSize see them as they'll be encoded
 func (g *Generator) generateTransformations(at parser.AnnotatedType) jen.Code {
 	functionCalls := make([]jen.Code, 0)
 	for _, e := range at.Entries {
+		calls := make([]jen.Code, 0)
+
 		for _, f := range e.Transformations {
 			fn := toGoFuncName(f.IsCustom, f.Function)
 
@@ -145,13 +357,25 @@ func (g *Generator) generateTransformations(at parser.AnnotatedType) jen.Code {
 				g.customFunctions = append(g.customFunctions, g.generateSkeletonTransform(at.Name, f.Function))
 			}
 
-			functionCalls = append(functionCalls,
-				jen.List(jen.Id("sf").Dot(e.Name), jen.Id("err")).Op("=").Add(fn).Call(jen.Id("sf").Dot(e.Name)),
+			calls = append(calls,
+				jen.List(optionalValue(e), jen.Id("err")).Op("=").Add(fn).Call(optionalValue(e)),
 				jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
 					jen.Return(),
 				),
 			)
 		}
+
+		if len(calls) > 0 {
+			functionCalls = append(functionCalls, ifPresent(e, calls...)...)
+		}
+
+		switch {
+		case e.DataType.Scalar == nil:
+			functionCalls = append(functionCalls, transformCollection(e.DataType, jen.Id("sf").Dot(e.Name), 0)...)
+
+		case isUserType(e.DataType.Scalar.Type):
+			functionCalls = append(functionCalls, ifPresent(e, transformElement(fieldRef(e)))...)
+		}
 	}
 
 	functionCalls = append(functionCalls, jen.Return())
@@ -162,14 +386,95 @@ func (g *Generator) generateTransformations(at parser.AnnotatedType) jen.Code {
 		)
 }
 
+// transformCollection ranges over v, a collection of mint type dt,
+// transforming each element of a user defined type, and recursing into
+// nested collections. Map values are copies, and so are written back
+// once transformed, other than slices and maps, which share what they
+// hold with the copy
+func transformCollection(dt *parser.DataType, v jen.Code, depth int) []jen.Code {
+	elem := dt.Elem()
+	if elem == nil {
+		return nil
+	}
+
+	var (
+		index = elemId("i", depth)
+		elemV = jen.Add(v).Index(index)
+		body  []jen.Code
+	)
+
+	if dt.Map != nil {
+		index = elemId("k", depth)
+		elemV = elemId("v", depth)
+	}
+
+	switch {
+	case elem.IsCollection():
+		body = transformCollection(elem, elemV, depth+1)
+
+	case isUserType(elem.Scalar.Type):
+		body = []jen.Code{transformElement(jen.Op("&").Add(elemV))}
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	if dt.Map != nil {
+		if elem.Slice == nil && elem.Map == nil {
+			body = append(body, jen.Add(v).Index(index).Op("=").Add(elemV))
+		}
+
+		return []jen.Code{jen.For(jen.List(index, elemV).Op(":=").Range().Add(v)).Block(body...)}
+	}
+
+	return []jen.Code{jen.For(jen.Add(index).Op(":=").Range().Add(v)).Block(body...)}
+}
+
+// transformElement transforms ptr, a pointer to a value of a user
+// defined type, where that type can be transformed
+func transformElement(ptr jen.Code) jen.Code {
+	return jen.If(jen.Id("err").Op("=").Qual(mintPath, "Transform").Call(ptr).Id(";").Id("err").Op("!=").Id("nil")).Block(
+		jen.Return(),
+	)
+}
+
+// fieldRef returns a pointer to field e; optional fields already are
+// pointers
+func fieldRef(e parser.AnnotatedEntry) jen.Code {
+	if e.Optional {
+		return jen.Id("sf").Dot(e.Name)
+	}
+
+	return jen.Op("&").Id("sf").Dot(e.Name)
+}
+
 // generateUnmarshaller will:
 //  1. Create an unmarshall function per field
 //  2. Create an implementation of the mint.Unmarshaller interface for this type
+//
+// The reader passed to Unmarshall is wrapped in a mint.DecodeReader so that
+// decoding limits, such as nesting depth, are enforced across nested types
 func (g *Generator) generateUnmarshaller(at parser.AnnotatedType) (j []jen.Code) {
-	functionCalls := make([]jen.Code, 0)
+	functionCalls := []jen.Code{
+		jen.Id("dr").Op(":=").Qual(mintPath, "AsDecodeReader").Call(jen.Id("r")),
+		jen.If(jen.Id("err").Op("=").Id("dr").Dot("Enter").Call().Id(";").Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(),
+		),
+		jen.Defer().Id("dr").Dot("Leave").Call(),
+	}
 	j = make([]jen.Code, 0)
 
-	for _, entry := range at.Entries {
+	if at.Evolvable {
+		functionCalls = append(functionCalls, enterBody(at.Name, "dr")...)
+	}
+
+	bits, optionals := presenceBits(at)
+	if optionals > 0 || at.Evolvable {
+		functionCalls = append(functionCalls, readPresence(at, "dr")...)
+	}
+
+	for _, entry := range at.ByTag() {
 		switch {
 		case entry.DataType.Scalar != nil:
 			j = append(j, g.unmarshallScalar(at.Name, entry))
@@ -186,11 +491,21 @@ func (g *Generator) generateUnmarshaller(at parser.AnnotatedType) (j []jen.Code)
 		}
 
 		fn := jen.Id("sf").Dot(unmarshallerFuncName(entry.Name))
-		functionCalls = append(functionCalls,
-			jen.If(jen.Id("err").Op("=").Add(fn).Call(jen.Id("r")).Id(";").Id("err").Op("!=").Id("nil")).Block(
-				jen.Return(),
-			),
+		call := jen.If(jen.Id("err").Op("=").Add(fn).Call(jen.Id("dr")).Id(";").Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(wrapDecodeError(at.Name, entry.Name)),
 		)
+
+		if bit, ok := bits[entry.Name]; ok {
+			functionCalls = append(functionCalls, ifMore(at, "dr", ifPresentBit(entry, bit, call)...)...)
+
+			continue
+		}
+
+		functionCalls = append(functionCalls, ifMore(at, "dr", call)...)
+	}
+
+	if at.Evolvable {
+		functionCalls = append(functionCalls, skipBody(at.Name, "dr"))
 	}
 
 	functionCalls = append(functionCalls,
@@ -218,26 +533,49 @@ func (g *Generator) generateMarshaller(at parser.AnnotatedType) (j []jen.Code) {
 
 	j = make([]jen.Code, 0)
 
-	for _, e := range at.Entries {
-		fieldName := e.Field.Name
+	_, optionals := presenceBits(at)
+
+	switch {
+	// the length of an evolvable type's body is its size, less
+	// that of the length itself
+	case at.Evolvable:
+		functionCalls = append(functionCalls,
+			jen.If(jen.Id("err").Op("=").Qual(mintPath, "WriteBodySize").Call(jen.Id("w"), jen.Id("sf").Dot("MarshalledSize").Call().Op("-").Qual(mintPath, "BodyHeaderSize")).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return()),
+			jen.If(jen.Id("err").Op("=").Qual(mintPath, "WriteSizedPresence").Call(jen.Id("w"), presenceArg(optionals), jen.Lit(optionals)).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return()),
+		)
+
+	case optionals > 0:
+		functionCalls = append(functionCalls,
+			jen.If(jen.Id("err").Op("=").Qual(mintPath, "WritePresence").Call(jen.Id("w"), presenceArg(optionals), jen.Lit(optionals)).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return()),
+		)
+	}
+
+	// direct encoding appends scalars to a scratch buffer, which
+	// is declared only where there are scalars to append
+	scratch := false
 
+	for _, e := range at.ByTag() {
 		switch {
+		case e.DataType.Scalar != nil && g.DirectEncoding:
+			scratch = scratch || scalarToAppendJen(e.DataType.Scalar.Type) != nil
+			functionCalls = append(functionCalls, ifPresent(e, g.marshallScalarDirect(e)...)...)
+
 		// if a scalar, and creator isn't 'new' then do
 		// err = mint.$creator(value).Marshall(w) (etc)
 		// else just value.Marshall(w)
 		case e.DataType.Scalar != nil:
 			if _, ok := parser.Scalars[e.DataType.Scalar.Type]; ok {
 				f, _, _ := scalarToMintJen(e.DataType.Scalar.Type)
-				functionCalls = append(functionCalls,
-					jen.If(jen.Id("err").Op("=").Add(f).Call(jen.Id("sf").Dot(fieldName)).Dot("Marshall").Call(jen.Id("w")).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return()),
-				)
+				functionCalls = append(functionCalls, ifPresent(e,
+					jen.If(jen.Id("err").Op("=").Add(f).Call(fieldValue(e)).Dot("Marshall").Call(jen.Id("w")).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return()),
+				)...)
 
 				continue
 			}
 
-			functionCalls = append(functionCalls,
-				jen.If(jen.Id("err").Op("=").Id("sf").Dot(fieldName).Dot("Marshall").Call(jen.Id("w")).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return()),
-			)
+			functionCalls = append(functionCalls, ifPresent(e,
+				jen.If(jen.Id("err").Op("=").Add(fieldValue(e)).Dot("Marshall").Call(jen.Id("w")).Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return()),
+			)...)
 
 		// if a slice or array, then mint.NewSlicecollection, setting
 		// the second arg accordingly)
@@ -264,6 +602,10 @@ func (g *Generator) generateMarshaller(at parser.AnnotatedType) (j []jen.Code) {
 		}
 	}
 
+	if scratch {
+		functionCalls = append([]jen.Code{jen.Var().Id("b").Index().Byte()}, functionCalls...)
+	}
+
 	functionCalls = append(functionCalls, jen.Return())
 
 	return append(j, jen.Func().Params(jen.Id("sf").Id(at.Name)).Id("Marshall").Params(jen.Id("w").Qual("io", "Writer")).Params(jen.Id("err").Id("error")).
@@ -287,27 +629,88 @@ func (g *Generator) generateValuer(n string) jen.Code {
 }
 
 func toJenType(t parser.Field) jen.Code {
-	dt := t.DataType
+	if t.DataType.Scalar != nil && t.Optional {
+		return jen.Op("*").Add(toJenDataType(t.DataType))
+	}
 
+	return toJenDataType(t.DataType)
+}
+
+// toJenDataType returns the go type of mint type dt, recursing into
+// the elements of collections
+func toJenDataType(dt *parser.DataType) jen.Code {
 	switch {
 	case dt.Scalar != nil:
-		_, _, goType := scalarToMintJen(t.DataType.Scalar.Type)
-
-		return goType
+		return toJenElemType(dt.Scalar.Type)
 
 	case dt.Slice != nil:
-		return jen.Index().Id(dt.Slice.Type)
+		return jen.Index().Add(toJenDataType(dt.Slice.Type))
 
 	case dt.FixedSizeSlice != nil:
-		return jen.Index(jen.Id(fmt.Sprintf("%d", dt.FixedSizeSlice.Size))).Id(dt.FixedSizeSlice.Type)
+		return jen.Index(arraySize(dt.FixedSizeSlice)).Add(toJenDataType(dt.FixedSizeSlice.Type))
 
 	case dt.Map != nil:
-		return jen.Map(jen.Id(dt.Map.Key)).Id(dt.Map.Value)
+		return jen.Map(toJenElemType(dt.Map.Key)).Add(toJenDataType(dt.Map.Value))
 	}
 
 	return jen.Null()
 }
 
+// arraySize returns the size of array fs; either the name of the const
+// which sets it, or its literal size
+func arraySize(fs *parser.FixedSizedSliceType) jen.Code {
+	if fs.SizeRef != "" {
+		return jen.Id(fs.SizeRef)
+	}
+
+	return jen.Lit(fs.Size)
+}
+
+// valueLiteral returns v, a literal or the name of a const, as go
+func valueLiteral(v parser.Value) jen.Code {
+	switch {
+	case v.Quoted != nil:
+		return jen.Lit(*v.Quoted)
+
+	case v.Ident != nil:
+		return jen.Id(*v.Ident)
+	}
+
+	if i, err := strconv.ParseInt(*v.Number, 10, 64); err == nil {
+		return jen.Lit(int(i))
+	}
+
+	f, _ := strconv.ParseFloat(*v.Number, 64)
+
+	return jen.Lit(f)
+}
+
+// elemId returns the variable name, such as i or v, which a collection
+// nested depth collections deep uses for its elements; the outermost
+// collection of a field uses name as-is, so that nested loops don't
+// shadow one another
+func elemId(name string, depth int) *jen.Statement {
+	if depth == 0 {
+		return jen.Id(name)
+	}
+
+	return jen.Id(fmt.Sprintf("%s%d", name, depth))
+}
+
+// toJenElemType returns the go type of a slice, array, or map element
+// of mint type t
+func toJenElemType(t string) jen.Code {
+	_, _, goType := scalarToMintJen(t)
+
+	return goType
+}
+
+// wrapDecodeError wraps err with the path to the field which
+// failed to decode, via mint.WrapDecodeError
+func wrapDecodeError(t, f string) jen.Code {
+	return jen.Qual(mintPath, "WrapDecodeError").Call(jen.Id("dr"), jen.Lit(t), jen.Lit(f), jen.Id("err"))
+}
+
 func callErrorable(f string) jen.Code {
 	return jen.If(jen.Id("err").Op("=").Id("sf").Dot(f).Call().Id(";").Id("err").Op("!=").Id("nil")).Block(jen.Return())
 }
@@ -321,7 +724,7 @@ func scalarToMintJen(ts string) (c, nilValue, castType jen.Code) {
 		return jen.Qual(mintPath, "NewDatetimeScalar"), jen.Qual("time", "Time").Block(), jen.Qual("time", "Time")
 
 	case "uuid":
-		return jen.Qual(mintPath, "NewUuidScalar"), jen.Qual("github.com/gofrs/uuid/v5", "UUID").Block(), jen.Qual("github.com/gofrs/uuid/v5", "UUID")
+		return jen.Qual(mintPath, "NewUuidScalar"), jen.Qual(uuidPath, "UUID").Block(), jen.Qual(uuidPath, "UUID")
 
 	case "uint32":
 		return jen.Qual(mintPath, "NewUInt32Scalar"), jen.Id("uint32").Call(jen.Lit(0)), jen.Id(ts)
@@ -351,5 +754,5 @@ func scalarToMintJen(ts string) (c, nilValue, castType jen.Code) {
 		return jen.Qual(mintPath, "NewUint16Scalar"), jen.Id("uint16").Call(jen.Lit(0)), jen.Id(ts)
 	}
 
-	return jen.Id("new"), jen.Id(ts), jen.Id(ts)
+	return jen.Id("new"), qualifiedId(ts), qualifiedId(ts)
 }
@@ -57,7 +57,7 @@ func TestGenerator_Generate(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expect := "h1:FPfwu0DVYm5hDfJSql2eCLmfV8m1L/iKYkpdX75ZmqI="
+	expect := "h1:qT0uybsPRUoz8lu7qKJGcK2/13s+GzH7P3e8KYjwPrQ="
 	received, err := dirhash.HashDir(dir, "", dirhash.DefaultHash)
 	if err != nil {
 		t.Fatal(err)
@@ -138,6 +138,120 @@ func TestGenerator_generateType(t *testing.T) {
 
 }
 
+func TestGenerator_generateType_ConstSize(t *testing.T) {
+	g := new(Generator)
+
+	expect := `type ConstsType struct {
+	Tags [MaxTags]string
+}`
+	received := codeToString(g.generateTypeDefinition(constsType))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_generateType_Deprecated(t *testing.T) {
+	g := new(Generator)
+
+	expect := `type DeprecatedType struct {
+	// Deprecated: Optional is deprecated
+	Optional *int32
+	Name     string
+	// Old is the old name
+	//
+	// Deprecated: use Name instead
+	Old string
+}`
+	received := codeToString(g.generateTypeDefinition(deprecatedType))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+// Tags needn't be contiguous; fields are encoded in tag order, with
+// nothing written for the gaps left by reserved tags
+func TestGenerator_generateAppender_Gaps(t *testing.T) {
+	g := new(Generator)
+
+	expect := `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf DeprecatedType) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	b = mint.AppendPresence(b, sf.presence(), 1)
+	b = mint.AppendString(b, sf.Name)
+	b = mint.AppendString(b, sf.Old)
+	if sf.Optional != nil {
+		b = mint.AppendInt32(b, *sf.Optional)
+	}
+	return
+}
+`
+	received := codeSliceToFile(g.generateAppender(deprecatedType))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_generateConstructor(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		ast    *parser.AST
+		at     parser.AnnotatedType
+		expect string
+	}{
+		{"Type without defaults", nil, simpleType, `// NewSomeTestType returns a new SomeTestType, with each field set to its default value
+func NewSomeTestType() *SomeTestType {
+	return &SomeTestType{}
+}`},
+		{"Type with defaults", nil, defaultsType, `// NewDefaultsType returns a new DefaultsType, with each field set to its default value
+func NewDefaultsType() *DefaultsType {
+	return &DefaultsType{
+		Enabled:   true,
+		Epoch:     time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Level:     LevelHigh,
+		Namespace: v5.Must(v5.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")),
+		Offset:    -12,
+		Ratio:     0.5,
+		Region:    "eu-west-1",
+	}
+}`},
+		{"Nested type with defaults", defaultsAST, defaultsType, `// NewDefaultsType returns a new DefaultsType, with each field set to its default value
+func NewDefaultsType() *DefaultsType {
+	return &DefaultsType{
+		Enabled:   true,
+		Epoch:     time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Level:     LevelHigh,
+		Namespace: v5.Must(v5.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")),
+		Offset:    -12,
+		Ratio:     0.5,
+		Region:    "eu-west-1",
+		Thingy:    *NewBlahType(),
+	}
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g := new(Generator)
+			g.ast = test.ast
+
+			received := codeToString(g.generateConstructor(test.at))
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
+
 func TestGenerator_generateValidations(t *testing.T) {
 	g := new(Generator)
 	g.GeneratorOptions.CustomFunctionSkeletons = true
@@ -176,6 +290,140 @@ func (sf SomeTestType) BlahBlahBlahHowDoesThisEvaluate(string, any) error {
 	})
 }
 
+func TestGenerator_generateValidations_Elements(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf ElementType) Validate() error {
+	errors := make([]error, 0)
+	for _, err := range []error{} {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	for i := range sf.Thingy {
+		if err := sf.Thingy[i].Validate(); err != nil {
+			errors = append(errors, mint.ErrInvalidElement{Path: "Thingy" + mint.IndexSegment(i), Err: err})
+		}
+	}
+	{
+		from := len(errors)
+		for k, v := range sf.Thingy {
+			if err := v.Validate(); err != nil {
+				errors = append(errors, mint.ErrInvalidElement{Path: "Thingy" + mint.KeySegment(k), Err: err})
+			}
+		}
+		mint.SortInvalidElements(errors[from:])
+	}
+	{
+		from := len(errors)
+		for k := range sf.Thingy {
+			if err := k.Validate(); err != nil {
+				errors = append(errors, mint.ErrInvalidElement{Path: "Thingy" + mint.KeySegment(k), Err: err})
+			}
+		}
+		mint.SortInvalidElements(errors[from:])
+	}
+	{
+		from := len(errors)
+		for k, v := range sf.Thingy {
+			if err := k.Validate(); err != nil {
+				errors = append(errors, mint.ErrInvalidElement{Path: "Thingy" + mint.KeySegment(k), Err: err})
+			}
+			if err := v.Validate(); err != nil {
+				errors = append(errors, mint.ErrInvalidElement{Path: "Thingy" + mint.KeySegment(k), Err: err})
+			}
+		}
+		mint.SortInvalidElements(errors[from:])
+	}
+	return mint.ValidationErrors("ElementType", errors)
+}`
+	received := codeToString(g.generateValidations(parser.AnnotatedType{
+		Name: "ElementType",
+		Entries: []parser.AnnotatedEntry{
+			userDefinedSliceEntry,
+			builtinToComplexMap,
+			complexToBuiltinMap,
+			complexToComplexMap,
+			nonFixedLengthSlice,
+		},
+	}))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_generateValidations_Nested(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf NestedType) Validate() error {
+	errors := make([]error, 0)
+	for _, err := range []error{} {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	{
+		from := len(errors)
+		for k, v := range sf.Groups {
+			for i1 := range v {
+				if err := v[i1].Validate(); err != nil {
+					errors = append(errors, mint.ErrInvalidElement{Path: "Groups" + mint.KeySegment(k) + mint.IndexSegment(i1), Err: err})
+				}
+			}
+		}
+		mint.SortInvalidElements(errors[from:])
+	}
+	return mint.ValidationErrors("NestedType", errors)
+}`
+	received := codeToString(g.generateValidations(parser.AnnotatedType{
+		Name:    "NestedType",
+		Entries: []parser.AnnotatedEntry{nestedSliceEntry, nestedMapEntry},
+	}))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_generateValidations_Args(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf ConstsType) Validate() error {
+	errors := make([]error, 0)
+	for _, err := range []error{sf.MaxLength("Tags", sf.Tags, MaxTags, -1.5, "tags")} {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return mint.ValidationErrors("ConstsType", errors)
+}`
+	received := codeToString(g.generateValidations(constsType))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_validateEnum(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf TestEnum) Validate() error {
+	if sf < 1 || sf > 2 {
+		return errors.New("invalid value for type TestEnum")
+	}
+	return nil
+}`
+	received := codeToString(g.validateEnum(parser.Enum{
+		Name:   "TestEnum",
+		Values: []*parser.EnumEntry{{}, {}},
+	}))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
 func TestGenerator_generateTransformations(t *testing.T) {
 	g := new(Generator)
 	g.GeneratorOptions.CustomFunctionSkeletons = true
@@ -203,6 +451,9 @@ func TrebleValue(any) (any, error) {
 	if err != nil {
 		return
 	}
+	if err = mint.Transform(&sf.Thingy); err != nil {
+		return
+	}
 	return
 }`
 	t.Run("Validate()", func(t *testing.T) {
@@ -223,6 +474,45 @@ func TrebleValue(any) (any, error) {
 	})
 }
 
+func TestGenerator_generateTransformations_Elements(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf *ElementType) Transform() (err error) {
+	for i := range sf.Thingy {
+		if err = mint.Transform(&sf.Thingy[i]); err != nil {
+			return
+		}
+	}
+	for k, v := range sf.Thingy {
+		if err = mint.Transform(&v); err != nil {
+			return
+		}
+		sf.Thingy[k] = v
+	}
+	for k, v := range sf.Groups {
+		for i1 := range v {
+			if err = mint.Transform(&v[i1]); err != nil {
+				return
+			}
+		}
+	}
+	return
+}`
+	received := codeToString(g.generateTransformations(parser.AnnotatedType{
+		Name: "ElementType",
+		Entries: []parser.AnnotatedEntry{
+			userDefinedSliceEntry,
+			builtinToComplexMap,
+			nestedSliceEntry,
+			nestedMapEntry,
+		},
+	}))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
 func TestGenerator_generateUnmarshaller(t *testing.T) {
 	g := new(Generator)
 
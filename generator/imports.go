@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// qualifyImports returns a copy of doc in which references to imported
+// types, such as geo.Location, are qualified by the go import path of the
+// package generated for them, such as example.com/geo.Location, and in
+// which doc.Imports is keyed by that path.
+//
+// This allows the rest of the generator to turn any type name into go
+// via qualifiedId, without needing to know about imports
+func qualifyImports(doc *parser.AST) (*parser.AST, error) {
+	if doc == nil || len(doc.Imports) == 0 {
+		return doc, nil
+	}
+
+	out := *doc
+	out.Imports = make(map[string]*parser.AST)
+
+	paths := make(map[string]string)
+	for alias, dep := range doc.Imports {
+		if dep.GoPackage == "" {
+			return nil, fmt.Errorf("package %s, imported as %s, has no go_package option, and so can't be imported", dep.Package, alias)
+		}
+
+		paths[alias] = dep.GoPackage
+		out.Imports[dep.GoPackage] = dep
+	}
+
+	qualify := func(t string) string {
+		if alias, name, ok := strings.Cut(t, "."); ok {
+			return paths[alias] + "." + name
+		}
+
+		return t
+	}
+
+	out.Types = make([]parser.AnnotatedType, len(doc.Types))
+	for i, t := range doc.Types {
+		entries := make([]parser.AnnotatedEntry, len(t.Entries))
+		for j, e := range t.Entries {
+			e.DataType = qualifyDataType(e.DataType, qualify)
+			entries[j] = e
+		}
+
+		t.Entries = entries
+		out.Types[i] = t
+	}
+
+	out.Unions = make([]parser.Union, len(doc.Unions))
+	for i, u := range doc.Unions {
+		variants := make([]*parser.UnionVariant, len(u.Variants))
+		for j, v := range u.Variants {
+			qv := *v
+			qv.Type = qualify(v.Type)
+			variants[j] = &qv
+		}
+
+		u.Variants = variants
+		out.Unions[i] = u
+	}
+
+	return &out, nil
+}
+
+// qualifyDataType returns a copy of dt, with each type name within it
+// passed through qualify
+func qualifyDataType(dt *parser.DataType, qualify func(string) string) *parser.DataType {
+	if dt == nil {
+		return nil
+	}
+
+	out := *dt
+
+	switch {
+	case dt.Scalar != nil:
+		s := *dt.Scalar
+		s.Type = qualify(s.Type)
+		out.Scalar = &s
+
+	case dt.Slice != nil:
+		s := *dt.Slice
+		s.Type = qualifyDataType(s.Type, qualify)
+		out.Slice = &s
+
+	case dt.FixedSizeSlice != nil:
+		fs := *dt.FixedSizeSlice
+		fs.Type = qualifyDataType(fs.Type, qualify)
+		out.FixedSizeSlice = &fs
+
+	case dt.Map != nil:
+		m := *dt.Map
+		m.Key = qualify(m.Key)
+		m.Value = qualifyDataType(m.Value, qualify)
+		out.Map = &m
+	}
+
+	return &out
+}
+
+// splitQualified splits a name qualified by import path, such as
+// example.com/geo.Location, into that path and the unqualified name;
+// path is empty where s isn't qualified
+func splitQualified(s string) (path, name string) {
+	i := strings.LastIndex(s, ".")
+	if i < 0 {
+		return "", s
+	}
+
+	return s[:i], s[i+1:]
+}
+
+// qualifiedId returns s, a type or value, as a go identifier, qualified
+// by package where s is imported
+func qualifiedId(s string) *jen.Statement {
+	path, name := splitQualified(s)
+	if path == "" {
+		return jen.Id(name)
+	}
+
+	return jen.Qual(path, name)
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/vinyl-linux/mint/parser"
+)
+
+func TestNew_Imports(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		ast       *parser.AST
+		expectErr bool
+	}{
+		{"No imports", &parser.AST{}, false},
+		{"Imports with go packages", &parser.AST{Imports: map[string]*parser.AST{"geo": {Package: "geo", GoPackage: "example.com/geo"}}}, false},
+		{"Imports without go packages", &parser.AST{Imports: map[string]*parser.AST{"geo": {Package: "geo"}}}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.ast, &GeneratorOptions{})
+			if err == nil && test.expectErr {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectErr {
+				t.Errorf("unexpected error %s", err)
+			}
+		})
+	}
+}
+
+func TestGenerator_Imports(t *testing.T) {
+	ast, err := parser.ParseDir("testdata/packages/weather")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := New(ast, &GeneratorOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		f      func() string
+		expect string
+	}{
+		{"generateTypeDefinition", func() string { return codeToString(g.generateTypeDefinition(g.ast.Types[0])) }, `type Report struct {
+	// Where this report was taken
+	Where       geo.Coordinates
+	Terrain     geo.Terrain
+	Route       []geo.Coordinates
+	Notes       map[geo.Terrain]string
+	Destination *geo.Coordinates
+}`},
+		{"generateConstructor", func() string { return codeToString(g.generateConstructor(g.ast.Types[0])) }, `// NewReport returns a new Report, with each field set to its default value
+func NewReport() *Report {
+	return &Report{Terrain: geo.TerrainSea}
+}`},
+		{"generateUnionVariant", func() string {
+			return codeToString(g.generateUnionVariant(g.ast.Unions[0], g.ast.Unions[0].Variants[0])[0])
+		}, `type ObservationPoint struct {
+	Value geo.Coordinates
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			received := test.f()
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+
+	t.Run("the imported AST is left untouched", func(t *testing.T) {
+		if received := ast.Types[0].Entries[0].DataType.Scalar.Type; received != "geo.Coordinates" {
+			t.Errorf("expected geo.Coordinates, received %s", received)
+		}
+	})
+}
+
+func TestQualifiedId(t *testing.T) {
+	for _, test := range []struct {
+		s      string
+		expect string
+	}{
+		{"Location", "Location"},
+		{"example.com/geo.Location", "geo.Location"},
+		{"example.com/geo.TerrainSea", "geo.TerrainSea"},
+	} {
+		t.Run(test.s, func(t *testing.T) {
+			received := codeToString(qualifiedId(test.s))
+			if test.expect != received {
+				t.Errorf("expected %q, received %q", test.expect, received)
+			}
+		})
+	}
+}
@@ -5,7 +5,16 @@ import (
 	"github.com/vinyl-linux/mint/parser"
 )
 
+// marshallSliceArray creates a function which marshalls slice or array e.
+//
+// Nested collections can't be boxed in scalars, since the length of each
+// inner collection is only known once read, and so are always encoded
+// directly; the wire format is the same either way
 func (g Generator) marshallSliceArray(t string, e parser.AnnotatedEntry) jen.Code {
+	if g.DirectEncoding || e.DataType.IsNested() {
+		return g.marshallSliceArrayDirect(t, e)
+	}
+
 	var (
 		dt string
 	)
@@ -16,10 +25,10 @@ func (g Generator) marshallSliceArray(t string, e parser.AnnotatedEntry) jen.Cod
 
 	switch {
 	case e.Field.DataType.Slice != nil:
-		dt = e.Field.DataType.Slice.Type
+		dt = e.Field.DataType.Slice.Type.Scalar.Type
 
 	case e.Field.DataType.FixedSizeSlice != nil:
-		dt = e.Field.DataType.FixedSizeSlice.Type
+		dt = e.Field.DataType.FixedSizeSlice.Type.Scalar.Type
 
 	default:
 		return jen.Null()
@@ -39,11 +48,17 @@ func (g Generator) marshallSliceArray(t string, e parser.AnnotatedEntry) jen.Cod
 
 }
 
+// marshallMap creates a function which marshalls map e; as with
+// marshallSliceArray, maps of collections are always encoded directly
 func (g Generator) marshallMap(t string, e parser.AnnotatedEntry) jen.Code {
+	if g.DirectEncoding || e.DataType.IsNested() {
+		return g.marshallMapDirect(t, e)
+	}
+
 	fn := marshallerFuncName(e.Name)
 
 	keyInitialiser := marshallerInitialiser(e.DataType.Map.Key)
-	valueInitialiser := marshallerInitialiser(e.DataType.Map.Value)
+	valueInitialiser := marshallerInitialiser(e.DataType.Map.Value.Scalar.Type)
 
 	return jen.Func().Params(jen.Id("sf").Id(t)).Id(fn).Params(jen.Id("w").Qual("io", "Writer")).Params(jen.Id("err").Id("error")).
 		Block(
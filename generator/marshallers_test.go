@@ -34,6 +34,25 @@ func TestGenerator_marshallSliceArray(t *testing.T) {
 		f[i] = &(sf.Thingy[i])
 	}
 	return mint.NewSliceCollection(f, false).Marshall(w)
+}`},
+		{"Nested collections are encoded directly", nestedSliceEntry, `func (sf TestType) marshallMatrix(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.Matrix)))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for _, v := range sf.Matrix {
+		b = mint.AppendUint32(b[:0], uint32(len(v)))
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+		for _, v1 := range v {
+			b = mint.AppendFloat64(b[:0], v1)
+			if _, err = w.Write(b); err != nil {
+				return
+			}
+		}
+	}
+	return
 }`},
 	} {
 		t.Run(test.name, func(t *testing.T) {
@@ -81,6 +100,28 @@ func TestGenerator_marshallMap(t *testing.T) {
 		f[&(k)] = &(v)
 	}
 	return mint.NewMapCollection(f).Marshall(w)
+}`},
+		{"Maps of collections are encoded directly", nestedMapEntry, `func (sf TestType) marshallGroups(w io.Writer) (err error) {
+	b := mint.AppendUint32(nil, uint32(len(sf.Groups)*2))
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	for k, v := range sf.Groups {
+		b = mint.AppendString(b[:0], k)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+		b = mint.AppendUint32(b[:0], uint32(len(v)))
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+		for _, v1 := range v {
+			if err = v1.Marshall(w); err != nil {
+				return
+			}
+		}
+	}
+	return
 }`},
 	} {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// presenceBits returns the bit each optional field of at occupies in
+// its presence bitmap, keyed by field name, along with the number of
+// optional fields.
+//
+// Bits are assigned in ascending tag order, so that reordering the
+// declarations in a document doesn't change the wire format
+func presenceBits(at parser.AnnotatedType) (bits map[string]int, n int) {
+	bits = make(map[string]int)
+	for _, e := range at.ByTag() {
+		if e.Optional {
+			bits[e.Name] = n
+			n++
+		}
+	}
+
+	return
+}
+
+// generatePresence creates a function which builds the presence bitmap
+// of a type with optional fields, setting the bit of each field which
+// isn't nil
+func (g *Generator) generatePresence(at parser.AnnotatedType) jen.Code {
+	bits, _ := presenceBits(at)
+
+	body := make([]jen.Code, 0)
+	for _, e := range at.Entries {
+		if bit, ok := bits[e.Name]; ok {
+			body = append(body, ifPresent(e,
+				jen.Id("p").Op("|=").Lit(1).Op("<<").Lit(bit),
+			)...)
+		}
+	}
+
+	body = append(body, jen.Return())
+
+	return jen.Func().Params(jen.Id("sf").Id(at.Name)).Id("presence").Params().Params(jen.Id("p").Uint64()).
+		Block(body...)
+}
+
+// ifPresent wraps body in a nil check where e is optional, so that
+// body only runs when the field is set
+func ifPresent(e parser.AnnotatedEntry, body ...jen.Code) []jen.Code {
+	if !e.Optional {
+		return body
+	}
+
+	return []jen.Code{
+		jen.If(jen.Id("sf").Dot(e.Name).Op("!=").Id("nil")).Block(body...),
+	}
+}
+
+// ifPresentBit resets optional field e, and wraps body in a check of
+// bit in the presence bitmap p, so that body only runs when the field
+// was encoded
+func ifPresentBit(e parser.AnnotatedEntry, bit int, body ...jen.Code) []jen.Code {
+	return []jen.Code{
+		jen.Id("sf").Dot(e.Name).Op("=").Id("nil"),
+		jen.If(jen.Id("p").Op("&").Parens(jen.Lit(1).Op("<<").Lit(bit)).Op("!=").Lit(0)).Block(body...),
+	}
+}
+
+// fieldValue returns the value of field e for encoding; optional
+// scalars are dereferenced, whereas optional user types are left as
+// pointers, which carry the same methods
+func fieldValue(e parser.AnnotatedEntry) *jen.Statement {
+	if _, ok := parser.Scalars[e.DataType.Scalar.Type]; ok && e.Optional {
+		return jen.Op("*").Id("sf").Dot(e.Name)
+	}
+
+	return jen.Id("sf").Dot(e.Name)
+}
+
+// readPresence reads the presence bitmap p of at from reader, which is
+// either a mint.DecodeReader or a mint.BytesDecoder.
+//
+// Evolvable types carry a sized bitmap, which is there even where they
+// have no optional fields, in which case it is read and discarded
+func readPresence(at parser.AnnotatedType, reader string) []jen.Code {
+	_, n := presenceBits(at)
+
+	fn := "ReadPresence"
+	if at.Evolvable {
+		fn = "ReadSizedPresence"
+	}
+
+	var read jen.Code
+	switch reader {
+	case "d":
+		read = jen.Id("d").Dot(fn).Call(jen.Lit(n))
+
+	default:
+		read = jen.Qual(mintPath, fn).Call(jen.Id(reader), jen.Lit(n))
+	}
+
+	assign := jen.List(jen.Id("p"), jen.Id("err")).Op(":=").Add(read)
+	if n == 0 {
+		assign = jen.List(jen.Id("_"), jen.Id("err")).Op("=").Add(read)
+	}
+
+	return []jen.Code{
+		assign,
+		jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(jen.Qual(mintPath, "WrapDecodeError").Call(jen.Id(reader), jen.Lit(at.Name), jen.Lit(""), jen.Id("err"))),
+		),
+	}
+}
+
+// presenceArg returns the presence bitmap of a type with n optional
+// fields, for passing to the functions which encode it
+func presenceArg(n int) jen.Code {
+	if n == 0 {
+		return jen.Lit(0)
+	}
+
+	return jen.Id("sf").Dot("presence").Call()
+}
+
+// optionalValue returns the value of field e for validations and
+// transforms, which work with values rather than pointers
+func optionalValue(e parser.AnnotatedEntry) *jen.Statement {
+	if e.Optional {
+		return jen.Op("*").Id("sf").Dot(e.Name)
+	}
+
+	return jen.Id("sf").Dot(e.Name)
+}
@@ -0,0 +1,371 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestPresenceBits(t *testing.T) {
+	bits, n := presenceBits(optionalType)
+	if n != 2 {
+		t.Errorf("expected 2 optional fields, received %d", n)
+	}
+
+	// bits are assigned in tag order, rather than declaration order
+	if bits["Thingy"] != 0 || bits["Nickname"] != 1 {
+		t.Errorf("unexpected bits %#v", bits)
+	}
+
+	if _, n = presenceBits(simpleType); n != 0 {
+		t.Errorf("expected no optional fields, received %d", n)
+	}
+}
+
+func TestGenerator_Optional(t *testing.T) {
+	g := new(Generator)
+
+	for _, test := range []struct {
+		name   string
+		f      func() string
+		expect string
+	}{
+		{"generatePresence", func() string { return codeToString(g.generatePresence(optionalType)) }, `func (sf OptionalType) presence() (p uint64) {
+	if sf.Nickname != nil {
+		p |= 1 << 1
+	}
+	if sf.Thingy != nil {
+		p |= 1 << 0
+	}
+	return
+}`},
+		{"generateTypeDefinition", func() string { return codeToString(g.generateTypeDefinition(optionalType)) }, `type OptionalType struct {
+	// ATypeOfSomeType is a uuid
+	ATypeOfSomeType v5.UUID
+	Nickname        *string
+	Thingy          *BlahType
+}`},
+		{"generateValidations", func() string { return codeToString(g.generateValidations(optionalType)) }, `func (sf OptionalType) Validate() error {
+	errors := make([]error, 0)
+	for _, err := range []error{mint.NotEmpty("ATypeOfSomeType", sf.ATypeOfSomeType), sf.BlahBlahBlahHowDoesThisEvaluate("ATypeOfSomeType", sf.ATypeOfSomeType)} {
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if sf.Nickname != nil {
+		for _, err := range []error{mint.StringNotEmpty("Nickname", *sf.Nickname)} {
+			if err != nil {
+				errors = append(errors, err)
+			}
+		}
+	}
+	return mint.ValidationErrors("OptionalType", errors)
+}`},
+		{"generateTransformations", func() string { return codeToString(g.generateTransformations(optionalType)) }, `func (sf *OptionalType) Transform() (err error) {
+	if sf.Nickname != nil {
+		*sf.Nickname, err = mint.ToLower(*sf.Nickname)
+		if err != nil {
+			return
+		}
+	}
+	if sf.Thingy != nil {
+		if err = mint.Transform(sf.Thingy); err != nil {
+			return
+		}
+	}
+	return
+}`},
+		{"generateMarshaller", func() string { return codeSliceToFile(g.generateMarshaller(optionalType)) }, `package test
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+func (sf OptionalType) Marshall(w io.Writer) (err error) {
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if err = mint.WritePresence(w, sf.presence(), 2); err != nil {
+		return
+	}
+	if err = mint.NewUuidScalar(sf.ATypeOfSomeType).Marshall(w); err != nil {
+		return
+	}
+	if sf.Thingy != nil {
+		if err = sf.Thingy.Marshall(w); err != nil {
+			return
+		}
+	}
+	if sf.Nickname != nil {
+		if err = mint.NewStringScalar(*sf.Nickname).Marshall(w); err != nil {
+			return
+		}
+	}
+	return
+}
+`},
+		{"generateUnmarshaller", func() string { return codeSliceToFile(g.generateUnmarshaller(optionalType)) }, `package test
+
+import (
+	v5 "github.com/gofrs/uuid/v5"
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+func (sf *OptionalType) unmarshallATypeOfSomeType(r io.Reader) (err error) {
+	f := mint.NewUuidScalar(v5.UUID{})
+	err = f.Unmarshall(r)
+	if err != nil {
+		return
+	}
+	sf.ATypeOfSomeType = f.Value().(v5.UUID)
+	return
+}
+func (sf *OptionalType) unmarshallThingy(r io.Reader) (err error) {
+	f := new(BlahType)
+	err = f.Unmarshall(r)
+	if err != nil {
+		return
+	}
+	v := f.Value().(BlahType)
+	sf.Thingy = &v
+	return
+}
+func (sf *OptionalType) unmarshallNickname(r io.Reader) (err error) {
+	f := mint.NewStringScalar("")
+	err = f.Unmarshall(r)
+	if err != nil {
+		return
+	}
+	v := f.Value().(string)
+	sf.Nickname = &v
+	return
+}
+func (sf *OptionalType) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	p, err := mint.ReadPresence(dr, 2)
+	if err != nil {
+		return mint.WrapDecodeError(dr, "OptionalType", "", err)
+	}
+	if err = sf.unmarshallATypeOfSomeType(dr); err != nil {
+		return mint.WrapDecodeError(dr, "OptionalType", "ATypeOfSomeType", err)
+	}
+	sf.Thingy = nil
+	if p&(1<<0) != 0 {
+		if err = sf.unmarshallThingy(dr); err != nil {
+			return mint.WrapDecodeError(dr, "OptionalType", "Thingy", err)
+		}
+	}
+	sf.Nickname = nil
+	if p&(1<<1) != 0 {
+		if err = sf.unmarshallNickname(dr); err != nil {
+			return mint.WrapDecodeError(dr, "OptionalType", "Nickname", err)
+		}
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+`},
+		{"generateAppender", func() string { return codeSliceToFile(g.generateAppender(optionalType)) }, `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf OptionalType) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	b = mint.AppendPresence(b, sf.presence(), 2)
+	b = mint.AppendUuid(b, sf.ATypeOfSomeType)
+	if sf.Thingy != nil {
+		if b, err = sf.Thingy.AppendMarshall(b); err != nil {
+			return
+		}
+	}
+	if sf.Nickname != nil {
+		b = mint.AppendString(b, *sf.Nickname)
+	}
+	return
+}
+`},
+		{"generateBytesUnmarshaller", func() string { return codeSliceToFile(g.generateBytesUnmarshaller(optionalType)) }, `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf *OptionalType) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	p, err := d.ReadPresence(2)
+	if err != nil {
+		return mint.WrapDecodeError(d, "OptionalType", "", err)
+	}
+	if sf.ATypeOfSomeType, err = d.ReadUuid(); err != nil {
+		return mint.WrapDecodeError(d, "OptionalType", "ATypeOfSomeType", err)
+	}
+	sf.Thingy = nil
+	if p&(1<<0) != 0 {
+		sf.Thingy = new(BlahType)
+		if err = sf.Thingy.UnmarshallDecoder(d); err != nil {
+			return mint.WrapDecodeError(d, "OptionalType", "Thingy", err)
+		}
+	}
+	sf.Nickname = nil
+	if p&(1<<1) != 0 {
+		sf.Nickname = new(string)
+		if *sf.Nickname, err = d.ReadString(); err != nil {
+			return mint.WrapDecodeError(d, "OptionalType", "Nickname", err)
+		}
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+func (sf *OptionalType) UnmarshallBytes(b []byte) (n int, err error) {
+	d := mint.NewBytesDecoder(b, nil)
+	err = sf.UnmarshallDecoder(d)
+	return int(d.Offset()), err
+}
+`},
+		{"generateSizer", func() string { return codeSliceToFile(g.generateSizer(optionalType)) }, `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf OptionalType) MarshalledSize() (n int) {
+	n += mint.PresenceSize(2)
+	n += mint.UuidSize
+	if sf.Thingy != nil {
+		n += sf.Thingy.MarshalledSize()
+	}
+	if sf.Nickname != nil {
+		n += mint.StringSize(*sf.Nickname)
+	}
+	return
+}
+`},
+		{"generateMarshaller_Direct", func() string { return codeSliceToFile(directGenerator.generateMarshaller(optionalType)) }, `package test
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+func (sf OptionalType) Marshall(w io.Writer) (err error) {
+	var b []byte
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	if err = mint.WritePresence(w, sf.presence(), 2); err != nil {
+		return
+	}
+	b = mint.AppendUuid(b[:0], sf.ATypeOfSomeType)
+	if _, err = w.Write(b); err != nil {
+		return
+	}
+	if sf.Thingy != nil {
+		if err = sf.Thingy.Marshall(w); err != nil {
+			return
+		}
+	}
+	if sf.Nickname != nil {
+		b = mint.AppendString(b[:0], *sf.Nickname)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+	}
+	return
+}
+`},
+		{"generateUnmarshaller_Direct", func() string { return codeSliceToFile(directGenerator.generateUnmarshaller(optionalType)) }, `package test
+
+import (
+	mint "github.com/vinyl-linux/mint"
+	"io"
+)
+
+func (sf *OptionalType) unmarshallATypeOfSomeType(r io.Reader) (err error) {
+	if sf.ATypeOfSomeType, err = mint.ReadUuid(r); err != nil {
+		return
+	}
+	return
+}
+func (sf *OptionalType) unmarshallThingy(r io.Reader) (err error) {
+	sf.Thingy = new(BlahType)
+	if err = sf.Thingy.Unmarshall(r); err != nil {
+		return
+	}
+	return
+}
+func (sf *OptionalType) unmarshallNickname(r io.Reader) (err error) {
+	sf.Nickname = new(string)
+	if *sf.Nickname, err = mint.ReadString(r); err != nil {
+		return
+	}
+	return
+}
+func (sf *OptionalType) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	p, err := mint.ReadPresence(dr, 2)
+	if err != nil {
+		return mint.WrapDecodeError(dr, "OptionalType", "", err)
+	}
+	if err = sf.unmarshallATypeOfSomeType(dr); err != nil {
+		return mint.WrapDecodeError(dr, "OptionalType", "ATypeOfSomeType", err)
+	}
+	sf.Thingy = nil
+	if p&(1<<0) != 0 {
+		if err = sf.unmarshallThingy(dr); err != nil {
+			return mint.WrapDecodeError(dr, "OptionalType", "Thingy", err)
+		}
+	}
+	sf.Nickname = nil
+	if p&(1<<1) != 0 {
+		if err = sf.unmarshallNickname(dr); err != nil {
+			return mint.WrapDecodeError(dr, "OptionalType", "Nickname", err)
+		}
+	}
+	if err = sf.Transform(); err != nil {
+		return
+	}
+	if err = sf.Validate(); err != nil {
+		return
+	}
+	return
+}
+`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			received := test.f()
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
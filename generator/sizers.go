@@ -0,0 +1,293 @@
+package generator
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// generateSizer will:
+//  1. Create a size function per collection field
+//  2. Create an implementation of the mint.MarshalledSizer interface for this type
+//
+// MarshalledSize sizes a type as it stands, without transforming it, so
+// that it has no side effects; Marshall transforms a type before sizing
+// it, and so sizes exactly what it encodes
+func (g *Generator) generateSizer(at parser.AnnotatedType) (j []jen.Code) {
+	functionCalls := make([]jen.Code, 0)
+
+	j = make([]jen.Code, 0)
+
+	_, optionals := presenceBits(at)
+
+	switch {
+	case at.Evolvable:
+		functionCalls = append(functionCalls,
+			jen.Id("n").Op("+=").Qual(mintPath, "BodyHeaderSize"),
+			jen.Id("n").Op("+=").Qual(mintPath, "SizedPresenceSize").Call(jen.Lit(optionals)),
+		)
+
+	case optionals > 0:
+		functionCalls = append(functionCalls, jen.Id("n").Op("+=").Qual(mintPath, "PresenceSize").Call(jen.Lit(optionals)))
+	}
+
+	for _, e := range at.ByTag() {
+		switch {
+		case e.DataType.Scalar != nil:
+			functionCalls = append(functionCalls, ifPresent(e,
+				jen.Id("n").Op("+=").Add(sizeValue(e.DataType.Scalar.Type, fieldValue(e))),
+			)...)
+
+		case e.DataType.Slice != nil ||
+			e.DataType.FixedSizeSlice != nil:
+			j = append(j, g.sizeSliceArray(at.Name, e))
+			functionCalls = append(functionCalls, jen.Id("n").Op("+=").Id("sf").Dot(sizerFuncName(e.Name)).Call())
+
+		case e.DataType.Map != nil:
+			j = append(j, g.sizeMap(at.Name, e))
+			functionCalls = append(functionCalls, jen.Id("n").Op("+=").Id("sf").Dot(sizerFuncName(e.Name)).Call())
+
+		default:
+			continue
+		}
+	}
+
+	functionCalls = append(functionCalls, jen.Return())
+
+	return append(j, sizerFunc(at.Name, "MarshalledSize", functionCalls...))
+}
+
+func (g Generator) sizeSliceArray(t string, e parser.AnnotatedEntry) jen.Code {
+	var (
+		dt     *parser.DataType
+		header jen.Code
+	)
+
+	if e.Field.DataType == nil {
+		return jen.Null()
+	}
+
+	switch {
+	case e.Field.DataType.Slice != nil:
+		dt = e.Field.DataType.Slice.Type
+		header = jen.Qual(mintPath, "CollectionHeaderSize")
+
+	case e.Field.DataType.FixedSizeSlice != nil:
+		dt = e.Field.DataType.FixedSizeSlice.Type
+
+	default:
+		return jen.Null()
+	}
+
+	// arrays have no header, and so only their elements are counted
+	total := jen.Id("len").Call(jen.Id("sf").Dot(e.Name))
+	start := jen.Null()
+
+	if header != nil {
+		start = jen.Id("n").Op("=").Add(header)
+	}
+
+	if size := fixedSize(dt); size != nil {
+		if header != nil {
+			total = jen.Add(header).Op("+").Add(total)
+		}
+
+		return sizerFunc(t, sizerFuncName(e.Name),
+			jen.Return(total.Op("*").Add(size)),
+		)
+	}
+
+	return sizerFunc(t, sizerFuncName(e.Name),
+		start,
+		jen.For(jen.List(jen.Id("_"), jen.Id("v")).Op(":=").Range().Id("sf").Dot(e.Name)).Block(
+			sizeElement(dt, jen.Id("v"), 1)...,
+		),
+		jen.Return(),
+	)
+}
+
+func (g Generator) sizeMap(t string, e parser.AnnotatedEntry) jen.Code {
+	keySize := scalarToSizeJen(e.DataType.Map.Key)
+	valueSize := fixedSize(e.DataType.Map.Value)
+
+	if keySize != nil && valueSize != nil {
+		return sizerFunc(t, sizerFuncName(e.Name),
+			jen.Return(jen.Qual(mintPath, "CollectionHeaderSize").Op("+").Id("len").Call(jen.Id("sf").Dot(e.Name)).Op("*").Parens(jen.Add(keySize).Op("+").Add(valueSize))),
+		)
+	}
+
+	// Range over only those of the key and value which vary in size,
+	// adding the rest on as a fixed amount per entry
+	vars := jen.List(jen.Id("k"), jen.Id("v"))
+
+	switch {
+	case keySize != nil:
+		vars = jen.List(jen.Id("_"), jen.Id("v"))
+
+	case valueSize != nil:
+		vars = jen.Id("k")
+	}
+
+	// scalar values are summed alongside their keys, whereas nested
+	// collections are sized element by element
+	var body []jen.Code
+	if value := e.DataType.Map.Value; value.Scalar != nil {
+		body = []jen.Code{
+			jen.Id("n").Op("+=").Add(sizeValue(e.DataType.Map.Key, jen.Id("k"))).Op("+").Add(sizeValue(value.Scalar.Type, jen.Id("v"))),
+		}
+	} else {
+		body = append([]jen.Code{
+			jen.Id("n").Op("+=").Add(sizeValue(e.DataType.Map.Key, jen.Id("k"))),
+		}, sizeElement(value, jen.Id("v"), 1)...)
+	}
+
+	return sizerFunc(t, sizerFuncName(e.Name),
+		jen.Id("n").Op("=").Qual(mintPath, "CollectionHeaderSize"),
+		jen.For(vars.Op(":=").Range().Id("sf").Dot(e.Name)).Block(body...),
+		jen.Return(),
+	)
+}
+
+func (g Generator) sizeEnum(e parser.Enum) jen.Code {
+	return jen.Func().Params(jen.Id("sf").Id(e.Name)).Id("MarshalledSize").Params().Params(jen.Int()).
+		Block(
+			jen.Return(jen.Qual(mintPath, "ByteSize")),
+		)
+}
+
+// sizerFunc wraps body in a function which returns the size of part of
+// type t, named fn
+func sizerFunc(t, fn string, body ...jen.Code) jen.Code {
+	return jen.Func().Params(jen.Id("sf").Id(t)).Id(fn).Params().Params(jen.Id("n").Int()).
+		Block(body...)
+}
+
+// sizeValue returns the size of v, of mint type dt; fixed size scalars
+// are a constant, strings are measured with mint.StringSize, and anything
+// else via its own MarshalledSize
+func sizeValue(dt string, v *jen.Statement) jen.Code {
+	if size := scalarToSizeJen(dt); size != nil {
+		return size
+	}
+
+	if dt == "string" {
+		return jen.Qual(mintPath, "StringSize").Call(v)
+	}
+
+	return jen.Add(v).Dot("MarshalledSize").Call()
+}
+
+// sizeElement adds the size of v, an element of a collection of mint
+// type dt, to n. Where v is itself a collection its size is worked out
+// as a field's would be, ranging over its own elements with variables
+// named for depth, unless those elements are of a fixed size
+func sizeElement(dt *parser.DataType, v *jen.Statement, depth int) []jen.Code {
+	var header jen.Code
+
+	switch {
+	case dt.Slice != nil, dt.Map != nil:
+		header = jen.Qual(mintPath, "CollectionHeaderSize")
+
+	case dt.FixedSizeSlice != nil:
+
+	default:
+		return []jen.Code{jen.Id("n").Op("+=").Add(sizeValue(dt.Scalar.Type, v))}
+	}
+
+	var (
+		elem    = dt.Elem()
+		keySize jen.Code
+		k       = jen.Id("_")
+	)
+
+	if dt.Map != nil {
+		keySize = scalarToSizeJen(dt.Map.Key)
+		if keySize == nil {
+			k = elemId("k", depth)
+		}
+	}
+
+	elemSize := fixedSize(elem)
+	if elemSize != nil && (dt.Map == nil || keySize != nil) {
+		if dt.Map != nil {
+			elemSize = jen.Parens(jen.Add(keySize).Op("+").Add(elemSize))
+		}
+
+		total := jen.Id("len").Call(v).Op("*").Add(elemSize)
+		if header != nil {
+			total = jen.Add(header).Op("+").Add(total)
+		}
+
+		return []jen.Code{jen.Id("n").Op("+=").Add(total)}
+	}
+
+	body := make([]jen.Code, 0)
+	if dt.Map != nil {
+		body = append(body, jen.Id("n").Op("+=").Add(sizeValue(dt.Map.Key, elemId("k", depth))))
+	}
+
+	body = append(body, sizeElement(elem, elemId("v", depth), depth+1)...)
+
+	// range over only the keys where values are of a fixed size
+	vars := jen.List(k, elemId("v", depth))
+	if elemSize != nil {
+		vars = elemId("k", depth)
+	}
+
+	out := make([]jen.Code, 0, 2)
+	if header != nil {
+		out = append(out, jen.Id("n").Op("+=").Add(header))
+	}
+
+	return append(out, jen.For(vars.Op(":=").Range().Add(v)).Block(body...))
+}
+
+// fixedSize returns the mint constant holding the size of mint type dt,
+// or nil where dt isn't a fixed size scalar
+func fixedSize(dt *parser.DataType) jen.Code {
+	if dt.Scalar == nil {
+		return nil
+	}
+
+	return scalarToSizeJen(dt.Scalar.Type)
+}
+
+// scalarToSizeJen returns the mint constant holding the size of scalar
+// type ts, or nil where ts isn't a fixed size scalar
+func scalarToSizeJen(ts string) jen.Code {
+	switch ts {
+	case "datetime":
+		return jen.Qual(mintPath, "DatetimeSize")
+
+	case "uuid":
+		return jen.Qual(mintPath, "UuidSize")
+
+	case "uint32":
+		return jen.Qual(mintPath, "Uint32Size")
+
+	case "int16":
+		return jen.Qual(mintPath, "Int16Size")
+
+	case "int32":
+		return jen.Qual(mintPath, "Int32Size")
+
+	case "int64":
+		return jen.Qual(mintPath, "Int64Size")
+
+	case "float32":
+		return jen.Qual(mintPath, "Float32Size")
+
+	case "float64":
+		return jen.Qual(mintPath, "Float64Size")
+
+	case "bool":
+		return jen.Qual(mintPath, "BoolSize")
+
+	case "byte", "uint8":
+		return jen.Qual(mintPath, "ByteSize")
+
+	case "uint16":
+		return jen.Qual(mintPath, "Uint16Size")
+	}
+
+	return nil
+}
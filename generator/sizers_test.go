@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/vinyl-linux/mint/parser"
+)
+
+func TestGenerator_generateSizer(t *testing.T) {
+	g := new(Generator)
+
+	expect := `package test
+
+import mint "github.com/vinyl-linux/mint"
+
+func (sf SomeTestType) sizeSomeStringSlice() (n int) {
+	n = mint.CollectionHeaderSize
+	for _, v := range sf.SomeStringSlice {
+		n += mint.StringSize(v)
+	}
+	return
+}
+func (sf SomeTestType) sizeSomeStringSlice() (n int) {
+	for _, v := range sf.SomeStringSlice {
+		n += mint.StringSize(v)
+	}
+	return
+}
+func (sf SomeTestType) sizeSomeStringSlice() (n int) {
+	n = mint.CollectionHeaderSize
+	for k := range sf.SomeStringSlice {
+		n += mint.StringSize(k) + mint.Int64Size
+	}
+	return
+}
+func (sf SomeTestType) MarshalledSize() (n int) {
+	n += sf.sizeSomeStringSlice()
+	n += sf.sizeSomeStringSlice()
+	n += sf.sizeSomeStringSlice()
+	n += mint.UuidSize
+	n += sf.Thingy.MarshalledSize()
+	return
+}
+`
+	received := codeSliceToFile(g.generateSizer(simpleType))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
+func TestGenerator_sizeSliceArray(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		ae     parser.AnnotatedEntry
+		expect string
+	}{
+		{"Bad input does nothing", parser.AnnotatedEntry{}, ""},
+		{"Non-slice returns nothing", parser.AnnotatedEntry{Field: parser.Field{DataType: &parser.DataType{}}}, ""},
+		{"Complex types size themselves", userDefinedSliceEntry, `func (sf TestType) sizeThingy() (n int) {
+	n = mint.CollectionHeaderSize
+	for _, v := range sf.Thingy {
+		n += v.MarshalledSize()
+	}
+	return
+}`},
+		{"Slices of fixed size scalars are multiplied out", parser.AnnotatedEntry{Field: parser.Field{Name: "Thingy", DataType: &parser.DataType{Slice: &parser.SliceType{Type: scalarDataType("int16")}}}}, `func (sf TestType) sizeThingy() (n int) {
+	return mint.CollectionHeaderSize + len(sf.Thingy)*mint.Int16Size
+}`},
+		{"Arrays of fixed size scalars have no header", parser.AnnotatedEntry{Field: parser.Field{Name: "Thingy", DataType: &parser.DataType{FixedSizeSlice: &parser.FixedSizedSliceType{Type: scalarDataType("int16"), Size: 4}}}}, `func (sf TestType) sizeThingy() (n int) {
+	return len(sf.Thingy) * mint.Int16Size
+}`},
+		{"Slices of slices of fixed size scalars multiply out each", nestedSliceEntry, `func (sf TestType) sizeMatrix() (n int) {
+	n = mint.CollectionHeaderSize
+	for _, v := range sf.Matrix {
+		n += mint.CollectionHeaderSize + len(v)*mint.Float64Size
+	}
+	return
+}`},
+		{"Arrays of slices are sized element by element", nestedArrayEntry, `func (sf TestType) sizeHalves() (n int) {
+	for _, v := range sf.Halves {
+		n += mint.CollectionHeaderSize
+		for _, v1 := range v {
+			n += mint.StringSize(v1)
+		}
+	}
+	return
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g := new(Generator)
+			received := codeToString(g.sizeSliceArray("TestType", test.ae))
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
+
+func TestGenerator_sizeMap(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		ae     parser.AnnotatedEntry
+		expect string
+	}{
+		{"map of complex to builtin", complexToBuiltinMap, `func (sf TestType) sizeThingy() (n int) {
+	n = mint.CollectionHeaderSize
+	for k := range sf.Thingy {
+		n += k.MarshalledSize() + mint.BoolSize
+	}
+	return
+}`},
+		{"map of builtin to complex", builtinToComplexMap, `func (sf TestType) sizeThingy() (n int) {
+	n = mint.CollectionHeaderSize
+	for k, v := range sf.Thingy {
+		n += mint.StringSize(k) + v.MarshalledSize()
+	}
+	return
+}`},
+		{"map of fixed size scalars", parser.AnnotatedEntry{Field: parser.Field{Name: "Thingy", DataType: &parser.DataType{Map: &parser.MapType{Key: "uuid", Value: scalarDataType("int32")}}}}, `func (sf TestType) sizeThingy() (n int) {
+	return mint.CollectionHeaderSize + len(sf.Thingy)*(mint.UuidSize+mint.Int32Size)
+}`},
+		{"map of builtin to collection", nestedMapEntry, `func (sf TestType) sizeGroups() (n int) {
+	n = mint.CollectionHeaderSize
+	for k, v := range sf.Groups {
+		n += mint.StringSize(k)
+		n += mint.CollectionHeaderSize
+		for _, v1 := range v {
+			n += v1.MarshalledSize()
+		}
+	}
+	return
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g := new(Generator)
+			received := codeToString(g.sizeMap("TestType", test.ae))
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
+
+func TestGenerator_sizeEnum(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf TestEnum) MarshalledSize() int {
+	return mint.ByteSize
+}`
+	received := codeToString(g.sizeEnum(parser.Enum{Name: "TestEnum"}))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
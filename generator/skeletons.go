@@ -4,8 +4,15 @@ import (
 	"github.com/dave/jennifer/jen"
 )
 
-func (g *Generator) generateSkeletonValidation(t string, fn string) jen.Code {
-	return jen.Func().Params(jen.Id("sf").Id(t)).Id(toCamel(fn)).Params(jen.Id("string"), jen.Id("any")).Params(jen.Id("error")).Block(
+// generateSkeletonValidation creates an empty custom validation, which
+// takes a field name and value, followed by nargs arguments
+func (g *Generator) generateSkeletonValidation(t string, fn string, nargs int) jen.Code {
+	params := []jen.Code{jen.Id("string"), jen.Id("any")}
+	for i := 0; i < nargs; i++ {
+		params = append(params, jen.Id("any"))
+	}
+
+	return jen.Func().Params(jen.Id("sf").Id(t)).Id(toCamel(fn)).Params(params...).Params(jen.Id("error")).Block(
 		jen.Return(jen.Id("nil")),
 	)
 }
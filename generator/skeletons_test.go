@@ -5,15 +5,26 @@ import (
 )
 
 func TestGenerator_generateSkeletonValidation(t *testing.T) {
-	expect := `func (sf TestType) ValidateSomeField(string, any) error {
+	for _, test := range []struct {
+		name   string
+		nargs  int
+		expect string
+	}{
+		{"No arguments", 0, `func (sf TestType) ValidateSomeField(string, any) error {
 	return nil
-}`
-
-	g := new(Generator)
-	received := codeToString(g.generateSkeletonValidation("TestType", "validate_some_field"))
+}`},
+		{"Arguments", 2, `func (sf TestType) ValidateSomeField(string, any, any, any) error {
+	return nil
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g := new(Generator)
+			received := codeToString(g.generateSkeletonValidation("TestType", "validate_some_field", test.nargs))
 
-	if expect != received {
-		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
 	}
 }
 
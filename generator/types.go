@@ -3,6 +3,7 @@ package generator
 import (
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/dave/jennifer/jen"
 	"github.com/vinyl-linux/mint/parser"
@@ -14,10 +15,19 @@ func (g *Generator) generateForType(t parser.AnnotatedType) (err error) {
 	ret := jen.NewFile(g.PackageName)
 
 	ret.Add(g.generateTypeDefinition(t))
+	ret.Add(g.generateConstructor(t))
 	ret.Add(g.generateValidations(t))
 	ret.Add(g.generateTransformations(t))
 	ret.Add(g.generateValuer(t.Name))
 
+	for _, f := range g.generateFingerprint(t) {
+		ret.Add(f)
+	}
+
+	if _, optionals := presenceBits(t); optionals > 0 {
+		ret.Add(g.generatePresence(t))
+	}
+
 	// We need to manually run these loops, rather than exploding
 	// the output of generateUnmarshaller (etc.) as per:
 	//
@@ -33,6 +43,18 @@ func (g *Generator) generateForType(t parser.AnnotatedType) (err error) {
 		ret.Add(u)
 	}
 
+	for _, u := range g.generateAppender(t) {
+		ret.Add(u)
+	}
+
+	for _, u := range g.generateBytesUnmarshaller(t) {
+		ret.Add(u)
+	}
+
+	for _, u := range g.generateSizer(t) {
+		ret.Add(u)
+	}
+
 	err = g.writeSkeletons(t.Name)
 	if err != nil {
 		return
@@ -50,6 +72,10 @@ func (g *Generator) generateTypeDefinition(at parser.AnnotatedType) (c jen.Code)
 			fields = append(fields, jen.Null().Comment(f.DocString))
 		}
 
+		if f.Deprecated {
+			fields = append(fields, deprecation(f)...)
+		}
+
 		fields = append(fields, jen.Null().Id(f.Name).Add(toJenType(f.Field)))
 	}
 
@@ -57,3 +83,138 @@ func (g *Generator) generateTypeDefinition(at parser.AnnotatedType) (c jen.Code)
 		fields...,
 	)
 }
+
+// deprecation returns the comment marking field f as deprecated, as a
+// paragraph of its own where f has a doc string, so that go tooling
+// recognises it
+func deprecation(f parser.AnnotatedEntry) []jen.Code {
+	msg := "Deprecated: " + f.Name + " is deprecated"
+	if f.DeprecatedReason != "" {
+		msg = "Deprecated: " + f.DeprecatedReason
+	}
+
+	if len(f.DocString) == 0 {
+		return []jen.Code{jen.Null().Comment(msg)}
+	}
+
+	return []jen.Code{
+		jen.Null().Comment(""),
+		jen.Null().Comment(msg),
+	}
+}
+
+// generateConstructor creates a New<Type> function, returning an instance
+// of a type with each field set to its default value.
+//
+// Fields of user defined types which themselves have defaults are set via
+// that type's constructor, so defaults apply no matter how deeply a type
+// is nested
+func (g *Generator) generateConstructor(at parser.AnnotatedType) jen.Code {
+	values := make(jen.Dict)
+	for _, f := range at.Entries {
+		switch {
+		case f.Default != nil:
+			values[jen.Id(f.Name)] = defaultValue(f)
+
+		case !f.Optional && f.DataType.Scalar != nil && hasDefaults(g.ast, f.DataType.Scalar.Type, nil):
+			values[jen.Id(f.Name)] = jen.Op("*").Add(constructorId(f.DataType.Scalar.Type)).Call()
+		}
+	}
+
+	name := constructorName(at.Name)
+
+	return jen.Null().Comment(name + " returns a new " + at.Name + ", with each field set to its default value").Line().
+		Func().Id(name).Params().Op("*").Id(at.Name).Block(
+		jen.Return(jen.Op("&").Id(at.Name).Values(values)),
+	)
+}
+
+// hasDefaults returns true where tn is a user defined type, either of
+// ast or of a package ast imports, with fields which have defaults,
+// either directly or via a nested type
+func hasDefaults(ast *parser.AST, tn string, seen map[*parser.AnnotatedType]bool) bool {
+	if ast == nil {
+		return false
+	}
+
+	if path, name := splitQualified(tn); path != "" {
+		return hasDefaults(ast.Imports[path], name, seen)
+	}
+
+	if seen == nil {
+		seen = make(map[*parser.AnnotatedType]bool)
+	}
+
+	for i := range ast.Types {
+		t := &ast.Types[i]
+		if t.Name != tn || seen[t] {
+			continue
+		}
+
+		seen[t] = true
+
+		for _, f := range t.Entries {
+			if f.Default != nil {
+				return true
+			}
+
+			if !f.Optional && f.DataType.Scalar != nil && hasDefaults(ast, f.DataType.Scalar.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// defaultValue returns the default value of field f as a go literal.
+//
+// Defaults are type checked by the parser, and so any errors converting
+// them are ignored here
+func defaultValue(f parser.AnnotatedEntry) jen.Code {
+	v := f.Default
+	st := f.DataType.Scalar.Type
+
+	switch st {
+	case "datetime":
+		t, _ := time.Parse(time.RFC3339, *v.Quoted)
+		t = t.UTC()
+
+		return jen.Qual("time", "Date").Call(
+			jen.Lit(t.Year()),
+			jen.Qual("time", t.Month().String()),
+			jen.Lit(t.Day()),
+			jen.Lit(t.Hour()),
+			jen.Lit(t.Minute()),
+			jen.Lit(t.Second()),
+			jen.Lit(t.Nanosecond()),
+			jen.Qual("time", "UTC"),
+		)
+
+	case "uuid":
+		return jen.Qual(uuidPath, "Must").Call(jen.Qual(uuidPath, "FromString").Call(jen.Lit(*v.Quoted)))
+
+	}
+
+	if _, ok := parser.Scalars[st]; ok {
+		return valueLiteral(*v)
+	}
+
+	// Anything else is an enum
+	return qualifiedId(enumValueString(st, *v.Ident))
+}
+
+func constructorName(tn string) string {
+	return "New" + tn
+}
+
+// constructorId returns the constructor of type tn, qualified by
+// package where tn is imported
+func constructorId(tn string) *jen.Statement {
+	path, name := splitQualified(tn)
+	if path == "" {
+		return jen.Id(constructorName(name))
+	}
+
+	return jen.Qual(path, constructorName(name))
+}
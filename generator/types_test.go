@@ -5,13 +5,38 @@ import (
 	"github.com/vinyl-linux/mint/parser"
 )
 
+// scalarDataType returns the data type of scalar, or user defined, type t
+func scalarDataType(t string) *parser.DataType {
+	return &parser.DataType{
+		Scalar: &parser.Scalar{
+			Type: t,
+		},
+	}
+}
+
+// defaultEntry returns a field named n, of scalar (or enum) type t,
+// with default value v
+func defaultEntry(n, t string, v parser.Value) parser.AnnotatedEntry {
+	return parser.AnnotatedEntry{
+		Default: &v,
+		Field: parser.Field{
+			Name:     n,
+			DataType: scalarDataType(t),
+		},
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
 var (
 	nonFixedLengthSlice = parser.AnnotatedEntry{
 		Field: parser.Field{
 			Name: "SomeStringSlice",
 			DataType: &parser.DataType{
 				Slice: &parser.SliceType{
-					Type: "string",
+					Type: scalarDataType("string"),
 				},
 			},
 		},
@@ -28,7 +53,7 @@ var (
 			Name: "SomeStringSlice",
 			DataType: &parser.DataType{
 				FixedSizeSlice: &parser.FixedSizedSliceType{
-					Type: "string",
+					Type: scalarDataType("string"),
 					Size: 5,
 				},
 			},
@@ -52,7 +77,7 @@ var (
 			DataType: &parser.DataType{
 				Map: &parser.MapType{
 					Key:   "string",
-					Value: "int64",
+					Value: scalarDataType("int64"),
 				},
 			},
 		},
@@ -96,7 +121,7 @@ var (
 			Name: "Thingy",
 			DataType: &parser.DataType{
 				Slice: &parser.SliceType{
-					Type: "BlahType",
+					Type: scalarDataType("BlahType"),
 				},
 			},
 		},
@@ -108,7 +133,7 @@ var (
 			DataType: &parser.DataType{
 				Map: &parser.MapType{
 					Key:   "string",
-					Value: "BlahType",
+					Value: scalarDataType("BlahType"),
 				},
 			},
 		},
@@ -120,7 +145,7 @@ var (
 			DataType: &parser.DataType{
 				Map: &parser.MapType{
 					Key:   "BlahType",
-					Value: "bool",
+					Value: scalarDataType("bool"),
 				},
 			},
 		},
@@ -132,7 +157,208 @@ var (
 			DataType: &parser.DataType{
 				Map: &parser.MapType{
 					Key:   "BlahType",
-					Value: "BlahType",
+					Value: scalarDataType("BlahType"),
+				},
+			},
+		},
+	}
+
+	nestedSliceEntry = parser.AnnotatedEntry{
+		Field: parser.Field{
+			Name: "Matrix",
+			DataType: &parser.DataType{
+				Slice: &parser.SliceType{
+					Type: &parser.DataType{
+						Slice: &parser.SliceType{
+							Type: scalarDataType("float64"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	nestedArrayEntry = parser.AnnotatedEntry{
+		Field: parser.Field{
+			Name: "Halves",
+			DataType: &parser.DataType{
+				FixedSizeSlice: &parser.FixedSizedSliceType{
+					Size: 2,
+					Type: &parser.DataType{
+						Slice: &parser.SliceType{
+							Type: scalarDataType("string"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	nestedMapEntry = parser.AnnotatedEntry{
+		Field: parser.Field{
+			Name: "Groups",
+			DataType: &parser.DataType{
+				Map: &parser.MapType{
+					Key: "string",
+					Value: &parser.DataType{
+						Slice: &parser.SliceType{
+							Type: scalarDataType("BlahType"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	optionalScalarEntry = parser.AnnotatedEntry{
+		Validations: []parser.Validation{
+			{
+				IsCustom: false,
+				Function: "string_not_empty",
+			},
+		},
+		Transformations: []parser.Transformation{
+			{
+				IsCustom: false,
+				Function: "to_lower",
+			},
+		},
+		Field: parser.Field{
+			Name:     "Nickname",
+			Tag:      2,
+			Optional: true,
+			DataType: &parser.DataType{
+				Scalar: &parser.Scalar{
+					Type: "string",
+				},
+			},
+		},
+	}
+
+	optionalUserDefinedEntry = parser.AnnotatedEntry{
+		Field: parser.Field{
+			Name:     "Thingy",
+			Tag:      1,
+			Optional: true,
+			DataType: &parser.DataType{
+				Scalar: &parser.Scalar{
+					Type: "BlahType",
+				},
+			},
+		},
+	}
+
+	optionalType = parser.AnnotatedType{
+		Name: "OptionalType",
+		Entries: []parser.AnnotatedEntry{
+			scalarEntry,
+			optionalScalarEntry,
+			optionalUserDefinedEntry,
+		},
+	}
+
+	defaultsType = parser.AnnotatedType{
+		Name: "DefaultsType",
+		Entries: []parser.AnnotatedEntry{
+			defaultEntry("Region", "string", parser.Value{Quoted: ptr("eu-west-1")}),
+			defaultEntry("Offset", "int16", parser.Value{Number: ptr("-12")}),
+			defaultEntry("Ratio", "float32", parser.Value{Number: ptr("0.5")}),
+			defaultEntry("Enabled", "bool", parser.Value{Ident: ptr("true")}),
+			defaultEntry("Epoch", "datetime", parser.Value{Quoted: ptr("2000-01-01T01:00:00+01:00")}),
+			defaultEntry("Namespace", "uuid", parser.Value{Quoted: ptr("6ba7b810-9dad-11d1-80b4-00c04fd430c8")}),
+			defaultEntry("Level", "Level", parser.Value{Ident: ptr("High")}),
+			userDefinedScalarEntry,
+			optionalUserDefinedEntry,
+		},
+	}
+
+	evolvableType = parser.AnnotatedType{
+		Name:      "EvolvableType",
+		Evolvable: true,
+		Entries: []parser.AnnotatedEntry{
+			defaultEntry("Region", "string", parser.Value{Quoted: ptr("eu-west-1")}),
+			optionalScalarEntry,
+		},
+	}
+
+	evolvableNoOptionalsType = parser.AnnotatedType{
+		Name:      "EvolvableNoOptionalsType",
+		Evolvable: true,
+		Entries: []parser.AnnotatedEntry{
+			defaultEntry("Region", "string", parser.Value{Quoted: ptr("eu-west-1")}),
+		},
+	}
+
+	deprecatedType = parser.AnnotatedType{
+		Name:         "DeprecatedType",
+		ReservedTags: []int{1, 3, 4},
+		Entries: []parser.AnnotatedEntry{
+			{
+				Field: parser.Field{
+					Name:     "Optional",
+					Tag:      5,
+					Optional: true,
+					DataType: scalarDataType("int32"),
+				},
+				Deprecated: true,
+			},
+			{
+				Field: parser.Field{
+					Name:     "Name",
+					DataType: scalarDataType("string"),
+				},
+			},
+			{
+				Field: parser.Field{
+					Name:     "Old",
+					Tag:      2,
+					DataType: scalarDataType("string"),
+				},
+				DocString:        "Old is the old name",
+				Deprecated:       true,
+				DeprecatedReason: "use Name instead",
+			},
+		},
+	}
+
+	constsType = parser.AnnotatedType{
+		Name: "ConstsType",
+		Entries: []parser.AnnotatedEntry{
+			{
+				Validations: []parser.Validation{
+					{
+						IsCustom: true,
+						Function: "max_length",
+						Args: []*parser.Value{
+							{Ident: ptr("MaxTags")},
+							{Number: ptr("-1.5")},
+							{Quoted: ptr("tags")},
+						},
+					},
+				},
+				Field: parser.Field{
+					Name: "Tags",
+					DataType: &parser.DataType{
+						FixedSizeSlice: &parser.FixedSizedSliceType{
+							Size:    16,
+							SizeRef: "MaxTags",
+							Type:    scalarDataType("string"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// defaultsAST holds a BlahType with defaults of its own, which
+	// DefaultsType should pick up
+	defaultsAST = &parser.AST{
+		Types: []parser.AnnotatedType{
+			defaultsType,
+			{
+				Name: "BlahType",
+				Entries: []parser.AnnotatedEntry{
+					defaultEntry("Count", "int32", parser.Value{Number: ptr("3")}),
 				},
 			},
 		},
@@ -167,11 +393,9 @@ func (sf *SomeTestType) unmarshallSomeStringSlice(r io.Reader) (err error) {
 		sf.SomeStringSlice = nil
 		return
 	}
-	f.V = make([]mint.MarshallerUnmarshallerValuer, f.Len())
-	for i := range f.V {
-		f.V[i] = mint.NewStringScalar("")
-	}
-	err = f.Unmarshall(r)
+	err = f.UnmarshallElements(r, func() mint.MarshallerUnmarshallerValuer {
+		return mint.NewStringScalar("")
+	})
 	if err != nil {
 		return
 	}
@@ -206,14 +430,15 @@ func (sf *SomeTestType) unmarshallSomeStringSlice(r io.Reader) (err error) {
 		sf.SomeStringSlice = nil
 		return
 	}
-	for i := 0; i < f.Len(); i++ {
-		f.V[mint.NewStringScalar("")] = mint.NewInt64Scalar(int64(0))
-	}
-	err = f.Unmarshall(r)
+	err = f.UnmarshallEntries(r, func() mint.MarshallerUnmarshallerValuer {
+		return mint.NewStringScalar("")
+	}, func() mint.MarshallerUnmarshallerValuer {
+		return mint.NewInt64Scalar(int64(0))
+	})
 	if err != nil {
 		return
 	}
-	sf.SomeStringSlice = make(map[string]int64)
+	sf.SomeStringSlice = make(map[string]int64, len(f.V))
 	for k, v := range f.Value().(map[mint.MarshallerUnmarshallerValuer]mint.MarshallerUnmarshallerValuer) {
 		sf.SomeStringSlice[k.Value().(string)] = v.Value().(int64)
 	}
@@ -238,20 +463,25 @@ func (sf *SomeTestType) unmarshallThingy(r io.Reader) (err error) {
 	return
 }
 func (sf *SomeTestType) Unmarshall(r io.Reader) (err error) {
-	if err = sf.unmarshallSomeStringSlice(r); err != nil {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
 		return
 	}
-	if err = sf.unmarshallSomeStringSlice(r); err != nil {
-		return
+	defer dr.Leave()
+	if err = sf.unmarshallSomeStringSlice(dr); err != nil {
+		return mint.WrapDecodeError(dr, "SomeTestType", "SomeStringSlice", err)
 	}
-	if err = sf.unmarshallSomeStringSlice(r); err != nil {
-		return
+	if err = sf.unmarshallSomeStringSlice(dr); err != nil {
+		return mint.WrapDecodeError(dr, "SomeTestType", "SomeStringSlice", err)
 	}
-	if err = sf.unmarshallATypeOfSomeType(r); err != nil {
-		return
+	if err = sf.unmarshallSomeStringSlice(dr); err != nil {
+		return mint.WrapDecodeError(dr, "SomeTestType", "SomeStringSlice", err)
 	}
-	if err = sf.unmarshallThingy(r); err != nil {
-		return
+	if err = sf.unmarshallATypeOfSomeType(dr); err != nil {
+		return mint.WrapDecodeError(dr, "SomeTestType", "ATypeOfSomeType", err)
+	}
+	if err = sf.unmarshallThingy(dr); err != nil {
+		return mint.WrapDecodeError(dr, "SomeTestType", "Thingy", err)
 	}
 	if err = sf.Transform(); err != nil {
 		return
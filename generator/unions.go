@@ -0,0 +1,246 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/vinyl-linux/mint/parser"
+)
+
+// generateForUnion creates, for union u:
+//
+//  1. A struct holding whichever variant is set
+//  2. A sealed interface implemented by each variant
+//  3. A type per variant, wrapping that variant's value; and
+//  4. Marshallers, appenders, sizers, unmarshallers, and a valuer
+//
+// On the wire a union is a single discriminator byte, holding the tag of
+// the variant which is set, followed by that variant's value
+func (g *Generator) generateForUnion(u parser.Union) (err error) {
+	ret := jen.NewFile(g.PackageName)
+
+	for _, d := range g.generateUnionDefinition(u) {
+		ret.Add(d)
+	}
+
+	for _, v := range u.Variants {
+		for _, d := range g.generateUnionVariant(u, v) {
+			ret.Add(d)
+		}
+	}
+
+	// Create validator, marshaller, appender, sizer, unmarshallers, valuer
+	ret.Add(g.validateUnion(u))
+	ret.Add(g.marshallUnion(u))
+	ret.Add(g.appendUnion(u))
+	ret.Add(g.sizeUnion(u))
+	ret.Add(g.unmarshallUnion(u))
+	ret.Add(g.decodeUnion(u))
+	ret.Add(unmarshallBytes(u.Name))
+	ret.Add(g.generateValuer(u.Name))
+
+	return ret.Save(filepath.Join(g.Directory, strings.Join([]string{strings.ToLower(u.Name), "go"}, ".")))
+}
+
+// generateUnionDefinition creates the struct holding a union's variant,
+// and the interface each variant implements
+func (g *Generator) generateUnionDefinition(u parser.Union) []jen.Code {
+	return []jen.Code{
+		jen.Null().Type().Id(u.Name).Struct(
+			jen.Id("Variant").Id(unionVariantInterface(u.Name)),
+		),
+		jen.Comment(unionVariantInterface(u.Name) + " is implemented by each of the variants of " + u.Name),
+		jen.Type().Id(unionVariantInterface(u.Name)).Interface(
+			jen.Id(unionVariantMethod(u.Name)).Params(),
+		),
+	}
+}
+
+// generateUnionVariant creates the type of variant v, which wraps a
+// value of the variant's type, and implements the union's sealed interface
+func (g *Generator) generateUnionVariant(u parser.Union, v *parser.UnionVariant) []jen.Code {
+	vt := unionVariantType(u.Name, v.Name)
+
+	return []jen.Code{
+		jen.Null().Type().Id(vt).Struct(
+			jen.Id("Value").Add(toJenElemType(v.Type)),
+		),
+		jen.Func().Params(jen.Id(vt)).Id(unionVariantMethod(u.Name)).Params().Block(),
+	}
+}
+
+// validateUnion creates an implementation of the mint.Validator interface,
+// ensuring a variant is set and, where that variant is a user defined type,
+// enum, or union, validating it in turn
+func (g Generator) validateUnion(u parser.Union) jen.Code {
+	bind := false
+	for _, v := range u.Variants {
+		bind = bind || isUserType(v.Type)
+	}
+
+	return jen.Func().Params(jen.Id("sf").Id(u.Name)).Id("Validate").Params().Params(jen.Id("error")).
+		Block(
+			unionSwitch(u, bind, func(v *parser.UnionVariant) []jen.Code {
+				if !isUserType(v.Type) {
+					return nil
+				}
+
+				return []jen.Code{
+					jen.If(
+						jen.Id("err").Op(":=").Id("v").Dot("Value").Dot("Validate").Call(),
+						jen.Id("err").Op("!=").Id("nil"),
+					).Block(
+						jen.Return(invalidElement(jen.Lit(v.Name))),
+					),
+				}
+			}, jen.Return(invalidUnionVariant(u.Name))),
+			jen.Return(jen.Id("nil")),
+		)
+}
+
+func (g Generator) marshallUnion(u parser.Union) jen.Code {
+	return marshallerFunc(u.Name, "Marshall",
+		jen.Var().Id("b").Index().Byte(),
+		unionSwitch(u, true, func(v *parser.UnionVariant) []jen.Code {
+			return append(
+				writeValue("byte", jen.Lit(v.Tag)),
+				writeValue(v.Type, jen.Id("v").Dot("Value"))...,
+			)
+		}, jen.Return(invalidUnionVariant(u.Name))),
+		jen.Return(),
+	)
+}
+
+func (g Generator) appendUnion(u parser.Union) jen.Code {
+	return appenderFunc(u.Name, "AppendMarshall",
+		jen.Id("b").Op("=").Id("in"),
+		unionSwitch(u, true, func(v *parser.UnionVariant) []jen.Code {
+			return []jen.Code{
+				appendValue("byte", jen.Lit(v.Tag)),
+				appendValue(v.Type, jen.Id("v").Dot("Value")),
+			}
+		}, jen.Return(jen.Id("in"), invalidUnionVariant(u.Name))),
+		jen.Return(),
+	)
+}
+
+func (g Generator) sizeUnion(u parser.Union) jen.Code {
+	// a variant need only be bound where at least one variant varies
+	// in size; otherwise the compiler complains it's unused
+	bind := false
+	for _, v := range u.Variants {
+		bind = bind || scalarToSizeJen(v.Type) == nil
+	}
+
+	return sizerFunc(u.Name, "MarshalledSize",
+		unionSwitch(u, bind, func(v *parser.UnionVariant) []jen.Code {
+			return []jen.Code{
+				jen.Id("n").Op("=").Qual(mintPath, "ByteSize").Op("+").Add(sizeValue(v.Type, jen.Id("v").Dot("Value"))),
+			}
+		}, nil),
+		jen.Return(),
+	)
+}
+
+func (g Generator) unmarshallUnion(u parser.Union) jen.Code {
+	return unmarshallerFunc(u.Name, "Unmarshall",
+		jen.Id("dr").Op(":=").Qual(mintPath, "AsDecodeReader").Call(jen.Id("r")),
+		jen.If(jen.Id("err").Op("=").Id("dr").Dot("Enter").Call().Id(";").Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(),
+		),
+		jen.Defer().Id("dr").Dot("Leave").Call(),
+		jen.List(jen.Id("tag"), jen.Id("err")).Op(":=").Qual(mintPath, "ReadByte").Call(jen.Id("dr")),
+		jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(wrapDecodeError(u.Name, "")),
+		),
+		tagSwitch(u, func(v *parser.UnionVariant, val *jen.Statement) jen.Code {
+			return readValueFrom("dr", v.Type, val, jen.Return(wrapDecodeError(u.Name, v.Name)))
+		}, jen.Return(jen.Qual(mintPath, "WrapDecodeError").Call(jen.Id("dr"), jen.Lit(u.Name), jen.Lit(""), invalidUnionVariant(u.Name)))),
+		jen.Return(),
+	)
+}
+
+func (g Generator) decodeUnion(u parser.Union) jen.Code {
+	wrap := func(f string, err jen.Code) jen.Code {
+		return jen.Return(jen.Qual(mintPath, "WrapDecodeError").Call(jen.Id("d"), jen.Lit(u.Name), jen.Lit(f), err))
+	}
+
+	return decoderFunc(u.Name, "UnmarshallDecoder",
+		jen.If(jen.Id("err").Op("=").Id("d").Dot("Enter").Call().Id(";").Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(),
+		),
+		jen.Defer().Id("d").Dot("Leave").Call(),
+		jen.List(jen.Id("tag"), jen.Id("err")).Op(":=").Id("d").Dot("ReadByte").Call(),
+		jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
+			wrap("", jen.Id("err")),
+		),
+		tagSwitch(u, func(v *parser.UnionVariant, val *jen.Statement) jen.Code {
+			return decodeValue(v.Type, val, wrap(v.Name, jen.Id("err")))
+		}, wrap("", invalidUnionVariant(u.Name))),
+		jen.Return(),
+	)
+}
+
+// unionSwitch switches on the variant held by a union, running the
+// output of body for each, and onUnset where no variant is set. Where
+// bind is true, the variant is available to body as v
+func unionSwitch(u parser.Union, bind bool, body func(*parser.UnionVariant) []jen.Code, onUnset jen.Code) jen.Code {
+	cases := make([]jen.Code, 0, len(u.Variants)+1)
+	for _, v := range u.Variants {
+		cases = append(cases, jen.Case(jen.Id(unionVariantType(u.Name, v.Name))).Block(body(v)...))
+	}
+
+	if onUnset != nil {
+		cases = append(cases, jen.Default().Block(onUnset))
+	}
+
+	subject := jen.Id("sf").Dot("Variant").Assert(jen.Type())
+	if bind {
+		subject = jen.Id("v").Op(":=").Add(subject)
+	}
+
+	return jen.Switch(subject).Block(cases...)
+}
+
+// tagSwitch switches on a discriminator, tag, which has been read from
+// the wire, reading the relevant variant with read, and running onUnknown
+// where tag doesn't correspond to any variant
+func tagSwitch(u parser.Union, read func(*parser.UnionVariant, *jen.Statement) jen.Code, onUnknown jen.Code) jen.Code {
+	cases := make([]jen.Code, 0, len(u.Variants)+1)
+	for _, v := range u.Variants {
+		cases = append(cases, jen.Case(jen.Lit(v.Tag)).Block(
+			jen.Id("v").Op(":=").Id(unionVariantType(u.Name, v.Name)).Values(),
+			read(v, jen.Id("v").Dot("Value")),
+			jen.Id("sf").Dot("Variant").Op("=").Id("v"),
+		))
+	}
+
+	cases = append(cases, jen.Default().Block(onUnknown))
+
+	return jen.Switch(jen.Id("tag")).Block(cases...)
+}
+
+// invalidUnionVariant returns the error generated code returns when a
+// union has no variant set, or a discriminator doesn't match a variant
+func invalidUnionVariant(u string) jen.Code {
+	return jen.Qual("errors", "New").Call(jen.Lit("invalid variant for union " + u))
+}
+
+// unionVariantInterface returns the name of the sealed interface
+// implemented by each variant of union u
+func unionVariantInterface(u string) string {
+	return u + "Variant"
+}
+
+// unionVariantMethod returns the name of the unexported method which
+// seals the variant interface of union u
+func unionVariantMethod(u string) string {
+	return "is" + u + "Variant"
+}
+
+// unionVariantType returns the name of the type representing variant
+// v of union u
+func unionVariantType(u, v string) string {
+	return u + v
+}
@@ -0,0 +1,198 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/vinyl-linux/mint/parser"
+)
+
+var (
+	testUnion = parser.Union{
+		Name: "TestUnion",
+		Variants: []*parser.UnionVariant{
+			{Type: "BlahType", Name: "Thingy", Tag: 0},
+			{Type: "bool", Name: "Flag", Tag: 3},
+		},
+	}
+
+	fixedSizeUnion = parser.Union{
+		Name: "TestUnion",
+		Variants: []*parser.UnionVariant{
+			{Type: "bool", Name: "Flag", Tag: 0},
+			{Type: "int64", Name: "Count", Tag: 1},
+		},
+	}
+)
+
+func TestGenerator_Union(t *testing.T) {
+	g := new(Generator)
+
+	for _, test := range []struct {
+		name   string
+		f      func() string
+		expect string
+	}{
+		{"Definition", func() string { return codeSliceToFile(g.generateUnionDefinition(testUnion)) }, `package test
+
+type TestUnion struct {
+	Variant TestUnionVariant
+}
+
+// TestUnionVariant is implemented by each of the variants of TestUnion
+type TestUnionVariant interface {
+	isTestUnionVariant()
+}
+`},
+		{"Variant", func() string { return codeSliceToFile(g.generateUnionVariant(testUnion, testUnion.Variants[1])) }, `package test
+
+type TestUnionFlag struct {
+	Value bool
+}
+
+func (TestUnionFlag) isTestUnionVariant() {}
+`},
+		{"Validate", func() string { return codeToString(g.validateUnion(testUnion)) }, `func (sf TestUnion) Validate() error {
+	switch v := sf.Variant.(type) {
+	case TestUnionThingy:
+		if err := v.Value.Validate(); err != nil {
+			return mint.ErrInvalidElement{Path: "Thingy", Err: err}
+		}
+	case TestUnionFlag:
+	default:
+		return errors.New("invalid variant for union TestUnion")
+	}
+	return nil
+}`},
+		{"Validate of scalar variants", func() string { return codeToString(g.validateUnion(fixedSizeUnion)) }, `func (sf TestUnion) Validate() error {
+	switch sf.Variant.(type) {
+	case TestUnionFlag:
+	case TestUnionCount:
+	default:
+		return errors.New("invalid variant for union TestUnion")
+	}
+	return nil
+}`},
+		{"Marshall", func() string { return codeToString(g.marshallUnion(testUnion)) }, `func (sf TestUnion) Marshall(w io.Writer) (err error) {
+	var b []byte
+	switch v := sf.Variant.(type) {
+	case TestUnionThingy:
+		b = mint.AppendByte(b[:0], 0)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+		if err = v.Value.Marshall(w); err != nil {
+			return
+		}
+	case TestUnionFlag:
+		b = mint.AppendByte(b[:0], 3)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+		b = mint.AppendBool(b[:0], v.Value)
+		if _, err = w.Write(b); err != nil {
+			return
+		}
+	default:
+		return errors.New("invalid variant for union TestUnion")
+	}
+	return
+}`},
+		{"AppendMarshall", func() string { return codeToString(g.appendUnion(testUnion)) }, `func (sf TestUnion) AppendMarshall(in []byte) (b []byte, err error) {
+	b = in
+	switch v := sf.Variant.(type) {
+	case TestUnionThingy:
+		b = mint.AppendByte(b, 0)
+		if b, err = v.Value.AppendMarshall(b); err != nil {
+			return
+		}
+	case TestUnionFlag:
+		b = mint.AppendByte(b, 3)
+		b = mint.AppendBool(b, v.Value)
+	default:
+		return in, errors.New("invalid variant for union TestUnion")
+	}
+	return
+}`},
+		{"MarshalledSize", func() string { return codeToString(g.sizeUnion(testUnion)) }, `func (sf TestUnion) MarshalledSize() (n int) {
+	switch v := sf.Variant.(type) {
+	case TestUnionThingy:
+		n = mint.ByteSize + v.Value.MarshalledSize()
+	case TestUnionFlag:
+		n = mint.ByteSize + mint.BoolSize
+	}
+	return
+}`},
+		{"MarshalledSize of fixed size variants", func() string { return codeToString(g.sizeUnion(fixedSizeUnion)) }, `func (sf TestUnion) MarshalledSize() (n int) {
+	switch sf.Variant.(type) {
+	case TestUnionFlag:
+		n = mint.ByteSize + mint.BoolSize
+	case TestUnionCount:
+		n = mint.ByteSize + mint.Int64Size
+	}
+	return
+}`},
+		{"Unmarshall", func() string { return codeToString(g.unmarshallUnion(testUnion)) }, `func (sf *TestUnion) Unmarshall(r io.Reader) (err error) {
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	tag, err := mint.ReadByte(dr)
+	if err != nil {
+		return mint.WrapDecodeError(dr, "TestUnion", "", err)
+	}
+	switch tag {
+	case 0:
+		v := TestUnionThingy{}
+		if err = v.Value.Unmarshall(dr); err != nil {
+			return mint.WrapDecodeError(dr, "TestUnion", "Thingy", err)
+		}
+		sf.Variant = v
+	case 3:
+		v := TestUnionFlag{}
+		if v.Value, err = mint.ReadBool(dr); err != nil {
+			return mint.WrapDecodeError(dr, "TestUnion", "Flag", err)
+		}
+		sf.Variant = v
+	default:
+		return mint.WrapDecodeError(dr, "TestUnion", "", errors.New("invalid variant for union TestUnion"))
+	}
+	return
+}`},
+		{"UnmarshallDecoder", func() string { return codeToString(g.decodeUnion(testUnion)) }, `func (sf *TestUnion) UnmarshallDecoder(d *mint.BytesDecoder) (err error) {
+	if err = d.Enter(); err != nil {
+		return
+	}
+	defer d.Leave()
+	tag, err := d.ReadByte()
+	if err != nil {
+		return mint.WrapDecodeError(d, "TestUnion", "", err)
+	}
+	switch tag {
+	case 0:
+		v := TestUnionThingy{}
+		if err = v.Value.UnmarshallDecoder(d); err != nil {
+			return mint.WrapDecodeError(d, "TestUnion", "Thingy", err)
+		}
+		sf.Variant = v
+	case 3:
+		v := TestUnionFlag{}
+		if v.Value, err = d.ReadBool(); err != nil {
+			return mint.WrapDecodeError(d, "TestUnion", "Flag", err)
+		}
+		sf.Variant = v
+	default:
+		return mint.WrapDecodeError(d, "TestUnion", "", errors.New("invalid variant for union TestUnion"))
+	}
+	return
+}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			received := test.f()
+
+			if test.expect != received {
+				t.Errorf("expected\n%s\nreceived\n%s", test.expect, received)
+			}
+		})
+	}
+}
@@ -5,7 +5,14 @@ import (
 	"github.com/vinyl-linux/mint/parser"
 )
 
+// unmarshallSliceArray creates a function which unmarshalls slice or
+// array e; as with marshallSliceArray, nested collections are always
+// decoded directly
 func (g Generator) unmarshallSliceArray(t string, e parser.AnnotatedEntry) jen.Code {
+	if g.DirectEncoding || e.DataType.IsNested() {
+		return g.unmarshallSliceArrayDirect(t, e)
+	}
+
 	fn := unmarshallerFuncName(e.Name)
 	var (
 		block []jen.Code
@@ -19,26 +26,37 @@ func (g Generator) unmarshallSliceArray(t string, e parser.AnnotatedEntry) jen.C
 
 	switch {
 	case e.Field.DataType.Slice != nil:
-		block = unmarshallSlicePreludeGetLen(e)
-		dt = e.Field.DataType.Slice.Type
-		maker = jen.Id("sf").Dot(e.Field.Name).Op("=").Id("make").Call(jen.Index().Id(dt), jen.Id("f").Dot("Len").Call())
+		dt = e.Field.DataType.Slice.Type.Scalar.Type
+		innerInitialiser, innerNilValue, _ := scalarToMintJen(dt)
+
+		// slices grow as their elements are read, rather than
+		// trusting the declared length up front
+		block = append(unmarshallSlicePreludeGetLen(e),
+			jen.Id("err").Op("=").Id("f").Dot("UnmarshallElements").Call(jen.Id("r"), jen.Func().Params().Add(muvType).Block(
+				jen.Return(jen.Add(innerInitialiser).Call(innerNilValue)),
+			)),
+		)
+		maker = jen.Id("sf").Dot(e.Field.Name).Op("=").Id("make").Call(jen.Index().Add(toJenElemType(dt)), jen.Id("f").Dot("Len").Call())
 
 	case e.Field.DataType.FixedSizeSlice != nil:
-		block = unmarshallSlicePreludeFixedLen(e)
-		dt = e.Field.DataType.FixedSizeSlice.Type
-		maker = jen.Id("sf").Dot(e.Field.Name).Op("=").Index(jen.Lit(e.Field.DataType.FixedSizeSlice.Size)).Id(dt).Block()
+		dt = e.Field.DataType.FixedSizeSlice.Type.Scalar.Type
+		innerInitialiser, innerNilValue, _ := scalarToMintJen(dt)
+
+		block = append(unmarshallSlicePreludeFixedLen(e),
+			jen.For(jen.List(jen.Id("i"), jen.Null()).Op(":=").Range().Id("f").Dot("V")).Block(
+				jen.Id("f").Dot("V").Index(jen.Id("i")).Op("=").Add(innerInitialiser).Call(innerNilValue),
+			),
+			jen.Id("err").Op("=").Id("f").Dot("Unmarshall").Call(jen.Id("r")),
+		)
+		maker = jen.Id("sf").Dot(e.Field.Name).Op("=").Index(arraySize(e.Field.DataType.FixedSizeSlice)).Add(toJenElemType(dt)).Block()
 
 	default:
 		return jen.Null()
 	}
 
-	innerInitialiser, innerNilValue, innerCastType := scalarToMintJen(dt)
+	_, _, innerCastType := scalarToMintJen(dt)
 
 	block = append(block, []jen.Code{
-		jen.For(jen.List(jen.Id("i"), jen.Null()).Op(":=").Range().Id("f").Dot("V")).Block(
-			jen.Id("f").Dot("V").Index(jen.Id("i")).Op("=").Add(innerInitialiser).Call(innerNilValue),
-		),
-		jen.Id("err").Op("=").Id("f").Dot("Unmarshall").Call(jen.Id("r")),
 		jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
 			jen.Return(),
 		),
@@ -56,11 +74,17 @@ func (g Generator) unmarshallSliceArray(t string, e parser.AnnotatedEntry) jen.C
 		)
 }
 
+// unmarshallMap creates a function which unmarshalls map e; as with
+// marshallMap, maps of collections are always decoded directly
 func (g Generator) unmarshallMap(t string, e parser.AnnotatedEntry) jen.Code {
+	if g.DirectEncoding || e.DataType.IsNested() {
+		return g.unmarshallMapDirect(t, e)
+	}
+
 	fn := unmarshallerFuncName(e.Name)
 
 	keyInitialiser, keyNilValue, keyCastType := scalarToMintJen(e.DataType.Map.Key)
-	valueInitialiser, valueNilValue, valueCastType := scalarToMintJen(e.DataType.Map.Value)
+	valueInitialiser, valueNilValue, valueCastType := scalarToMintJen(e.DataType.Map.Value.Scalar.Type)
 
 	return jen.Func().Params(jen.Id("sf").Op("*").Id(t)).Id(fn).Params(jen.Id("r").Qual("io", "Reader")).Params(jen.Id("err").Id("error")).
 		Block(
@@ -73,16 +97,19 @@ func (g Generator) unmarshallMap(t string, e parser.AnnotatedEntry) jen.Code {
 				jen.Id("sf").Dot(e.Name).Op("=").Id("nil"),
 				jen.Return(),
 			),
-			jen.For(jen.Id("i").Op(":=").Lit(0), jen.Id("i").Op("<").Id("f").Dot("Len").Call(), jen.Id("i").Op("++")).Block(
-				jen.Id("f").Dot("V").Index(jen.Add(keyInitialiser).Call(keyNilValue)).Op("=").Add(valueInitialiser).Call(valueNilValue),
+			jen.Id("err").Op("=").Id("f").Dot("UnmarshallEntries").Call(jen.Id("r"),
+				jen.Func().Params().Add(muvType).Block(
+					jen.Return(jen.Add(keyInitialiser).Call(keyNilValue)),
+				),
+				jen.Func().Params().Add(muvType).Block(
+					jen.Return(jen.Add(valueInitialiser).Call(valueNilValue)),
+				),
 			),
-
-			jen.Id("err").Op("=").Id("f").Dot("Unmarshall").Call(jen.Id("r")),
 			jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
 				jen.Return(),
 			),
 
-			jen.Id("sf").Dot(e.Name).Op("=").Id("make").Call(jen.Map(keyCastType).Add(valueCastType)),
+			jen.Id("sf").Dot(e.Name).Op("=").Id("make").Call(jen.Map(keyCastType).Add(valueCastType), jen.Len(jen.Id("f").Dot("V"))),
 
 			jen.For(jen.List(jen.Id("k"), jen.Id("v")).Op(":=").Range().Id("f").Dot("Value").Call().Assert(jen.Map(muvType).Add(muvType))).Block(
 				jen.Id("sf").Dot(e.Name).Index(jen.Id("k").Dot("Value").Call().Assert(keyCastType)).Op("=").Id("v").Dot("Value").Call().Assert(valueCastType),
@@ -93,18 +120,38 @@ func (g Generator) unmarshallMap(t string, e parser.AnnotatedEntry) jen.Code {
 }
 
 func (g Generator) unmarshallScalar(t string, e parser.AnnotatedEntry) jen.Code {
+	if g.DirectEncoding {
+		return g.unmarshallScalarDirect(t, e)
+	}
+
 	fn := unmarshallerFuncName(e.Name)
 	initialiser, nilValue, castType := scalarToMintJen(e.Field.DataType.Scalar.Type)
 
+	block := []jen.Code{
+		jen.Id("f").Op(":=").Add(initialiser).Call(nilValue),
+		jen.Id("err").Op("=").Id("f").Dot("Unmarshall").Call(jen.Id("r")),
+		jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
+			jen.Return(),
+		),
+	}
+
+	// optional fields point to the value read
+	if e.Optional {
+		block = append(block,
+			jen.Id("v").Op(":=").Id("f").Dot("Value").Call().Assert(castType),
+			jen.Id("sf").Dot(e.Name).Op("=").Op("&").Id("v"),
+		)
+	} else {
+		block = append(block,
+			jen.Id("sf").Dot(e.Name).Op("=").Id("f").Dot("Value").Call().Assert(castType),
+		)
+	}
+
+	block = append(block, jen.Return())
+
 	return jen.Func().Params(jen.Id("sf").Op("*").Id(t)).Id(fn).Params(jen.Id("r").Qual("io", "Reader")).Params(jen.Id("err").Id("error")).
 		Block(
-			jen.Id("f").Op(":=").Add(initialiser).Call(nilValue),
-			jen.Id("err").Op("=").Id("f").Dot("Unmarshall").Call(jen.Id("r")),
-			jen.If(jen.Id("err").Op("!=").Id("nil")).Block(
-				jen.Return(),
-			),
-			jen.Id("sf").Dot(e.Name).Op("=").Id("f").Dot("Value").Call().Assert(castType),
-			jen.Return(),
+			block...,
 		)
 }
 
@@ -137,10 +184,9 @@ func unmarshallSlicePreludeGetLen(e parser.AnnotatedEntry) []jen.Code {
 			jen.Id("sf").Dot(e.Name).Op("=").Id("nil"),
 			jen.Return(),
 		),
-		jen.Id("f").Dot("V").Op("=").Id("make").Call(jen.Index().Qual(mintPath, "MarshallerUnmarshallerValuer"), jen.Id("f").Dot("Len").Call()),
 	}
 }
 
 func unmarshallSlicePreludeFixedLen(e parser.AnnotatedEntry) []jen.Code {
-	return []jen.Code{jen.Id("f").Op(":=").Qual(mintPath, "NewSliceCollection").Call(jen.Id("make").Call(jen.Index().Add(muvType), jen.Lit(e.DataType.FixedSizeSlice.Size)), jen.Id("true"))}
+	return []jen.Code{jen.Id("f").Op(":=").Qual(mintPath, "NewSliceCollection").Call(jen.Id("make").Call(jen.Index().Add(muvType), arraySize(e.DataType.FixedSizeSlice)), jen.Id("true"))}
 }
@@ -17,11 +17,9 @@ var (
 		sf.SomeStringSlice = nil
 		return
 	}
-	f.V = make([]mint.MarshallerUnmarshallerValuer, f.Len())
-	for i := range f.V {
-		f.V[i] = mint.NewStringScalar("")
-	}
-	err = f.Unmarshall(r)
+	err = f.UnmarshallElements(r, func() mint.MarshallerUnmarshallerValuer {
+		return mint.NewStringScalar("")
+	})
 	if err != nil {
 		return
 	}
@@ -59,6 +57,48 @@ func TestGenerator_unmarshallSliceArray(t *testing.T) {
 		{"Fixed length slice writes length", fixedLengthSlice, fixedLengthUnmarshaller},
 		{"Bad input does nothing", parser.AnnotatedEntry{}, ""},
 		{"Non-slice returns nothing", parser.AnnotatedEntry{Field: parser.Field{DataType: &parser.DataType{}}}, ""},
+		{"Nested collections are decoded directly", nestedSliceEntry, `func (sf *TestType) unmarshallMatrix(r io.Reader) (err error) {
+	l, err := mint.ReadSliceLen(r)
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Matrix = nil
+		return
+	}
+	sf.Matrix = make([][]float64, 0, mint.CollectionCap(l))
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := 0; i < l; i++ {
+		var e []float64
+		l1, err := mint.ReadSliceLen(r)
+		if err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		if l1 == 0 {
+			e = nil
+		} else {
+			e = make([]float64, 0, mint.CollectionCap(l1))
+			if err = dr.Enter(); err != nil {
+				return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+			}
+			for i1 := 0; i1 < l1; i1++ {
+				var e1 float64
+				if e1, err = mint.ReadFloat64(r); err != nil {
+					dr.Leave()
+					return mint.WrapDecodeError(r, "", mint.IndexSegment(i), mint.WrapDecodeError(r, "", mint.IndexSegment(i1), err))
+				}
+				e = append(e, e1)
+			}
+			dr.Leave()
+		}
+		sf.Matrix = append(sf.Matrix, e)
+	}
+	return
+}`},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			g := new(Generator)
@@ -85,14 +125,15 @@ func TestGenerator_unmarshallMap(t *testing.T) {
 		sf.SomeStringSlice = nil
 		return
 	}
-	for i := 0; i < f.Len(); i++ {
-		f.V[mint.NewStringScalar("")] = mint.NewInt64Scalar(int64(0))
-	}
-	err = f.Unmarshall(r)
+	err = f.UnmarshallEntries(r, func() mint.MarshallerUnmarshallerValuer {
+		return mint.NewStringScalar("")
+	}, func() mint.MarshallerUnmarshallerValuer {
+		return mint.NewInt64Scalar(int64(0))
+	})
 	if err != nil {
 		return
 	}
-	sf.SomeStringSlice = make(map[string]int64)
+	sf.SomeStringSlice = make(map[string]int64, len(f.V))
 	for k, v := range f.Value().(map[mint.MarshallerUnmarshallerValuer]mint.MarshallerUnmarshallerValuer) {
 		sf.SomeStringSlice[k.Value().(string)] = v.Value().(int64)
 	}
@@ -106,6 +147,62 @@ func TestGenerator_unmarshallMap(t *testing.T) {
 
 }
 
+func TestGenerator_unmarshallMap_Nested(t *testing.T) {
+	g := new(Generator)
+
+	expect := `func (sf *TestType) unmarshallGroups(r io.Reader) (err error) {
+	l, err := mint.ReadMapLen(r)
+	if err != nil {
+		return
+	}
+	if l == 0 {
+		sf.Groups = nil
+		return
+	}
+	sf.Groups = make(map[string][]BlahType, mint.CollectionCap(l))
+	dr := mint.AsDecodeReader(r)
+	if err = dr.Enter(); err != nil {
+		return
+	}
+	defer dr.Leave()
+	for i := 0; i < l; i++ {
+		var k string
+		var v []BlahType
+		if k, err = mint.ReadString(r); err != nil {
+			return mint.WrapDecodeError(r, "", mint.IndexSegment(i), err)
+		}
+		l1, err := mint.ReadSliceLen(r)
+		if err != nil {
+			return mint.WrapDecodeError(r, "", mint.KeySegment(k), err)
+		}
+		if l1 == 0 {
+			v = nil
+		} else {
+			v = make([]BlahType, 0, mint.CollectionCap(l1))
+			if err = dr.Enter(); err != nil {
+				return mint.WrapDecodeError(r, "", mint.KeySegment(k), err)
+			}
+			for i1 := 0; i1 < l1; i1++ {
+				var e1 BlahType
+				if err = e1.Unmarshall(r); err != nil {
+					dr.Leave()
+					return mint.WrapDecodeError(r, "", mint.KeySegment(k), mint.WrapDecodeError(r, "", mint.IndexSegment(i1), err))
+				}
+				v = append(v, e1)
+			}
+			dr.Leave()
+		}
+		sf.Groups[k] = v
+	}
+	return
+}`
+	received := codeToString(g.unmarshallMap("TestType", nestedMapEntry))
+
+	if expect != received {
+		t.Errorf("expected\n%s\nreceived\n%s", expect, received)
+	}
+}
+
 func TestGenerator_unmarshallScalar(t *testing.T) {
 	g := new(Generator)
 
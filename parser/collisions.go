@@ -56,10 +56,10 @@ func (c collisionsErr) Error() string {
 	return strings.Join(out, "\n\n")
 }
 
-// namedSlice coerces a slice of types and enums into a slice of
-// named types to aid the ast solver
-func namedSlice(t []AnnotatedType, e []Enum) (out []named) {
-	out = make([]named, len(t)+len(e))
+// namedSlice coerces a slice of types, enums, unions, and consts into a
+// slice of named types to aid the ast solver
+func namedSlice(t []AnnotatedType, e []Enum, u []Union, c []Const) (out []named) {
+	out = make([]named, len(t)+len(e)+len(u)+len(c))
 	idx := 0
 
 	for _, elem := range t {
@@ -72,6 +72,16 @@ func namedSlice(t []AnnotatedType, e []Enum) (out []named) {
 		idx++
 	}
 
+	for _, elem := range u {
+		out[idx] = elem
+		idx++
+	}
+
+	for _, elem := range c {
+		out[idx] = elem
+		idx++
+	}
+
 	return
 }
 
@@ -2,20 +2,73 @@ package parser
 
 import (
 	"fmt"
+	"hash/fnv"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
 	"unicode"
 
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type AST struct {
-	Types []AnnotatedType
-	Enums []Enum
+	Types  []AnnotatedType
+	Enums  []Enum
+	Unions []Union
+	Consts []Const
+
+	// Package is the name of the package these documents belong to,
+	// if any, and GoPackage the import path of the go package generated
+	// from them, where set by the go_package option
+	Package   string
+	GoPackage string
+
+	// Imports holds each imported package, keyed by the name used to
+	// refer to it, such as geo in geo.Location
+	Imports map[string]*AST
+
+	// imports, dir, and pos are set per document, prior to merging, and
+	// hold that document's import statements, the directory imports are
+	// relative to, and the position of its package statement
+	imports []*Import
+	dir     string
+	pos     lexer.Position
 }
 
-func toAST(d Document) (ad *AST, err error) {
+func toAST(fn string, d Document) (ad *AST, err error) {
 	ad = new(AST)
+	ad.imports = d.Imports
+	ad.dir = filepath.Dir(fn)
+	ad.pos = d.Pos
+
+	if d.Package != nil {
+		ad.Package = d.Package.Name
+		ad.pos = d.Package.Pos
+
+		for _, o := range d.Package.Options {
+			if o.GoPackage == nil {
+				continue
+			}
+
+			if ad.GoPackage != "" && ad.GoPackage != *o.GoPackage {
+				return nil, packageMismatchErr{
+					what: "go_package",
+					values: map[string][]lexer.Position{
+						ad.GoPackage: {ad.pos},
+						*o.GoPackage: {o.Pos},
+					},
+				}
+			}
+
+			ad.GoPackage = *o.GoPackage
+		}
+	}
+
 	ad.Enums = make([]Enum, 0)
 	ad.Types = make([]AnnotatedType, 0)
+	ad.Unions = make([]Union, 0)
+	ad.Consts = make([]Const, 0)
 
 	for _, e := range d.Entries {
 		if e.Enum != nil {
@@ -24,6 +77,23 @@ func toAST(d Document) (ad *AST, err error) {
 			continue
 		}
 
+		if e.Const != nil {
+			ad.Consts = append(ad.Consts, *e.Const)
+
+			continue
+		}
+
+		if e.Union != nil {
+			err = validateUnion(*e.Union)
+			if err != nil {
+				return nil, err
+			}
+
+			ad.Unions = append(ad.Unions, *e.Union)
+
+			continue
+		}
+
 		at, err := toAnnotatedType(*e.Type)
 		if err != nil {
 			return nil, err
@@ -39,6 +109,21 @@ type AnnotatedType struct {
 	Pos     lexer.Position
 	Name    string
 	Entries []AnnotatedEntry
+
+	// Evolvable types are encoded with a length prefix, allowing fields
+	// to be added without breaking existing readers and writers
+	Evolvable bool
+
+	// ReservedTags and ReservedNames are those of fields which have been
+	// removed from this type, and which no field may use
+	ReservedTags  []int
+	ReservedNames []string
+
+	// Fingerprint identifies the encoding of this type, changing whenever
+	// its name, or the name, tag, or type of any of its fields, does,
+	// including any change to the types, enums, and unions those fields
+	// refer to. It is set once a document has been solved
+	Fingerprint uint64
 }
 
 func (at AnnotatedType) name() string {
@@ -49,12 +134,202 @@ func (at AnnotatedType) pos() lexer.Position {
 	return at.Pos
 }
 
+// ByTag returns the entries of at in ascending tag order, which is the
+// order in which they're encoded, regardless of the order in which
+// they're declared
+func (at AnnotatedType) ByTag() []AnnotatedEntry {
+	entries := make([]AnnotatedEntry, len(at.Entries))
+	copy(entries, at.Entries)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Tag < entries[j].Tag
+	})
+
+	return entries
+}
+
+// fingerprint hashes a canonical description of at, a type in ast,
+// listing its fields in tag order, so that the order in which they're
+// declared, their defaults, and their annotations, none of which affect
+// how at is encoded, don't affect its fingerprint.
+//
+// The types, enums, and unions at's fields refer to are described in
+// full, rather than by name, so that changing them changes at's
+// fingerprint too, while renaming the alias an imported package is
+// referred to by doesn't
+func (at *AnnotatedType) fingerprint(ast *AST) uint64 {
+	h := fnv.New64a()
+
+	f := fingerprinter{w: h}
+	f.describeType(ast, at)
+
+	return h.Sum64()
+}
+
+// fingerprinter writes the canonical descriptions fingerprints are taken
+// of.
+//
+// visiting holds the types and unions currently being described,
+// outermost first, so that recursive references are described by how
+// far up they point, rather than endlessly
+type fingerprinter struct {
+	w        io.Writer
+	visiting []any
+}
+
+// describeType describes at, a type in ast
+func (f *fingerprinter) describeType(ast *AST, at *AnnotatedType) {
+	f.visiting = append(f.visiting, at)
+	defer func() { f.visiting = f.visiting[:len(f.visiting)-1] }()
+
+	fmt.Fprintf(f.w, "type %s", at.Name)
+	if at.Evolvable {
+		fmt.Fprint(f.w, " [evolvable]")
+	}
+
+	fmt.Fprintln(f.w, " {")
+
+	for _, e := range at.ByTag() {
+		if e.Optional {
+			fmt.Fprint(f.w, "optional ")
+		}
+
+		f.describeDataType(ast, e.DataType)
+		fmt.Fprintf(f.w, " %s = %d;\n", e.Name, e.Tag)
+	}
+
+	fmt.Fprint(f.w, "}")
+}
+
+// describeDataType describes dt, as written in ast, with each type, enum,
+// and union it refers to described in full
+func (f *fingerprinter) describeDataType(ast *AST, dt *DataType) {
+	switch {
+	case dt.FixedSizeSlice != nil:
+		fmt.Fprintf(f.w, "[%d]", dt.FixedSizeSlice.Size)
+		f.describeDataType(ast, dt.FixedSizeSlice.Type)
+
+	case dt.Slice != nil:
+		fmt.Fprint(f.w, "[]")
+		f.describeDataType(ast, dt.Slice.Type)
+
+	case dt.Map != nil:
+		fmt.Fprint(f.w, "map<")
+		f.describeName(ast, dt.Map.Key)
+		fmt.Fprint(f.w, ", ")
+		f.describeDataType(ast, dt.Map.Value)
+		fmt.Fprint(f.w, ">")
+
+	default:
+		f.describeName(ast, dt.Scalar.Type)
+	}
+}
+
+// describeName describes the scalar, type, enum, or union called name in
+// ast, resolving names qualified by an import against the imported
+// package
+func (f *fingerprinter) describeName(ast *AST, name string) {
+	if _, ok := Scalars[name]; ok || ast == nil {
+		fmt.Fprint(f.w, name)
+
+		return
+	}
+
+	if alias, local, ok := strings.Cut(name, "."); ok {
+		if imported, ok := ast.Imports[alias]; ok {
+			f.describeName(imported, local)
+
+			return
+		}
+	}
+
+	for i := range ast.Types {
+		if ast.Types[i].Name == name {
+			f.describeDefinition(&ast.Types[i], func() { f.describeType(ast, &ast.Types[i]) })
+
+			return
+		}
+	}
+
+	for _, e := range ast.Enums {
+		if e.Name == name {
+			fmt.Fprintf(f.w, "enum %s {", e.Name)
+
+			for _, v := range e.Values {
+				fmt.Fprintf(f.w, " %s", v.Value.Key)
+			}
+
+			fmt.Fprint(f.w, " }")
+
+			return
+		}
+	}
+
+	for i := range ast.Unions {
+		if ast.Unions[i].Name == name {
+			f.describeDefinition(&ast.Unions[i], func() { f.describeUnion(ast, &ast.Unions[i]) })
+
+			return
+		}
+	}
+
+	// Names are checked before fingerprints are taken, and so this
+	// should never happen
+	fmt.Fprint(f.w, name)
+}
+
+// describeDefinition calls describe to describe d, a type or union,
+// unless d is already being described, in which case it's described by
+// how many levels up it is
+func (f *fingerprinter) describeDefinition(d any, describe func()) {
+	for i := len(f.visiting) - 1; i >= 0; i-- {
+		if f.visiting[i] == d {
+			fmt.Fprintf(f.w, "^%d", len(f.visiting)-i)
+
+			return
+		}
+	}
+
+	describe()
+}
+
+// describeUnion describes u, a union in ast
+func (f *fingerprinter) describeUnion(ast *AST, u *Union) {
+	f.visiting = append(f.visiting, u)
+	defer func() { f.visiting = f.visiting[:len(f.visiting)-1] }()
+
+	fmt.Fprintf(f.w, "union %s {\n", u.Name)
+
+	variants := make([]*UnionVariant, len(u.Variants))
+	copy(variants, u.Variants)
+
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variants[i].Tag < variants[j].Tag
+	})
+
+	for _, v := range variants {
+		f.describeName(ast, v.Type)
+		fmt.Fprintf(f.w, " %s = %d;\n", v.Name, v.Tag)
+	}
+
+	fmt.Fprint(f.w, "}")
+}
+
 type AnnotatedEntry struct {
 	Field
 
 	DocString       string
 	Validations     []Validation
 	Transformations []Transformation
+
+	// Default is the value this field takes in a newly created
+	// instance of its type, or nil where it has none
+	Default *Value
+
+	// Deprecated fields are encoded as normal, but are marked as
+	// deprecated in generated code, giving DeprecatedReason where set
+	Deprecated       bool
+	DeprecatedReason string
 }
 
 func (ae *AnnotatedEntry) AppendDocString(s string) {
@@ -86,28 +361,13 @@ func (ae *AnnotatedEntry) AppendTransformation(v Transformation) {
 //
 //  1. The specified type starts with a lower case and exists in our base scalars map; or
 //  2. It starts with an upper case and exists as a Type or Enum in our AST
+//
+// For collections, this holds for every element, key, and value, including
+// those of nested collections
 func (ae *AnnotatedEntry) IsValidType(names map[string][]lexer.Position) error {
-	var (
-		pos = ae.DataType.Pos
-
-		t []string
-	)
-
-	switch {
-	case ae.DataType.Scalar != nil:
-		t = []string{ae.DataType.Scalar.Type}
-
-	case ae.DataType.Slice != nil:
-		t = []string{ae.DataType.Slice.Type}
-
-	case ae.DataType.FixedSizeSlice != nil:
-		t = []string{ae.DataType.FixedSizeSlice.Type}
-
-	case ae.DataType.Map != nil:
-		t = []string{ae.DataType.Map.Key, ae.DataType.Map.Value}
-
-	default:
-		return fmt.Errorf("Unable to determine type at %s", pos.String())
+	t := ae.DataType.Types()
+	if len(t) == 0 {
+		return fmt.Errorf("Unable to determine type at %s", ae.DataType.Pos.String())
 	}
 
 	for _, s := range t {
@@ -123,9 +383,26 @@ func (ae *AnnotatedEntry) IsValidType(names map[string][]lexer.Position) error {
 	return nil
 }
 
+// IsValidType returns an error unless the type of this variant is
+// either a base scalar, or a Type, Enum, or Union in our AST
+func (v UnionVariant) IsValidType(names map[string][]lexer.Position) error {
+	if !scalarOrNames(v.Type, names) {
+		return incorrectTypeErr{
+			t:   v.Type,
+			pos: v.Pos,
+		}
+	}
+
+	return nil
+}
+
 type Validation struct {
 	IsCustom bool
 	Function string
+
+	// Args are passed to Function after the field name and value,
+	// and are either literals or the names of consts
+	Args []*Value
 }
 
 type Transformation struct {
@@ -143,7 +420,7 @@ func (e incorrectTypeErr) Error() string {
 }
 
 func scalarOrNames(s string, names map[string][]lexer.Position) bool {
-	if unicode.IsLower(rune(s[0])) {
+	if unicode.IsLower(rune(s[0])) && !strings.Contains(s, ".") {
 		_, ok := Scalars[s]
 		return ok
 	}
@@ -1,6 +1,10 @@
 package parser
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/alecthomas/participle/v2/lexer"
@@ -18,3 +22,250 @@ func TestAnnotatedEntry_IsValidType(t *testing.T) {
 		t.Errorf("expected error, got none")
 	}
 }
+
+func TestAnnotatedType_ByTag(t *testing.T) {
+	at := AnnotatedType{
+		Entries: []AnnotatedEntry{
+			{Field: Field{Name: "C", Tag: 2}},
+			{Field: Field{Name: "A", Tag: 0}},
+			{Field: Field{Name: "B", Tag: 1}},
+		},
+	}
+
+	received := make([]string, 0)
+	for _, e := range at.ByTag() {
+		received = append(received, e.Name)
+	}
+
+	if strings.Join(received, ",") != "A,B,C" {
+		t.Errorf("expected A,B,C, received %v", received)
+	}
+
+	// Declaration order is left alone
+	if at.Entries[0].Name != "C" {
+		t.Errorf("expected entries to be left as declared, received %v", at.Entries)
+	}
+}
+
+func TestAnnotatedType_fingerprint(t *testing.T) {
+	base := `
+const Size int32 = 4;
+
+type Foo {
+  string Name = 0;
+  optional int32 Age = 1;
+  [Size]map<string, []float32> Scores = 2;
+}
+`
+
+	for _, test := range []struct {
+		name   string
+		doc    string
+		expect bool
+	}{
+		{"identical documents", base, true},
+		{"fields declared in a different order", `
+const Size int32 = 4;
+
+type Foo {
+  optional int32 Age = 1;
+  [Size]map<string, []float32> Scores = 2;
+  string Name = 0;
+}
+`, true},
+		{"defaults, annotations, and reservations", `
+const Size int32 = 4;
+
+type Foo {
+  reserved 3, "Old";
+
+  +mint:validate:not_empty
+  string Name = 0 [default = "Jo"];
+  optional int32 Age = 1;
+
+  // Scores, with a doc string
+  [Size]map<string, []float32> Scores = 2;
+}
+`, true},
+		{"array size given as a literal", `
+type Foo {
+  string Name = 0;
+  optional int32 Age = 1;
+  [4]map<string, []float32> Scores = 2;
+}
+`, true},
+		{"renamed type", strings.Replace(base, "Foo", "Bar", 1), false},
+		{"renamed field", strings.Replace(base, "Name", "Nickname", 1), false},
+		{"renumbered field", strings.Replace(base, "Age = 1", "Age = 3", 1), false},
+		{"retyped field", strings.Replace(base, "int32 Age", "int64 Age", 1), false},
+		{"retyped element", strings.Replace(base, "[]float32", "[]float64", 1), false},
+		{"resized array", strings.Replace(base, "Size int32 = 4", "Size int32 = 5", 1), false},
+		{"field no longer optional", strings.Replace(base, "optional ", "", 1), false},
+		{"added field", strings.Replace(base, "  string Name = 0;", "  string Name = 0;\n  bool Admin = 3;", 1), false},
+		{"evolvable type", strings.Replace(base, "Foo {", "Foo [evolvable] {", 1), false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			a, err := Parse("base", strings.NewReader(base))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := Parse(test.name, strings.NewReader(test.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			received := a.Types[0].Fingerprint == b.Types[0].Fingerprint
+			if test.expect != received {
+				t.Errorf("expected fingerprints %#x and %#x to match: %v", a.Types[0].Fingerprint, b.Types[0].Fingerprint, test.expect)
+			}
+		})
+	}
+}
+
+func TestAnnotatedType_fingerprint_References(t *testing.T) {
+	base := `
+enum Kind {
+  A
+  B
+}
+
+union Either {
+  In Left = 0;
+  string Right = 1;
+}
+
+type In {
+  string Name = 0;
+}
+
+type Out {
+  In In = 0;
+  []Kind Kinds = 1;
+  Either Either = 2;
+  map<Kind, In> ByKind = 3;
+  []Out Children = 4;
+}
+
+type Unrelated {
+  string Name = 0;
+}
+`
+
+	for _, test := range []struct {
+		name   string
+		doc    string
+		expect bool
+	}{
+		{"identical documents", base, true},
+		{"unrelated type changed", strings.Replace(base, "type Unrelated {", "type Unrelated {\n  bool Extra = 1;", 1), true},
+		{"union variants declared in a different order", strings.Replace(base, "  In Left = 0;\n  string Right = 1;", "  string Right = 1;\n  In Left = 0;", 1), true},
+		{"nested type gains a field", strings.Replace(base, "type In {", "type In {\n  int32 Age = 1;", 1), false},
+		{"nested field retyped", strings.Replace(base, "string Name = 0;\n}\n\ntype Out", "int64 Name = 0;\n}\n\ntype Out", 1), false},
+		{"enum gains a value", strings.Replace(base, "  A\n  B\n", "  A\n  B\n  C\n", 1), false},
+		{"enum values reordered", strings.Replace(base, "  A\n  B\n", "  B\n  A\n", 1), false},
+		{"union gains a variant", strings.Replace(base, "  string Right = 1;", "  string Right = 1;\n  int32 Middle = 2;", 1), false},
+		{"union variant retyped", strings.Replace(base, "string Right = 1;", "bool Right = 1;", 1), false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			a, err := Parse("base", strings.NewReader(base))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := Parse(test.name, strings.NewReader(test.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fa, fb := typeFingerprint(t, a, "Out"), typeFingerprint(t, b, "Out")
+
+			received := fa == fb
+			if test.expect != received {
+				t.Errorf("expected fingerprints %#x and %#x to match: %v", fa, fb, test.expect)
+			}
+		})
+	}
+}
+
+func TestAnnotatedType_fingerprint_Imports(t *testing.T) {
+	geo := `package geo;
+
+type Coordinates {
+  float64 Latitude = 0;
+  float64 Longitude = 1;
+}
+`
+
+	report := `package weather;
+
+import %s"../geo";
+
+type Report {
+  %s.Coordinates Where = 0;
+}
+`
+
+	fingerprint := func(t *testing.T, geo, alias string) uint64 {
+		t.Helper()
+
+		dir := t.TempDir()
+
+		qualifier := alias
+		if alias == "" {
+			qualifier = "geo"
+		} else {
+			alias += " "
+		}
+
+		for fn, contents := range map[string]string{
+			"geo/geo.mint":         geo,
+			"weather/weather.mint": fmt.Sprintf(report, alias, qualifier),
+		} {
+			fn = filepath.Join(dir, fn)
+
+			err := os.MkdirAll(filepath.Dir(fn), 0700)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = os.WriteFile(fn, []byte(contents), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		a, err := ParseDir(filepath.Join(dir, "weather"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return typeFingerprint(t, a, "Report")
+	}
+
+	base := fingerprint(t, geo, "")
+
+	if received := fingerprint(t, geo, "g"); received != base {
+		t.Errorf("expected renaming an import to keep fingerprint %#x, received %#x", base, received)
+	}
+
+	changed := strings.Replace(geo, "float64 Longitude", "float32 Longitude", 1)
+	if received := fingerprint(t, changed, ""); received == base {
+		t.Errorf("expected changing an imported type to change fingerprint %#x", base)
+	}
+}
+
+// typeFingerprint returns the fingerprint of the type called name in a
+func typeFingerprint(t *testing.T, a *AST, name string) uint64 {
+	t.Helper()
+
+	for _, at := range a.Types {
+		if at.Name == name {
+			return at.Fingerprint
+		}
+	}
+
+	t.Fatalf("no type %s", name)
+
+	return 0
+}
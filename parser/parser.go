@@ -2,10 +2,12 @@
 package parser
 
 import (
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/alecthomas/participle/v2"
@@ -19,14 +21,63 @@ const (
 type Document struct {
 	Pos lexer.Position
 
-	Entries []*Entry `( @@ ";"* )*`
+	Package *Package  `( @@ ";"* )?`
+	Imports []*Import `( @@ ";"* )*`
+	Entries []*Entry  `( @@ ";"* )*`
+}
+
+// Package names the package a document belongs to, such as geo, which
+// other documents use to refer to its types, such as geo.Location
+type Package struct {
+	Pos lexer.Position
+
+	Name    string           `"package" @Ident`
+	Options []*PackageOption `( "[" @@ ( "," @@ )* "]" )?`
+}
+
+// PackageOption is an option set on a package, such as
+// [go_package = "github.com/example/geo"]
+type PackageOption struct {
+	Pos lexer.Position
+
+	GoPackage *string `"go_package" "=" @String`
+}
+
+// Import makes the package defined by the documents in the directory
+// Path, relative to the importing document, available to that document
+// as Alias, or by that package's own name where Alias isn't set
+type Import struct {
+	Pos lexer.Position
+
+	Alias string `"import" @Ident?`
+	Path  string `@String`
 }
 
 type Entry struct {
 	Pos lexer.Position
 
-	Type *Type ` @@`
-	Enum *Enum `| @@`
+	Type  *Type  ` @@`
+	Enum  *Enum  `| @@`
+	Union *Union `| @@`
+	Const *Const `| @@`
+}
+
+// Const is a named value which may be used in place of a literal, such
+// as the size of an array, or an argument to a validator
+type Const struct {
+	Pos lexer.Position
+
+	Name  string `"const" @Ident`
+	Type  string `@Ident`
+	Value *Value `"=" @@`
+}
+
+func (c Const) name() string {
+	return c.Name
+}
+
+func (c Const) pos() lexer.Position {
+	return c.Pos
 }
 
 type Enum struct {
@@ -56,38 +107,138 @@ type EnumValue struct {
 	Key string `@Ident`
 }
 
+// Union is a value which holds exactly one of a set of variants, each
+// of which is identified on the wire by its tag
+type Union struct {
+	Pos lexer.Position
+
+	Name     string          `"union" @Ident`
+	Variants []*UnionVariant `"{" ( @@ ";"* )* "}"`
+}
+
+func (u Union) name() string {
+	return u.Name
+}
+
+func (u Union) pos() lexer.Position {
+	return u.Pos
+}
+
+type UnionVariant struct {
+	Pos lexer.Position
+
+	Type string `@( Ident ( "." Ident )? )`
+	Name string `@Ident`
+	Tag  int    `"=" @Int`
+}
+
 type Type struct {
 	Pos lexer.Position
 
 	Name    string          `"type" @Ident`
+	Options []*TypeOption   `( "[" @@ ( "," @@ )* "]" )?`
 	Entries []*MessageEntry `"{" @@* "}"`
 }
 
+// TypeOption is an option set on a type after its name, such as
+// [evolvable]
+type TypeOption struct {
+	Pos lexer.Position
+
+	Evolvable bool `@"evolvable"`
+}
+
 type MessageEntry struct {
 	Pos lexer.Position
 
-	Annotation *Annotation `@@`
+	Reserved   *Reserved   `  @@ ";"*`
+	Annotation *Annotation `| @@`
 	Field      *Field      `| @@  ";"*`
 }
 
+// Reserved lists the tags, and names, of fields which have been removed
+// from a type, and which may therefore never be used again, such as
+// reserved 3, 5, "Nickname";
+type Reserved struct {
+	Pos lexer.Position
+
+	Values []*ReservedValue `"reserved" @@ ( "," @@ )*`
+}
+
+// ReservedValue is either a tag or a name in a reserved statement
+type ReservedValue struct {
+	Pos lexer.Position
+
+	Tag  *int    `  @Int`
+	Name *string `| @String`
+}
+
+// Annotation annotates the field which follows it, such as
+// +mint:validate:string_not_empty, or +mint:deprecated, which takes
+// no value
 type Annotation struct {
 	Pos lexer.Position
 
 	Provider string `"+" @Ident`
-	Type     string `":" @Ident ":"`
+	Type     string `":" @Ident`
 
-	Func  string `( @Ident`
-	Value string `| @String )`
+	HasValue bool     `( @":"`
+	Func     string   `  ( @Ident`
+	Args     []*Value `    ( "(" ( @@ ( "," @@ )* )? ")" )?`
+	Value    string   `  | @String`
+	Number   string   `  | @( "-"? ( Float | Int ) ) ) )?`
 }
 
 type Field struct {
 	Pos lexer.Position
 
-	DataType *DataType `@@`
-	Name     string    `@Ident`
-	Tag      int       `"=" @Int`
+	Optional bool           `@"optional"?`
+	DataType *DataType      `@@`
+	Name     string         `@Ident`
+	Tag      int            `"=" @Int`
+	Options  []*FieldOption `( "[" @@ ( "," @@ )* "]" )?`
+}
+
+// FieldOption is an option set on a field after its tag, such as
+// [default = "eu-west-1"]
+type FieldOption struct {
+	Pos lexer.Position
+
+	Default *Value `"default" "=" @@`
 }
 
+// Value is a literal in a document, such as the default value of a field;
+// either a quoted string, a number, or an identifier such as true, false,
+// or the value of an enum
+type Value struct {
+	Pos lexer.Position
+
+	Quoted *string `  @String`
+	Number *string `| @( "-"? ( Float | Int ) )`
+	Ident  *string `| @Ident`
+}
+
+// String returns v as it was written in a document
+func (v Value) String() string {
+	switch {
+	case v.Quoted != nil:
+		return strconv.Quote(*v.Quoted)
+
+	case v.Number != nil:
+		return *v.Number
+
+	case v.Ident != nil:
+		return *v.Ident
+	}
+
+	return ""
+}
+
+// DataType is the type of a field, or of an element of a collection.
+//
+// Slices, arrays, and the values of maps may themselves be collections,
+// allowing for types such as [][]float64 or map<string, []string>; map
+// keys must be comparable, and so may not
 type DataType struct {
 	Pos lexer.Position
 
@@ -97,30 +248,113 @@ type DataType struct {
 	Scalar         *Scalar              `| @@`
 }
 
+// Types returns the names of each scalar, type, enum, or union which
+// makes up dt, including those of any nested collections
+func (dt *DataType) Types() []string {
+	switch {
+	case dt == nil:
+		return nil
+
+	case dt.Scalar != nil:
+		return []string{dt.Scalar.Type}
+
+	case dt.Map != nil:
+		return append([]string{dt.Map.Key}, dt.Map.Value.Types()...)
+	}
+
+	if elem := dt.Elem(); elem != nil {
+		return elem.Types()
+	}
+
+	return nil
+}
+
+// Elem returns the type of the elements of a slice or array, or of the
+// values of a map, or nil where dt isn't a collection
+func (dt *DataType) Elem() *DataType {
+	switch {
+	case dt == nil:
+		return nil
+
+	case dt.Slice != nil:
+		return dt.Slice.Type
+
+	case dt.FixedSizeSlice != nil:
+		return dt.FixedSizeSlice.Type
+
+	case dt.Map != nil:
+		return dt.Map.Value
+	}
+
+	return nil
+}
+
+// IsCollection returns true where dt is a slice, array, or map
+func (dt *DataType) IsCollection() bool {
+	return dt.Elem() != nil
+}
+
+// IsNested returns true where dt is a collection of collections
+func (dt *DataType) IsNested() bool {
+	elem := dt.Elem()
+
+	return elem != nil && elem.IsCollection()
+}
+
+// String returns dt as it would be written in a document, with the
+// size of any array which refers to a const resolved, such as [5]int16
+func (dt *DataType) String() string {
+	switch {
+	case dt == nil:
+		return ""
+
+	case dt.Scalar != nil:
+		return dt.Scalar.Type
+
+	case dt.Slice != nil:
+		return "[]" + dt.Slice.Type.String()
+
+	case dt.FixedSizeSlice != nil:
+		return fmt.Sprintf("[%d]%s", dt.FixedSizeSlice.Size, dt.FixedSizeSlice.Type)
+
+	case dt.Map != nil:
+		return fmt.Sprintf("map<%s, %s>", dt.Map.Key, dt.Map.Value)
+	}
+
+	return ""
+}
+
 type MapType struct {
 	Pos lexer.Position
 
-	Key   string `"map" "<" @Ident`
-	Value string `"," @Ident ">"`
+	Key   string    `"map" "<" @( Ident ( "." Ident )? )`
+	Value *DataType `"," @@ ">"`
 }
 
 type SliceType struct {
 	Pos lexer.Position
 
-	Type string `"[" "]" @Ident`
+	Type *DataType `"[" "]" @@`
 }
 
+// FixedSizedSliceType is an array, the size of which is either an
+// integer, or the name of a const; in the latter case SizeRef holds
+// that name, and Size is set by the solver
 type FixedSizedSliceType struct {
 	Pos lexer.Position
 
-	Size int    `"[" @Int "]"`
-	Type string `@Ident`
+	Size    int       `"[" ( @Int`
+	SizeRef string    `    | @Ident ) "]"`
+	Type    *DataType `@@`
 }
 
+// Scalar is a builtin scalar, or a user defined type, enum, or union,
+// the name of which may be qualified by the package it belongs to, such
+// as geo.Location
 type Scalar struct {
 	Pos lexer.Position
 
-	Type string `@Ident`
+	Type string `@( Ident ( "." Ident )? )`
 }
 
 var p = participle.MustBuild[Document](participle.UseLookahead(2), participle.Elide("Comment"), participle.Unquote())
@@ -131,7 +365,7 @@ func parse(fn string, in io.Reader) (*AST, error) {
 		return nil, err
 	}
 
-	return toAST(*d)
+	return toAST(fn, *d)
 }
 
 func Parse(fn string, in io.Reader) (*AST, error) {
@@ -142,7 +376,7 @@ func Parse(fn string, in io.Reader) (*AST, error) {
 
 	// Because the solver does some magic around uniqueness
 	// we need to call it here for a len 1 slice
-	return merge([]*AST{a})
+	return merge([]*AST{a}, make(map[string]bool))
 }
 
 func ParseFile(fn string) (*AST, error) {
@@ -158,10 +392,27 @@ func ParseFile(fn string) (*AST, error) {
 	return Parse(fn, f)
 }
 
+// ParseDir parses each document in dir, and its subdirectories, into a
+// single package, along with any packages those documents import
 func ParseDir(dir string) (*AST, error) {
+	return parseDir(dir, make(map[string]bool))
+}
+
+// parseDir parses the documents in dir; importing holds the absolute
+// path of each directory currently being parsed, so that import cycles
+// can be found
+func parseDir(dir string, importing map[string]bool) (*AST, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	importing[abs] = true
+	defer delete(importing, abs)
+
 	asts := make([]*AST, 0)
 
-	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -195,5 +446,5 @@ func ParseDir(dir string) (*AST, error) {
 		return nil, err
 	}
 
-	return merge(asts)
+	return merge(asts, importing)
 }
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
@@ -22,11 +23,42 @@ func TestParse(t *testing.T) {
 		{"colliding type names error", collidingNames, nil, true},
 		{"colliding field names error", collidingFieldNames, nil, true},
 		{"colliding field tags error", collidingTags, nil, true},
-		{"missing field tags error", missingFields, nil, true},
+		{"reused reserved tags error", reusedReservedTag, nil, true},
+		{"reused reserved names error", reusedReservedName, nil, true},
+		{"duplicate reserved tags error", duplicateReservedTag, nil, true},
+		{"annotations without values error", annotationWithoutValue, nil, true},
+		{"deprecations with non-string reasons error", invalidDeprecation, nil, true},
 		{"valid input works", validInput, fullAST, false},
 		{"invalid scalar type errors", invalidScalar, nil, true},
 		{"invalid map key type errors", invalidMapKey, nil, true},
 		{"invalid map value type errors", invalidMapValue, nil, true},
+		{"optional collections error", optionalCollection, nil, true},
+		{"too many optional fields error", tooManyOptionals(), nil, true},
+		{"empty unions error", emptyUnion, nil, true},
+		{"out of range union tags error", outOfRangeUnionTag, nil, true},
+		{"colliding variant names error", collidingVariantNames, nil, true},
+		{"colliding variant tags error", collidingVariantTags, nil, true},
+		{"invalid variant type errors", invalidVariantType, nil, true},
+		{"colliding union and type names error", collidingUnionName, nil, true},
+		{"invalid nested element type errors", invalidNestedElement, nil, true},
+		{"invalid nested map value type errors", invalidNestedMapValue, nil, true},
+		{"collection map keys error", collectionMapKey, nil, true},
+		{"defaults of the wrong kind error", invalidDefaultKind, nil, true},
+		{"out of range defaults error", outOfRangeDefault, nil, true},
+		{"invalid datetime defaults error", invalidDefaultDatetime, nil, true},
+		{"duplicate defaults error", duplicateDefault, nil, true},
+		{"collection defaults error", collectionDefault, nil, true},
+		{"optional defaults error", optionalDefault, nil, true},
+		{"user type defaults error", userTypeDefault, nil, true},
+		{"unknown enum defaults error", unknownEnumDefault, nil, true},
+		{"invalid const values error", invalidConstValue, nil, true},
+		{"datetime consts error", datetimeConst, nil, true},
+		{"consts used as types error", constAsType, nil, true},
+		{"colliding const and type names error", collidingConstName, nil, true},
+		{"unknown array size consts error", unknownSizeConst, nil, true},
+		{"float array size consts error", floatSizeConst, nil, true},
+		{"unknown validation argument consts error", unknownArgConst, nil, true},
+		{"transformation arguments error", transformationArgs, nil, true},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			received, err := Parse(test.name, strings.NewReader(test.body))
@@ -53,6 +85,244 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParse_Optional(t *testing.T) {
+	received, err := Parse("optional fields", strings.NewReader(optionalFields))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expect := map[string]bool{
+		"Bar":      false,
+		"Nickname": true,
+		"Baz":      true,
+	}
+
+	for _, e := range received.Types[0].Entries {
+		if expect[e.Name] != e.Optional {
+			t.Errorf("%s: expected optional %v, received %v", e.Name, expect[e.Name], e.Optional)
+		}
+	}
+}
+
+func TestParse_Nested(t *testing.T) {
+	received, err := Parse("nested collections", strings.NewReader(nestedCollections))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	for _, test := range []struct {
+		name         string
+		expectTypes  []string
+		expectNested bool
+	}{
+		{"Matrix", []string{"float64"}, true},
+		{"Grid", []string{"int32"}, true},
+		{"Tags", []string{"string", "string"}, true},
+		{"Neighbours", []string{"Bar"}, true},
+		{"Lookup", []string{"uuid", "uuid", "Bar"}, true},
+		{"Flat", []string{"string"}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var e *AnnotatedEntry
+			for i := range received.Types[0].Entries {
+				if received.Types[0].Entries[i].Name == test.name {
+					e = &received.Types[0].Entries[i]
+				}
+			}
+
+			if e == nil {
+				t.Fatalf("field %s not found", test.name)
+			}
+
+			if !reflect.DeepEqual(test.expectTypes, e.DataType.Types()) {
+				t.Errorf("expected %#v, received %#v", test.expectTypes, e.DataType.Types())
+			}
+
+			if test.expectNested != e.DataType.IsNested() {
+				t.Errorf("expected nested %v, received %v", test.expectNested, e.DataType.IsNested())
+			}
+		})
+	}
+}
+
+func TestParse_Default(t *testing.T) {
+	received, err := Parse("default values", strings.NewReader(defaultValues))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expect := map[string]string{
+		"Region":    `"eu-west-1"`,
+		"Owner":     `"anonymous"`,
+		"Offset":    "-12",
+		"Ratio":     "0.5",
+		"Enabled":   "true",
+		"Epoch":     `"2000-01-01T00:00:00Z"`,
+		"Namespace": `"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`,
+		"Level":     "High",
+	}
+
+	for _, e := range received.Types[0].Entries {
+		var receivedDefault string
+		if e.Default != nil {
+			receivedDefault = e.Default.String()
+		}
+
+		if expect[e.Name] != receivedDefault {
+			t.Errorf("%s: expected default %q, received %q", e.Name, expect[e.Name], receivedDefault)
+		}
+	}
+}
+
+func TestParse_Evolvable(t *testing.T) {
+	received, err := Parse("evolvable", strings.NewReader(evolvableTypes))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		expect bool
+	}{
+		{"Fixed", false},
+		{"Growing", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for _, at := range received.Types {
+				if at.Name == test.name && at.Evolvable != test.expect {
+					t.Errorf("expected %v, received %v", test.expect, at.Evolvable)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Reserved(t *testing.T) {
+	received, err := Parse("reserved", strings.NewReader(reservedFields))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	at := received.Types[0]
+
+	if !reflect.DeepEqual([]int{1, 3, 4}, at.ReservedTags) {
+		t.Errorf("expected reserved tags [1 3 4], received %v", at.ReservedTags)
+	}
+
+	if !reflect.DeepEqual([]string{"Nickname"}, at.ReservedNames) {
+		t.Errorf("expected reserved names [Nickname], received %v", at.ReservedNames)
+	}
+
+	for _, test := range []struct {
+		field           string
+		expect          bool
+		expectReason    string
+		expectDocString string
+	}{
+		{"Bar", false, "", ""},
+		{"Baz", true, "", ""},
+		{"Qux", true, "use Bar instead", "Qux is a thing"},
+	} {
+		t.Run(test.field, func(t *testing.T) {
+			for _, e := range at.Entries {
+				if e.Name != test.field {
+					continue
+				}
+
+				if test.expect != e.Deprecated || test.expectReason != e.DeprecatedReason {
+					t.Errorf("expected %v (%q), received %v (%q)", test.expect, test.expectReason, e.Deprecated, e.DeprecatedReason)
+				}
+
+				if test.expectDocString != e.DocString {
+					t.Errorf("expected doc string %q, received %q", test.expectDocString, e.DocString)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_Const(t *testing.T) {
+	received, err := Parse("consts", strings.NewReader(validConsts))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if len(received.Consts) != 3 {
+		t.Fatalf("expected 3 consts, received %d", len(received.Consts))
+	}
+
+	entries := received.Types[0].Entries
+
+	t.Run("array sizes", func(t *testing.T) {
+		for _, test := range []struct {
+			dt            *DataType
+			expectSize    int
+			expectSizeRef string
+		}{
+			{entries[0].DataType, 16, "MaxTags"},
+			{entries[1].DataType, 3, "Grid"},
+			{entries[1].DataType.Elem(), 3, "Grid"},
+			{entries[2].DataType, 4, ""},
+		} {
+			fs := test.dt.FixedSizeSlice
+			if test.expectSize != fs.Size || test.expectSizeRef != fs.SizeRef {
+				t.Errorf("expected [%d] (%q), received [%d] (%q)", test.expectSize, test.expectSizeRef, fs.Size, fs.SizeRef)
+			}
+		}
+	})
+
+	t.Run("validation arguments", func(t *testing.T) {
+		expect := [][]string{
+			{"MaxTags"},
+			{"-1.5", "2", `"m"`, "true"},
+		}
+
+		for i, v := range entries[0].Validations {
+			received := make([]string, len(v.Args))
+			for j, a := range v.Args {
+				received[j] = a.String()
+			}
+
+			if !reflect.DeepEqual(expect[i], received) {
+				t.Errorf("expected %#v, received %#v", expect[i], received)
+			}
+		}
+	})
+}
+
+func TestParse_Union(t *testing.T) {
+	received, err := Parse("unions", strings.NewReader(validUnion))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if len(received.Unions) != 1 {
+		t.Fatalf("expected 1 union, received %d", len(received.Unions))
+	}
+
+	u := received.Unions[0]
+	if u.Name != "Event" {
+		t.Errorf("expected Event, received %s", u.Name)
+	}
+
+	expect := []UnionVariant{
+		{Type: "Created", Name: "Created", Tag: 0},
+		{Type: "string", Name: "Message", Tag: 1},
+		{Type: "int64", Name: "Count", Tag: 5},
+	}
+
+	if len(expect) != len(u.Variants) {
+		t.Fatalf("expected %d variants, received %d", len(expect), len(u.Variants))
+	}
+
+	for i, v := range u.Variants {
+		v.Pos = expect[i].Pos
+		if expect[i] != *v {
+			t.Errorf("expected %#v, received %#v", expect[i], *v)
+		}
+	}
+}
+
 func TestParseFile(t *testing.T) {
 	for _, test := range []struct {
 		fn        string
@@ -80,6 +350,10 @@ func TestParseDir(t *testing.T) {
 		{"testdata/valid-documents", false},
 		{"testdata/nonsuch", true},
 		{"testdata/invalid-document", true},
+		{"testdata/packages/weather", false},
+		{"testdata/packages/cycle-a", true},
+		{"testdata/packages/mixed", true},
+		{"testdata/packages/unnamed-import", true},
 	} {
 		t.Run(test.dir, func(t *testing.T) {
 			_, err := ParseDir(test.dir)
@@ -92,6 +366,70 @@ func TestParseDir(t *testing.T) {
 	}
 }
 
+func TestParseDir_Imports(t *testing.T) {
+	received, err := ParseDir("testdata/packages/weather")
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if received.Package != "weather" || received.GoPackage != "example.com/packages/weather" {
+		t.Errorf("unexpected package %q (%q)", received.Package, received.GoPackage)
+	}
+
+	geo, ok := received.Imports["geo"]
+	if !ok {
+		t.Fatalf("expected geo to be imported, received %#v", received.Imports)
+	}
+
+	if geo.GoPackage != "example.com/packages/geo" || len(geo.Types) != 1 || len(geo.Enums) != 1 {
+		t.Errorf("unexpected import %#v", geo)
+	}
+
+	expect := [][]string{
+		{"geo.Coordinates"},
+		{"geo.Terrain"},
+		{"geo.Coordinates"},
+		{"geo.Terrain", "string"},
+		{"geo.Coordinates"},
+	}
+
+	for i, e := range received.Types[0].Entries {
+		if !reflect.DeepEqual(expect[i], e.DataType.Types()) {
+			t.Errorf("%s: expected %#v, received %#v", e.Name, expect[i], e.DataType.Types())
+		}
+	}
+}
+
+func TestParse_Imports(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		body      string
+		expectErr bool
+	}{
+		{"aliased imports work", aliasedImport, false},
+		{"unimported packages error", unimportedPackage, true},
+		{"unknown imported types error", unknownImportedType, true},
+		{"reused aliases error", reusedAlias, true},
+		{"missing imports error", missingImport, true},
+		{"conflicting go packages error", conflictingGoPackage, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// Imports are relative to the document, and so the
+			// filename matters here
+			_, err := Parse("testdata/packages/test.mint", strings.NewReader(test.body))
+			if err != nil {
+				t.Log(err)
+			}
+
+			if err == nil && test.expectErr {
+				t.Errorf("expected error, received none")
+			} else if err != nil && !test.expectErr {
+				t.Errorf("unexpected error %s", err)
+			}
+		})
+	}
+}
+
 var (
 	emptyAST = new(AST)
 	fullAST  = &AST{
@@ -201,8 +539,24 @@ var (
 										Line:     13,
 										Column:   3,
 									},
-									Key:   "string",
-									Value: "int32",
+									Key: "string",
+									Value: &DataType{
+										Pos: lexer.Position{
+											Filename: "valid input works",
+											Offset:   256,
+											Line:     13,
+											Column:   15,
+										},
+										Scalar: &Scalar{
+											Pos: lexer.Position{
+												Filename: "valid input works",
+												Offset:   256,
+												Line:     13,
+												Column:   15,
+											},
+											Type: "int32",
+										},
+									},
 								},
 							},
 							Name: "MappyMap",
@@ -231,7 +585,23 @@ var (
 										Line:     14,
 										Column:   3,
 									},
-									Type: "string",
+									Type: &DataType{
+										Pos: lexer.Position{
+											Filename: "valid input works",
+											Offset:   281,
+											Line:     14,
+											Column:   5,
+										},
+										Scalar: &Scalar{
+											Pos: lexer.Position{
+												Filename: "valid input works",
+												Offset:   281,
+												Line:     14,
+												Column:   5,
+											},
+											Type: "string",
+										},
+									},
 								},
 							},
 							Name: "UnboundString",
@@ -261,7 +631,23 @@ var (
 										Column:   3,
 									},
 									Size: 5,
-									Type: "byte",
+									Type: &DataType{
+										Pos: lexer.Position{
+											Filename: "valid input works",
+											Offset:   312,
+											Line:     15,
+											Column:   6,
+										},
+										Scalar: &Scalar{
+											Pos: lexer.Position{
+												Filename: "valid input works",
+												Offset:   312,
+												Line:     15,
+												Column:   6,
+											},
+											Type: "byte",
+										},
+									},
 								},
 							},
 							Name: "FixedSizeByteSlice",
@@ -269,6 +655,7 @@ var (
 						},
 					},
 				},
+				Fingerprint: 0x7f20fe7bb75df25c,
 			},
 		},
 		Enums: []Enum{
@@ -354,10 +741,59 @@ type Foo {
   string Baz = 0;
 }
 `
-	missingFields = `
+	reservedFields = `
 type Foo {
+  reserved 1, 3, "Nickname";
+  reserved 4;
+
   string Bar = 0;
+
+  +mint:deprecated
   string Baz = 2;
+
+  +mint:doc:"Qux is a thing"
+  +mint:deprecated:"use Bar instead"
+  optional string Qux = 5;
+}
+`
+
+	reusedReservedTag = `
+type Foo {
+  reserved 1;
+
+  string Bar = 0;
+  string Baz = 1;
+}
+`
+
+	reusedReservedName = `
+type Foo {
+  reserved "Baz";
+
+  string Bar = 0;
+  string Baz = 1;
+}
+`
+
+	duplicateReservedTag = `
+type Foo {
+  reserved 1, 1;
+
+  string Bar = 0;
+}
+`
+
+	annotationWithoutValue = `
+type Foo {
+  +mint:doc
+  string Bar = 0;
+}
+`
+
+	invalidDeprecation = `
+type Foo {
+  +mint:deprecated:12
+  string Bar = 0;
 }
 `
 	validInput = `
@@ -399,5 +835,322 @@ type Foo {
 type Foo {
   map<string, Bar> BarMap = 0;
 }
+`
+	optionalFields = `
+type Foo {
+  string Bar = 0;
+  optional string Nickname = 1;
+  optional Baz Baz = 2;
+}
+
+type Baz {
+  string Qux = 0;
+}
+`
+	optionalCollection = `
+type Foo {
+  optional []string Bar = 0;
+}
+`
+	validUnion = `
+type Created {
+  string Name = 0;
+}
+
+union Event {
+  Created Created = 0;
+  string Message = 1;
+  int64 Count = 5;
+}
+`
+	emptyUnion = `
+union Event {}
+`
+	outOfRangeUnionTag = `
+union Event {
+  string Message = 256;
+}
+`
+	collidingVariantNames = `
+union Event {
+  string Message = 0;
+  int64 Message = 1;
+}
+`
+	collidingVariantTags = `
+union Event {
+  string Message = 0;
+  int64 Count = 0;
+}
+`
+	invalidVariantType = `
+union Event {
+  Nonsuch Message = 0;
+}
+`
+	collidingUnionName = `
+type Event {
+  string Message = 0;
+}
+
+union Event {
+  string Message = 0;
+}
 `
 )
+
+const (
+	nestedCollections = `
+type Foo {
+  [][]float64 Matrix = 0;
+  [3][3]int32 Grid = 1;
+  map<string, []string> Tags = 2;
+  [][4]Bar Neighbours = 3;
+  map<uuid, map<uuid, Bar>> Lookup = 4;
+  []string Flat = 5;
+}
+
+type Bar {
+  string Baz = 0;
+}
+`
+
+	invalidNestedElement = `
+type Foo {
+  [][]Nope Matrix = 0;
+}
+`
+
+	invalidNestedMapValue = `
+type Foo {
+  map<string, []Nope> Tags = 0;
+}
+`
+
+	collectionMapKey = `
+type Foo {
+  map<[]string, int32> Tags = 0;
+}
+`
+)
+
+const (
+	defaultValues = `
+type Foo {
+  string Region = 0 [default = "eu-west-1"];
+
+  +mint:default:"anonymous"
+  string Owner = 1;
+
+  int16 Offset = 2 [default = -12];
+  float32 Ratio = 3 [default = 0.5];
+  bool Enabled = 4 [default = true];
+  datetime Epoch = 5 [default = "2000-01-01T00:00:00Z"];
+  uuid Namespace = 6 [default = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"];
+  Level Level = 7 [default = High];
+  byte Flags = 8;
+}
+
+enum Level {
+  Low
+  High
+}
+`
+
+	evolvableTypes = `
+type Fixed {
+  string Name = 0;
+}
+
+type Growing [evolvable] {
+  string Name = 0;
+  optional int32 Age = 1;
+}
+`
+
+	invalidDefaultKind = `
+type Foo {
+  int32 Count = 0 [default = "ten"];
+}
+`
+
+	outOfRangeDefault = `
+type Foo {
+  int16 Count = 0 [default = 40000];
+}
+`
+
+	invalidDefaultDatetime = `
+type Foo {
+  datetime When = 0 [default = "yesterday"];
+}
+`
+
+	duplicateDefault = `
+type Foo {
+  +mint:default:"a"
+  string Bar = 0 [default = "b"];
+}
+`
+
+	collectionDefault = `
+type Foo {
+  []string Bar = 0 [default = "a"];
+}
+`
+
+	optionalDefault = `
+type Foo {
+  optional string Bar = 0 [default = "a"];
+}
+`
+
+	userTypeDefault = `
+type Foo {
+  Bar Bar = 0 [default = Baz];
+}
+
+type Bar {
+  string Baz = 0;
+}
+`
+
+	unknownEnumDefault = `
+type Foo {
+  Level Level = 0 [default = Extreme];
+}
+
+enum Level {
+  Low
+  High
+}
+`
+)
+
+const (
+	validConsts = `
+const MaxTags int32 = 16;
+const Grid byte = 3;
+const Region string = "eu-west-1";
+
+type Foo {
+  +custom:validate:max_length(MaxTags)
+  +custom:validate:within(-1.5, 2, "m", true)
+  [MaxTags]string Tags = 0;
+
+  [Grid][Grid]float64 Matrix = 1;
+  [4]int32 Flat = 2;
+}
+`
+
+	invalidConstValue = `
+const MaxTags int32 = "sixteen";
+`
+
+	datetimeConst = `
+const Epoch datetime = "2000-01-01T00:00:00Z";
+`
+
+	constAsType = `
+const MaxTags int32 = 16;
+
+type Foo {
+  MaxTags Bar = 0;
+}
+`
+
+	collidingConstName = `
+const Foo int32 = 16;
+
+type Foo {
+  string Bar = 0;
+}
+`
+
+	unknownSizeConst = `
+type Foo {
+  [MaxTags]string Bar = 0;
+}
+`
+
+	floatSizeConst = `
+const MaxTags float32 = 16;
+
+type Foo {
+  [MaxTags]string Bar = 0;
+}
+`
+
+	unknownArgConst = `
+type Foo {
+  +custom:validate:max_length(MaxTags)
+  string Bar = 0;
+}
+`
+
+	transformationArgs = `
+type Foo {
+  +mint:transform:to_lower(1)
+  string Bar = 0;
+}
+`
+)
+
+const (
+	aliasedImport = `
+package test;
+
+import g "geo";
+
+type Foo {
+  g.Coordinates Bar = 0;
+  g.Terrain Baz = 1 [default = Land];
+}
+`
+
+	unimportedPackage = `
+type Foo {
+  geo.Coordinates Bar = 0;
+}
+`
+
+	unknownImportedType = `
+import "geo";
+
+type Foo {
+  geo.Nonsuch Bar = 0;
+}
+`
+
+	reusedAlias = `
+import g "geo";
+import g "weather";
+
+type Foo {
+  g.Coordinates Bar = 0;
+}
+`
+
+	missingImport = `
+import "nonsuch";
+`
+
+	conflictingGoPackage = `
+package test [go_package = "example.com/a", go_package = "example.com/b"];
+`
+)
+
+// tooManyOptionals returns a document containing a type with one more
+// optional field than fits into a presence bitmap
+func tooManyOptionals() string {
+	sb := new(strings.Builder)
+	sb.WriteString("type Foo {\n")
+
+	for i := 0; i <= maxOptionalFields; i++ {
+		fmt.Fprintf(sb, "  optional string Bar%d = %d;\n", i, i)
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
@@ -1,45 +1,312 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/gofrs/uuid/v5"
 )
 
-// missingTagErr describes occasions where a type has a
-// missing tag; all tags (even deprecated ones) must still
-// exist in a message or things break
-type missingTagErr struct {
+// reservedErr describes occasions where a field uses a tag, or a
+// name, which its type has reserved
+type reservedErr struct {
+	t        string
+	f        string
+	what     string
+	value    string
+	pos      lexer.Position
+	reserved lexer.Position
+}
+
+// Error returns an error message describing which field uses which
+// reserved tag or name
+func (e reservedErr) Error() string {
+	return fmt.Sprintf("field %s of type %s at %s uses %s %s, which is reserved at %s",
+		e.f,
+		e.t,
+		e.pos.String(),
+		e.what,
+		e.value,
+		e.reserved.String(),
+	)
+}
+
+// invalidAnnotationErr describes occasions where an annotation is
+// missing a value, or has a value it doesn't expect
+type invalidAnnotationErr struct {
+	a      string
+	reason string
+	pos    lexer.Position
+}
+
+// Error returns an error message describing which annotation is
+// invalid, and why
+func (e invalidAnnotationErr) Error() string {
+	return fmt.Sprintf("%s annotation at %s is invalid: %s",
+		e.a,
+		e.pos.String(),
+		e.reason,
+	)
+}
+
+// maxOptionalFields is the most optional fields a type may have, being
+// the number of bits in the presence bitmap generated code works with
+const maxOptionalFields = 64
+
+// optionalCollectionErr describes occasions where a slice, array, or
+// map is marked optional; collections may already be empty, and so
+// can't also be absent
+type optionalCollectionErr struct {
 	t   string
+	f   string
+	pos lexer.Position
+}
+
+// Error returns an error message describing which field of which
+// type is an optional collection
+func (e optionalCollectionErr) Error() string {
+	return fmt.Sprintf("field %s of type %s at %s is a collection, and so can't be optional",
+		e.f,
+		e.t,
+		e.pos.String(),
+	)
+}
+
+// tooManyOptionalsErr describes occasions where a type has more
+// optional fields than fit into a presence bitmap
+type tooManyOptionalsErr struct {
+	t     string
+	count int
+}
+
+// Error returns an error message describing which type has too
+// many optional fields
+func (e tooManyOptionalsErr) Error() string {
+	return fmt.Sprintf("type %s has %d optional fields, but may have at most %d",
+		e.t,
+		e.count,
+		maxOptionalFields,
+	)
+}
+
+// maxUnionTag is the largest tag a union variant may have, being the
+// largest value which fits into a union's discriminator byte
+const maxUnionTag = 255
+
+// emptyUnionErr describes occasions where a union has no variants,
+// and so can never hold a value
+type emptyUnionErr struct {
+	u   string
+	pos lexer.Position
+}
+
+// Error returns an error message describing which union is empty
+func (e emptyUnionErr) Error() string {
+	return fmt.Sprintf("union %s at %s has no variants",
+		e.u,
+		e.pos.String(),
+	)
+}
+
+// unionTagRangeErr describes occasions where a union variant has a
+// tag which doesn't fit into a discriminator byte
+type unionTagRangeErr struct {
+	u   string
+	v   string
 	tag int
+	pos lexer.Position
 }
 
-// Error returns an error message describing which tag is
-// missing in which type
-func (e missingTagErr) Error() string {
-	return fmt.Sprintf("missing tag %d for type %s",
+// Error returns an error message describing which variant of which
+// union has an out of range tag
+func (e unionTagRangeErr) Error() string {
+	return fmt.Sprintf("variant %s of union %s at %s has tag %d, but tags must be between 0 and %d",
+		e.v,
+		e.u,
+		e.pos.String(),
 		e.tag,
+		maxUnionTag,
+	)
+}
+
+// duplicateDefaultErr describes occasions where a field has a default
+// set both by annotation and by field option
+type duplicateDefaultErr struct {
+	t   string
+	f   string
+	pos lexer.Position
+}
+
+// Error returns an error message describing which field of which
+// type has more than one default
+func (e duplicateDefaultErr) Error() string {
+	return fmt.Sprintf("field %s of type %s at %s has more than one default",
+		e.f,
+		e.t,
+		e.pos.String(),
+	)
+}
+
+// invalidDefaultErr describes occasions where the default value of a
+// field doesn't suit the type of that field
+type invalidDefaultErr struct {
+	t      string
+	f      string
+	v      string
+	reason string
+	pos    lexer.Position
+}
+
+// Error returns an error message describing which field of which type
+// has an invalid default, and why
+func (e invalidDefaultErr) Error() string {
+	return fmt.Sprintf("default %s of field %s of type %s at %s is invalid: %s",
+		e.v,
+		e.f,
 		e.t,
+		e.pos.String(),
+		e.reason,
+	)
+}
+
+// unexpectedArgsErr describes occasions where an annotation other than
+// a validation is passed arguments
+type unexpectedArgsErr struct {
+	a   string
+	pos lexer.Position
+}
+
+// Error returns an error message describing which annotation
+// has arguments
+func (e unexpectedArgsErr) Error() string {
+	return fmt.Sprintf("%s annotation at %s has arguments, but only validations take arguments",
+		e.a,
+		e.pos.String(),
+	)
+}
+
+// invalidConstErr describes occasions where the value of a const
+// doesn't suit its type
+type invalidConstErr struct {
+	c      string
+	reason string
+	pos    lexer.Position
+}
+
+// Error returns an error message describing which const is
+// invalid, and why
+func (e invalidConstErr) Error() string {
+	return fmt.Sprintf("const %s at %s is invalid: %s",
+		e.c,
+		e.pos.String(),
+		e.reason,
 	)
 }
 
-// merge takes a slice of asts, ensures uniqueness of names, and
-// returns either an error describing collisions, or the union of
-// all ASTs
-func merge(in []*AST) (out *AST, err error) {
+// constRefErr describes occasions where a const is referenced, such
+// as by an array size or validation argument, but can't be used there
+type constRefErr struct {
+	c      string
+	reason string
+	pos    lexer.Position
+}
+
+// Error returns an error message describing which reference to which
+// const is invalid, and why
+func (e constRefErr) Error() string {
+	return fmt.Sprintf("invalid reference to const %s at %s: %s",
+		e.c,
+		e.pos.String(),
+		e.reason,
+	)
+}
+
+// packageMismatchErr describes occasions where documents parsed
+// together disagree on which package they belong to, or where a
+// package's options conflict
+type packageMismatchErr struct {
+	what   string
+	values map[string][]lexer.Position
+}
+
+// Error returns an error message listing each of the values declared,
+// and where
+func (e packageMismatchErr) Error() string {
+	values := make([]string, 0, len(e.values))
+	for v, locs := range e.values {
+		if v == "" {
+			v = "(none)"
+		}
+
+		cols := make([]string, len(locs))
+		for i, l := range locs {
+			cols[i] = l.String()
+		}
+
+		values = append(values, fmt.Sprintf("%s at %s", v, strings.Join(cols, ", ")))
+	}
+
+	sort.Strings(values)
+
+	return fmt.Sprintf("%d conflicting %ss declared:\n\t%s",
+		len(e.values),
+		e.what,
+		strings.Join(values, "\n\t"),
+	)
+}
+
+// importErr describes occasions where a package can't be imported
+type importErr struct {
+	path   string
+	reason string
+	pos    lexer.Position
+}
+
+// Error returns an error message describing which import failed,
+// and why
+func (e importErr) Error() string {
+	return fmt.Sprintf("import %q at %s failed: %s",
+		e.path,
+		e.pos.String(),
+		e.reason,
+	)
+}
+
+// merge takes a slice of asts, ensures uniqueness of names, resolves
+// imports, and returns either an error describing collisions, or the
+// union of all ASTs.
+//
+// importing holds the absolute path of each directory currently being
+// parsed, so that import cycles can be found
+func merge(in []*AST, importing map[string]bool) (out *AST, err error) {
 	names := make(map[string][]lexer.Position)
 	intermediateOut := new(AST)
 
+	packages := make(map[string][]lexer.Position)
+	goPackages := make(map[string][]lexer.Position)
+
 	for _, a := range in {
 		if a == nil {
 			continue
 		}
 
+		packages[a.Package] = append(packages[a.Package], a.pos)
+		if a.GoPackage != "" {
+			goPackages[a.GoPackage] = append(goPackages[a.GoPackage], a.pos)
+		}
+
 		intermediateOut.Types = append(intermediateOut.Types, a.Types...)
 		intermediateOut.Enums = append(intermediateOut.Enums, a.Enums...)
+		intermediateOut.Unions = append(intermediateOut.Unions, a.Unions...)
+		intermediateOut.Consts = append(intermediateOut.Consts, a.Consts...)
 
-		for _, t := range namedSlice(a.Types, a.Enums) {
+		for _, t := range namedSlice(a.Types, a.Enums, a.Unions, a.Consts) {
 			if _, ok := names[t.name()]; !ok {
 				names[t.name()] = make([]lexer.Position, 0)
 			}
@@ -53,19 +320,171 @@ func merge(in []*AST) (out *AST, err error) {
 		return
 	}
 
-	// Validate all type fields against types/ enums, scalars map
+	switch {
+	case len(packages) > 1:
+		return nil, packageMismatchErr{what: "package", values: packages}
+
+	case len(goPackages) > 1:
+		return nil, packageMismatchErr{what: "go_package", values: goPackages}
+	}
+
+	for p := range packages {
+		intermediateOut.Package = p
+	}
+
+	for p := range goPackages {
+		intermediateOut.GoPackage = p
+	}
+
+	err = resolveImports(intermediateOut, in, importing)
+	if err != nil {
+		return
+	}
+
+	// Imported types, enums, and unions are referred to by their
+	// qualified names, such as geo.Location
+	enums := make(map[string]Enum)
+	for alias, dep := range intermediateOut.Imports {
+		for _, t := range namedSlice(dep.Types, dep.Enums, dep.Unions, nil) {
+			names[alias+"."+t.name()] = append(names[alias+"."+t.name()], t.pos())
+		}
+
+		for _, e := range dep.Enums {
+			enums[alias+"."+e.Name] = e
+		}
+	}
+
+	// Consts share a namespace with types, enums, and unions, but
+	// can't be used as the types of fields
+	consts := make(map[string]Const)
+	for _, c := range intermediateOut.Consts {
+		err = validateConst(c)
+		if err != nil {
+			return
+		}
+
+		consts[c.Name] = c
+		delete(names, c.Name)
+	}
+
+	// Validate all type fields against types/ enums, scalars map,
+	// and defaults against the types of their fields
+	for _, e := range intermediateOut.Enums {
+		enums[e.Name] = e
+	}
+
 	for _, t := range intermediateOut.Types {
 		for _, e := range t.Entries {
 			err = e.IsValidType(names)
 			if err != nil {
 				return
 			}
+
+			err = validateDefault(t.Name, e, enums)
+			if err != nil {
+				return
+			}
+
+			err = resolveSizes(e.DataType, consts)
+			if err != nil {
+				return
+			}
+
+			for _, v := range e.Validations {
+				err = validateArgs(v.Args, consts)
+				if err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	// Validate all union variants against types/ enums, scalars map
+	for _, u := range intermediateOut.Unions {
+		for _, v := range u.Variants {
+			err = v.IsValidType(names)
+			if err != nil {
+				return
+			}
 		}
 	}
 
+	// Fingerprints describe the sizes of arrays, and so can only be
+	// taken once those sizes are resolved
+	for i := range intermediateOut.Types {
+		intermediateOut.Types[i].Fingerprint = intermediateOut.Types[i].fingerprint(intermediateOut)
+	}
+
 	return intermediateOut, nil
 }
 
+// resolveImports parses the package imported by each import statement
+// in the documents in, adding it to out.Imports.
+//
+// Import paths are relative to the directory of the importing document,
+// and each name a package is imported as must refer to only one package
+func resolveImports(out *AST, in []*AST, importing map[string]bool) error {
+	dirs := make(map[string]string)
+
+	for _, a := range in {
+		if a == nil {
+			continue
+		}
+
+		for _, imp := range a.imports {
+			invalid := func(reason string) error {
+				return importErr{
+					path:   imp.Path,
+					reason: reason,
+					pos:    imp.Pos,
+				}
+			}
+
+			dir := imp.Path
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(a.dir, dir)
+			}
+
+			dir, err := filepath.Abs(dir)
+			if err != nil {
+				return invalid(err.Error())
+			}
+
+			if importing[dir] {
+				return invalid("import cycle")
+			}
+
+			dep, err := parseDir(dir, importing)
+			if err != nil {
+				return invalid(err.Error())
+			}
+
+			alias := imp.Alias
+			if alias == "" {
+				alias = dep.Package
+			}
+
+			if alias == "" {
+				return invalid("imported documents don't declare a package")
+			}
+
+			if d, ok := dirs[alias]; ok && d != dir {
+				return invalid(fmt.Sprintf("%s already refers to the package in %s", alias, d))
+			}
+
+			dirs[alias] = dir
+
+			if out.Imports == nil {
+				out.Imports = make(map[string]*AST)
+			}
+
+			out.Imports[alias] = dep
+		}
+	}
+
+	return nil
+}
+
 // toAnnotatedType accepts a Type definiton from our parser, and
 // generates an annotated type.
 //
@@ -74,6 +493,8 @@ func merge(in []*AST) (out *AST, err error) {
 //  2. Determine which validations, transforms, and doc strings belong to which 'thing'
 //  3. Ensure each entry is unique in name
 //  4. Ensure each entry has a unique position tag
+//  5. Ensure only scalars and user types are optional, and that there
+//     aren't more optional fields than fit into a presence bitmap
 //
 // Groupings occur by parsing each entry until we hit a field definition, and then
 // merging those entries into a single definition.
@@ -82,13 +503,50 @@ func toAnnotatedType(m Type) (a AnnotatedType, err error) {
 	a.Name = m.Name
 	a.Entries = make([]AnnotatedEntry, 0)
 
+	for _, o := range m.Options {
+		a.Evolvable = a.Evolvable || o.Evolvable
+	}
+
 	names := make(map[string][]lexer.Position)
 	tags := make(map[string][]lexer.Position)
-	tagValues := make([]int, 0)
+	optionals := 0
+
+	reservedTags := make(map[string][]lexer.Position)
+	reservedNames := make(map[string][]lexer.Position)
 
 	ae := AnnotatedEntry{}
 	for _, e := range m.Entries {
+		if e.Reserved != nil {
+			for _, v := range e.Reserved.Values {
+				if v.Tag != nil {
+					reservedTags[intToStr(*v.Tag)] = append(reservedTags[intToStr(*v.Tag)], v.Pos)
+					a.ReservedTags = append(a.ReservedTags, *v.Tag)
+
+					continue
+				}
+
+				reservedNames[*v.Name] = append(reservedNames[*v.Name], v.Pos)
+				a.ReservedNames = append(a.ReservedNames, *v.Name)
+			}
+
+			continue
+		}
+
 		if e.Annotation != nil {
+			if len(e.Annotation.Args) > 0 && e.Annotation.Type != "validate" {
+				err = unexpectedArgsErr{
+					a:   e.Annotation.Type,
+					pos: e.Annotation.Pos,
+				}
+
+				return
+			}
+
+			err = validateAnnotation(*e.Annotation)
+			if err != nil {
+				return
+			}
+
 			switch e.Annotation.Type {
 			case "doc":
 				ae.AppendDocString(e.Annotation.Value)
@@ -96,12 +554,18 @@ func toAnnotatedType(m Type) (a AnnotatedType, err error) {
 				ae.AppendValidation(Validation{
 					IsCustom: e.Annotation.Provider == "custom",
 					Function: e.Annotation.Func,
+					Args:     e.Annotation.Args,
 				})
 			case "transform":
 				ae.AppendTransformation(Transformation{
 					IsCustom: e.Annotation.Provider == "custom",
 					Function: e.Annotation.Func,
 				})
+			case "default":
+				ae.Default = annotationValue(*e.Annotation)
+			case "deprecated":
+				ae.Deprecated = true
+				ae.DeprecatedReason = e.Annotation.Value
 			}
 
 			continue
@@ -122,7 +586,37 @@ func toAnnotatedType(m Type) (a AnnotatedType, err error) {
 
 		tags[tagStr] = append(tags[tagStr], e.Field.Pos)
 
-		tagValues = append(tagValues, e.Field.Tag)
+		if e.Field.Optional {
+			if e.Field.DataType.Scalar == nil {
+				err = optionalCollectionErr{
+					t:   a.Name,
+					f:   e.Field.Name,
+					pos: e.Field.Pos,
+				}
+
+				return
+			}
+
+			optionals++
+		}
+
+		for _, o := range e.Field.Options {
+			if o.Default == nil {
+				continue
+			}
+
+			if ae.Default != nil {
+				err = duplicateDefaultErr{
+					t:   a.Name,
+					f:   e.Field.Name,
+					pos: e.Field.Pos,
+				}
+
+				return
+			}
+
+			ae.Default = o.Default
+		}
 
 		ae.Field = *e.Field
 		a.Entries = append(a.Entries, ae)
@@ -141,15 +635,50 @@ func toAnnotatedType(m Type) (a AnnotatedType, err error) {
 		return
 	}
 
-	// Ensure there are no missing tags
-	is := sort.IntSlice(tagValues)
-	is.Sort()
+	if optionals > maxOptionalFields {
+		err = tooManyOptionalsErr{
+			t:     a.Name,
+			count: optionals,
+		}
 
-	for idx, v := range is {
-		if idx != v {
-			err = missingTagErr{
-				t:   a.Name,
-				tag: idx,
+		return
+	}
+
+	// Ensure neither reserved tags, nor reserved names, are reused,
+	// and are only reserved once
+	err = toCollisionError(a.Name+" reserved tag", reservedTags)
+	if err != nil {
+		return
+	}
+
+	err = toCollisionError(a.Name+" reserved name", reservedNames)
+	if err != nil {
+		return
+	}
+
+	for _, e := range a.Entries {
+		tagStr := intToStr(e.Tag)
+		if pos, ok := reservedTags[tagStr]; ok {
+			err = reservedErr{
+				t:        a.Name,
+				f:        e.Name,
+				what:     "tag",
+				value:    tagStr,
+				pos:      e.Pos,
+				reserved: pos[0],
+			}
+
+			return
+		}
+
+		if pos, ok := reservedNames[e.Name]; ok {
+			err = reservedErr{
+				t:        a.Name,
+				f:        e.Name,
+				what:     "name",
+				value:    e.Name,
+				pos:      e.Pos,
+				reserved: pos[0],
 			}
 
 			return
@@ -159,6 +688,307 @@ func toAnnotatedType(m Type) (a AnnotatedType, err error) {
 	return
 }
 
+// validateAnnotation ensures an annotation has a value, save for
+// +mint:deprecated, which may only be given a quoted reason
+func validateAnnotation(a Annotation) error {
+	if a.Type == "deprecated" {
+		if a.Func != "" || a.Number != "" {
+			return invalidAnnotationErr{
+				a:      a.Type,
+				reason: "only a quoted reason may be given",
+				pos:    a.Pos,
+			}
+		}
+
+		return nil
+	}
+
+	if !a.HasValue {
+		return invalidAnnotationErr{
+			a:      a.Type,
+			reason: "missing a value",
+			pos:    a.Pos,
+		}
+	}
+
+	return nil
+}
+
+// validateUnion ensures a union has at least one variant, and that
+// each variant has a unique name, and a unique tag which fits into a
+// discriminator byte.
+//
+// Unlike the fields of a type, variant tags need not be contiguous;
+// only one variant is ever encoded, and so gaps cost nothing
+func validateUnion(u Union) (err error) {
+	if len(u.Variants) == 0 {
+		return emptyUnionErr{
+			u:   u.Name,
+			pos: u.Pos,
+		}
+	}
+
+	names := make(map[string][]lexer.Position)
+	tags := make(map[string][]lexer.Position)
+
+	for _, v := range u.Variants {
+		if v.Tag < 0 || v.Tag > maxUnionTag {
+			return unionTagRangeErr{
+				u:   u.Name,
+				v:   v.Name,
+				tag: v.Tag,
+				pos: v.Pos,
+			}
+		}
+
+		names[v.Name] = append(names[v.Name], v.Pos)
+
+		tagStr := intToStr(v.Tag)
+		tags[tagStr] = append(tags[tagStr], v.Pos)
+	}
+
+	// Ensure names are unique
+	err = toCollisionError(u.Name+" variant", names)
+	if err != nil {
+		return
+	}
+
+	// Ensure tags are unique
+	return toCollisionError(u.Name+" tag value", tags)
+}
+
+// annotationValue returns the value set by annotation a, such as
+// +mint:default:"eu-west-1"
+func annotationValue(a Annotation) *Value {
+	v := &Value{Pos: a.Pos}
+
+	switch {
+	case a.Func != "":
+		v.Ident = &a.Func
+
+	case a.Number != "":
+		v.Number = &a.Number
+
+	default:
+		v.Quoted = &a.Value
+	}
+
+	return v
+}
+
+// validateDefault ensures the default of e, where set, suits its type.
+//
+// Only scalars and enums may have defaults; strings, datetimes, and
+// uuids take quoted strings, numbers take numbers which fit their type,
+// and bools and enums take identifiers naming one of their values
+func validateDefault(t string, e AnnotatedEntry, enums map[string]Enum) error {
+	if e.Default == nil {
+		return nil
+	}
+
+	invalid := func(reason string) error {
+		return invalidDefaultErr{
+			t:      t,
+			f:      e.Name,
+			v:      e.Default.String(),
+			reason: reason,
+			pos:    e.Default.Pos,
+		}
+	}
+
+	switch {
+	case e.DataType.IsCollection():
+		return invalid("collections can't have defaults")
+
+	case e.Optional:
+		return invalid("optional fields can't have defaults")
+	}
+
+	st := e.DataType.Scalar.Type
+	v := e.Default
+
+	if enum, ok := enums[st]; ok {
+		if v.Ident != nil {
+			for _, ev := range enum.Values {
+				if ev.Value.Key == *v.Ident {
+					return nil
+				}
+			}
+		}
+
+		return invalid(fmt.Sprintf("expected a value of enum %s", st))
+	}
+
+	if !Scalars[st] {
+		return invalid("only scalars and enums may have defaults")
+	}
+
+	if err := checkValue(st, v); err != nil {
+		return invalid(err.Error())
+	}
+
+	return nil
+}
+
+// checkValue ensures v is a valid value of scalar st, returning an error
+// describing why not where it isn't
+func checkValue(st string, v *Value) (err error) {
+	switch st {
+	case "string":
+		if v.Quoted == nil {
+			return errors.New("expected a quoted string")
+		}
+
+	case "datetime":
+		if v.Quoted == nil {
+			return errors.New("expected a quoted RFC3339 datetime")
+		}
+
+		_, err = time.Parse(time.RFC3339, *v.Quoted)
+
+	case "uuid":
+		if v.Quoted == nil {
+			return errors.New("expected a quoted uuid")
+		}
+
+		_, err = uuid.FromString(*v.Quoted)
+
+	case "bool":
+		if v.Ident == nil || (*v.Ident != "true" && *v.Ident != "false") {
+			return errors.New("expected true or false")
+		}
+
+	case "int16", "int32", "int64":
+		if v.Number == nil {
+			return errors.New("expected an integer")
+		}
+
+		_, err = strconv.ParseInt(*v.Number, 10, bitSize(st))
+
+	case "byte":
+		if v.Number == nil {
+			return errors.New("expected an integer")
+		}
+
+		_, err = strconv.ParseUint(*v.Number, 10, 8)
+
+	case "float32", "float64":
+		if v.Number == nil {
+			return errors.New("expected a number")
+		}
+
+		_, err = strconv.ParseFloat(*v.Number, bitSize(st))
+	}
+
+	if ne, ok := err.(*strconv.NumError); ok {
+		err = ne.Err
+	}
+
+	return
+}
+
+// validateConst ensures the value of c suits its type; consts may be
+// strings, bools, or numbers, but not datetimes or uuids, which have
+// no constant form in go
+func validateConst(c Const) error {
+	var err error
+
+	switch c.Type {
+	case "datetime", "uuid":
+		err = fmt.Errorf("consts can't be of type %s", c.Type)
+
+	default:
+		if !Scalars[c.Type] {
+			err = fmt.Errorf("consts must be scalars, rather than %s", c.Type)
+
+			break
+		}
+
+		err = checkValue(c.Type, c.Value)
+	}
+
+	if err != nil {
+		return invalidConstErr{
+			c:      c.Name,
+			reason: err.Error(),
+			pos:    c.Pos,
+		}
+	}
+
+	return nil
+}
+
+// resolveSizes sets the size of each array in dt sized by a const, such
+// as [MaxTags]string, to the value of that const
+func resolveSizes(dt *DataType, consts map[string]Const) error {
+	for ; dt != nil; dt = dt.Elem() {
+		fs := dt.FixedSizeSlice
+		if fs == nil || fs.SizeRef == "" {
+			continue
+		}
+
+		invalid := func(reason string) error {
+			return constRefErr{
+				c:      fs.SizeRef,
+				reason: reason,
+				pos:    fs.Pos,
+			}
+		}
+
+		c, ok := consts[fs.SizeRef]
+		if !ok {
+			return invalid("no such const")
+		}
+
+		if c.Type == "string" || c.Type == "bool" || strings.HasPrefix(c.Type, "float") {
+			return invalid("array sizes must be integers")
+		}
+
+		size, err := strconv.Atoi(*c.Value.Number)
+		if err != nil || size < 0 {
+			return invalid("array sizes can't be negative")
+		}
+
+		fs.Size = size
+	}
+
+	return nil
+}
+
+// validateArgs ensures each identifier passed to a validation, other
+// than true and false, names a const
+func validateArgs(args []*Value, consts map[string]Const) error {
+	for _, a := range args {
+		if a.Ident == nil || *a.Ident == "true" || *a.Ident == "false" {
+			continue
+		}
+
+		if _, ok := consts[*a.Ident]; !ok {
+			return constRefErr{
+				c:      *a.Ident,
+				reason: "no such const",
+				pos:    a.Pos,
+			}
+		}
+	}
+
+	return nil
+}
+
+// bitSize returns the size, in bits, of numeric scalar st, for use
+// with the strconv package
+func bitSize(st string) int {
+	switch st {
+	case "int16":
+		return 16
+
+	case "int32", "float32":
+		return 32
+	}
+
+	return 64
+}
+
 func intToStr(i int) string {
 	return fmt.Sprintf("%d", i)
 }
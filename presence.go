@@ -0,0 +1,166 @@
+package mint
+
+import (
+	"io"
+)
+
+// PresenceSize returns the number of bytes a presence bitmap covering
+// n optional fields takes up on the wire
+func PresenceSize(n int) int {
+	return (n + 7) / 8
+}
+
+// AppendPresence appends the presence bitmap p, covering n optional
+// fields, to b, returning the extended buffer.
+//
+// Bit i of p is set where the i-th optional field (in tag order) is
+// present, and p is written least significant byte first
+func AppendPresence(b []byte, p uint64, n int) []byte {
+	for i := 0; i < PresenceSize(n); i++ {
+		b = append(b, byte(p>>(8*i)))
+	}
+
+	return b
+}
+
+// WritePresence writes the presence bitmap p, covering n optional
+// fields, to w
+func WritePresence(w io.Writer, p uint64, n int) error {
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendPresence(b, p, n)
+	})
+}
+
+// ReadPresence reads a presence bitmap covering n optional fields
+// from r, returning ErrInvalidPresence should any bit beyond the
+// n-th be set
+func ReadPresence(r io.Reader, n int) (uint64, error) {
+	b, err := readScratch(r, PresenceSize(n))
+	if err != nil {
+		return 0, err
+	}
+
+	return decodePresence(b, n)
+}
+
+// ReadPresence reads a presence bitmap covering n optional fields
+func (d *BytesDecoder) ReadPresence(n int) (uint64, error) {
+	b, err := d.next(int64(PresenceSize(n)))
+	if err != nil {
+		return 0, err
+	}
+
+	return decodePresence(b, n)
+}
+
+// decodePresence converts the bytes of a presence bitmap into a uint64,
+// ensuring no bits are set for fields which don't exist
+func decodePresence(b []byte, n int) (p uint64, err error) {
+	for i, c := range b {
+		p |= uint64(c) << (8 * i)
+	}
+
+	if n < 64 && p>>n != 0 {
+		err = ErrInvalidPresence{
+			Fields: n,
+			Bitmap: p,
+		}
+	}
+
+	return
+}
+
+// SizedPresenceSize returns the number of bytes the presence bitmap of
+// an evolvable type with n optional fields takes up on the wire
+func SizedPresenceSize(n int) int {
+	return 1 + PresenceSize(n)
+}
+
+// AppendSizedPresence appends the presence bitmap p of an evolvable
+// type, covering n optional fields, to b, returning the extended buffer.
+//
+// Unlike AppendPresence, the bitmap is always written, and is preceded
+// by a byte holding its length, so that readers may cope with a bitmap
+// covering more, or fewer, optional fields than they know about
+func AppendSizedPresence(b []byte, p uint64, n int) []byte {
+	return AppendPresence(append(b, byte(PresenceSize(n))), p, n)
+}
+
+// WriteSizedPresence writes the presence bitmap p of an evolvable type,
+// covering n optional fields, to w
+func WriteSizedPresence(w io.Writer, p uint64, n int) error {
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendSizedPresence(b, p, n)
+	})
+}
+
+// ReadSizedPresence reads the presence bitmap of an evolvable type with
+// n optional fields from r.
+//
+// Bits beyond the n-th belong to optional fields added since the reader
+// was generated, and so are ignored, whereas a bitmap shorter than
+// expected comes from before later fields were added, which are absent
+func ReadSizedPresence(r io.Reader, n int) (uint64, error) {
+	l, err := ReadByte(r)
+	if err != nil {
+		return 0, err
+	}
+
+	err = checkPresenceLength(l)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := readScratch(r, int(l))
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeSizedPresence(b, n), nil
+}
+
+// ReadSizedPresence reads the presence bitmap of an evolvable type with
+// n optional fields
+func (d *BytesDecoder) ReadSizedPresence(n int) (uint64, error) {
+	l, err := d.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	err = checkPresenceLength(l)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := d.next(int64(l))
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeSizedPresence(b, n), nil
+}
+
+// checkPresenceLength ensures the length of a sized presence bitmap
+// fits into a uint64
+func checkPresenceLength(l byte) error {
+	if l > 8 {
+		return ErrLimitExceeded{
+			Limit:    "presence bytes",
+			Max:      8,
+			Received: int64(l),
+		}
+	}
+
+	return nil
+}
+
+// decodeSizedPresence converts the bytes of a sized presence bitmap
+// into a uint64, dropping the bits of any fields beyond the n-th
+func decodeSizedPresence(b []byte, n int) (p uint64) {
+	p, _ = decodePresence(b, 64)
+	if n < 64 {
+		p &= 1<<n - 1
+	}
+
+	return
+}
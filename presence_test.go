@@ -0,0 +1,178 @@
+package mint
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPresence(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		p      uint64
+		n      int
+		expect []byte
+	}{
+		{"No optional fields", 0, 0, []byte{}},
+		{"Single field, absent", 0, 1, []byte{0}},
+		{"Single field, present", 1, 1, []byte{1}},
+		{"Eight fields", 0b10000001, 8, []byte{0x81}},
+		{"Nine fields", 0b100000001, 9, []byte{0x01, 0x01}},
+		{"Sixty four fields", 1 << 63, 64, []byte{0, 0, 0, 0, 0, 0, 0, 0x80}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := AppendPresence(nil, test.p, test.n)
+			if !bytes.Equal(test.expect, b) && len(test.expect)+len(b) > 0 {
+				t.Errorf("expected %#v, received %#v", test.expect, b)
+			}
+
+			if PresenceSize(test.n) != len(b) {
+				t.Errorf("expected size %d, received %d", len(b), PresenceSize(test.n))
+			}
+
+			buf := new(bytes.Buffer)
+
+			err := WritePresence(buf, test.p, test.n)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !bytes.Equal(b, buf.Bytes()) {
+				t.Errorf("WritePresence and AppendPresence differ: %#v vs %#v", buf.Bytes(), b)
+			}
+
+			p, err := ReadPresence(bytes.NewReader(b), test.n)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.p != p {
+				t.Errorf("expected %#b, received %#b", test.p, p)
+			}
+
+			p, err = NewBytesDecoder(b, nil).ReadPresence(test.n)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.p != p {
+				t.Errorf("expected %#b, received %#b", test.p, p)
+			}
+		})
+	}
+}
+
+func TestReadPresence_Invalid(t *testing.T) {
+	_, err := ReadPresence(bytes.NewReader([]byte{0b100}), 2)
+	if !errors.As(err, new(ErrInvalidPresence)) {
+		t.Errorf("expected ErrInvalidPresence, received %#v", err)
+	}
+
+	_, err = NewBytesDecoder([]byte{0x01, 0x02}, nil).ReadPresence(9)
+	if !errors.As(err, new(ErrInvalidPresence)) {
+		t.Errorf("expected ErrInvalidPresence, received %#v", err)
+	}
+
+	_, err = ReadPresence(bytes.NewReader(nil), 2)
+	if !errors.As(err, new(ErrTruncated)) {
+		t.Errorf("expected ErrTruncated, received %#v", err)
+	}
+}
+
+func TestSizedPresence(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		p      uint64
+		n      int
+		expect []byte
+	}{
+		{"No optional fields", 0, 0, []byte{0}},
+		{"Single field, present", 1, 1, []byte{1, 1}},
+		{"Nine fields", 0b100000001, 9, []byte{2, 0x01, 0x01}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := AppendSizedPresence(nil, test.p, test.n)
+			if !bytes.Equal(test.expect, b) {
+				t.Errorf("expected %#v, received %#v", test.expect, b)
+			}
+
+			if SizedPresenceSize(test.n) != len(b) {
+				t.Errorf("expected size %d, received %d", len(b), SizedPresenceSize(test.n))
+			}
+
+			buf := new(bytes.Buffer)
+
+			err := WriteSizedPresence(buf, test.p, test.n)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !bytes.Equal(b, buf.Bytes()) {
+				t.Errorf("WriteSizedPresence and AppendSizedPresence differ: %#v vs %#v", buf.Bytes(), b)
+			}
+
+			p, err := ReadSizedPresence(bytes.NewReader(b), test.n)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.p != p {
+				t.Errorf("expected %#b, received %#b", test.p, p)
+			}
+
+			p, err = NewBytesDecoder(b, nil).ReadSizedPresence(test.n)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.p != p {
+				t.Errorf("expected %#b, received %#b", test.p, p)
+			}
+		})
+	}
+}
+
+func TestReadSizedPresence_Evolved(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		input  []byte
+		n      int
+		expect uint64
+	}{
+		{"Written with more optional fields", []byte{2, 0xff, 0x01}, 3, 0b111},
+		{"Written with fewer optional fields", []byte{1, 0x81}, 9, 0x81},
+		{"Written without optional fields", []byte{0}, 2, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := ReadSizedPresence(bytes.NewReader(test.input), test.n)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.expect != p {
+				t.Errorf("expected %#b, received %#b", test.expect, p)
+			}
+
+			p, err = NewBytesDecoder(test.input, nil).ReadSizedPresence(test.n)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.expect != p {
+				t.Errorf("expected %#b, received %#b", test.expect, p)
+			}
+		})
+	}
+}
+
+func TestReadSizedPresence_Invalid(t *testing.T) {
+	_, err := ReadSizedPresence(bytes.NewReader([]byte{9}), 2)
+	if !errors.As(err, new(ErrLimitExceeded)) {
+		t.Errorf("expected ErrLimitExceeded, received %#v", err)
+	}
+
+	_, err = NewBytesDecoder([]byte{2, 0x01}, nil).ReadSizedPresence(9)
+	if !errors.As(err, new(ErrTruncated)) {
+		t.Errorf("expected ErrTruncated, received %#v", err)
+	}
+}
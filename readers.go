@@ -0,0 +1,166 @@
+package mint
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// ReadString reads the mint encoding of a string from r
+func ReadString(r io.Reader) (string, error) {
+	if d, ok := r.(*BytesDecoder); ok {
+		return d.ReadString()
+	}
+
+	// Strings are read in two parts; wrapping r allows any error to
+	// report an offset from the start of the string, rather than from
+	// the start of whichever part was being read
+	dr := AsDecodeReader(r)
+
+	b, err := readScratch(dr, 8)
+	if err != nil {
+		return "", err
+	}
+
+	l := int64(binary.LittleEndian.Uint64(b))
+
+	err = checkStringLength(dr, l)
+	if err != nil {
+		return "", err
+	}
+
+	return readString(dr, l)
+}
+
+// ReadDatetime reads the mint encoding of a datetime from r
+func ReadDatetime(r io.Reader) (time.Time, error) {
+	b, err := readScratch(r, 8)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return timeFromUnixNano(int64(binary.LittleEndian.Uint64(b))), nil
+}
+
+// ReadUuid reads the mint encoding of a uuid from r
+func ReadUuid(r io.Reader) (u uuid.UUID, err error) {
+	b, err := readScratch(r, uuid.Size)
+	if err != nil {
+		return
+	}
+
+	copy(u[:], b)
+
+	return
+}
+
+// ReadInt16 reads the mint encoding of an int16 from r
+func ReadInt16(r io.Reader) (int16, error) {
+	i, err := ReadUint16(r)
+
+	return int16(i), err
+}
+
+// ReadUint16 reads the mint encoding of a uint16 from r
+func ReadUint16(r io.Reader) (uint16, error) {
+	b, err := readScratch(r, 2)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+// ReadInt32 reads the mint encoding of an int32 from r
+func ReadInt32(r io.Reader) (int32, error) {
+	i, err := ReadUint32(r)
+
+	return int32(i), err
+}
+
+// ReadUint32 reads the mint encoding of a uint32 from r
+func ReadUint32(r io.Reader) (uint32, error) {
+	b, err := readScratch(r, 4)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// ReadInt64 reads the mint encoding of an int64 from r
+func ReadInt64(r io.Reader) (int64, error) {
+	b, err := readScratch(r, 8)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(binary.LittleEndian.Uint64(b)), nil
+}
+
+// ReadFloat32 reads the mint encoding of a float32 from r
+func ReadFloat32(r io.Reader) (float32, error) {
+	i, err := ReadUint32(r)
+
+	return math.Float32frombits(i), err
+}
+
+// ReadFloat64 reads the mint encoding of a float64 from r
+func ReadFloat64(r io.Reader) (float64, error) {
+	b, err := readScratch(r, 8)
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+}
+
+// ReadByte reads the mint encoding of a byte from r
+func ReadByte(r io.Reader) (byte, error) {
+	b, err := readScratch(r, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
+
+// ReadBool reads the mint encoding of a bool from r
+func ReadBool(r io.Reader) (bool, error) {
+	b, err := ReadByte(r)
+
+	return b != 0, err
+}
+
+// ReadSliceLen reads the number of elements in a slice from r,
+// ensuring it is within the limits in force for r
+func ReadSliceLen(r io.Reader) (int, error) {
+	if d, ok := r.(*BytesDecoder); ok {
+		return d.ReadSliceLen()
+	}
+
+	l, err := ReadUint32(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(l), checkCollectionLength(r, l)
+}
+
+// ReadMapLen reads the number of entries in a map from r,
+// ensuring it is within the limits in force for r
+func ReadMapLen(r io.Reader) (int, error) {
+	if d, ok := r.(*BytesDecoder); ok {
+		return d.ReadMapLen()
+	}
+
+	l, err := ReadUint32(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(l / 2), checkCollectionLength(r, l/2)
+}
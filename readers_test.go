@@ -0,0 +1,74 @@
+package mint
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestReaders(t *testing.T) {
+	b := AppendString(nil, "Hello, World!")
+	b = AppendInt16(b, -10_000)
+	b = AppendFloat64(b, -3.14)
+	b = AppendBool(b, true)
+	b = AppendUint32(b, 3)
+	b = AppendUint32(b, 6)
+
+	r := bytes.NewReader(b)
+
+	for _, test := range []struct {
+		name   string
+		f      func(io.Reader) (any, error)
+		expect any
+	}{
+		{"String", func(r io.Reader) (any, error) { return ReadString(r) }, "Hello, World!"},
+		{"Int16", func(r io.Reader) (any, error) { return ReadInt16(r) }, int16(-10_000)},
+		{"Float64", func(r io.Reader) (any, error) { return ReadFloat64(r) }, float64(-3.14)},
+		{"Bool", func(r io.Reader) (any, error) { return ReadBool(r) }, true},
+		{"Slice length", func(r io.Reader) (any, error) { return ReadSliceLen(r) }, 3},
+		{"Map length", func(r io.Reader) (any, error) { return ReadMapLen(r) }, 3},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			received, err := test.f(r)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.expect != received {
+				t.Errorf("expected %#v, received %#v", test.expect, received)
+			}
+		})
+	}
+
+	_, err := ReadByte(r)
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, received %#v", err)
+	}
+}
+
+func TestReaders_Limits(t *testing.T) {
+	opts := &DecodeOptions{MaxCollectionElements: 2}
+
+	for _, test := range []struct {
+		name string
+		f    func(io.Reader) (int, error)
+	}{
+		{"Slice length", ReadSliceLen},
+		{"Map length", ReadMapLen},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewDecodeReader(bytes.NewReader(AppendUint32(nil, 1_000)), opts)
+
+			_, err := test.f(r)
+			if err == nil {
+				t.Fatal("expected error, received none")
+			}
+
+			var received ErrLimitExceeded
+			if !errors.As(err, &received) {
+				t.Errorf("expected ErrLimitExceeded, received %#v", err)
+			}
+		})
+	}
+}
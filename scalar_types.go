@@ -1,7 +1,6 @@
 package mint
 
 import (
-	"encoding/binary"
 	"io"
 	"time"
 
@@ -19,30 +18,24 @@ func NewStringScalar(s string) *StringScalar {
 }
 
 func (s StringScalar) Marshall(w io.Writer) (err error) {
-	err = binary.Write(w, binary.LittleEndian, int64(len(s.v)))
+	err = writeAppended(w, func(b []byte) []byte {
+		return AppendInt64(b, int64(len(s.v)))
+	})
 	if err != nil {
 		return
 	}
 
-	_, err = w.Write([]byte(s.v))
+	_, err = io.WriteString(w, s.v)
 
 	return
 }
 
-func (s *StringScalar) Unmarshall(r io.Reader) (err error) {
-	var len int64
-	err = binary.Read(r, binary.LittleEndian, &len)
-	if err != nil {
-		return
-	}
-
-	in := make([]byte, len)
-	_, err = r.Read(in)
-	if err != nil {
-		return
-	}
+func (s StringScalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendString(b, s.v), nil
+}
 
-	s.v = string(in)
+func (s *StringScalar) Unmarshall(r io.Reader) (err error) {
+	s.v, err = ReadString(r)
 
 	return
 }
@@ -51,6 +44,10 @@ func (s StringScalar) Value() any {
 	return s.v
 }
 
+func (s StringScalar) Size() int {
+	return StringSize(s.v)
+}
+
 type DatetimeScalar struct {
 	v time.Time
 }
@@ -62,38 +59,41 @@ func NewDatetimeScalar(t time.Time) *DatetimeScalar {
 }
 
 func (s DatetimeScalar) Marshall(w io.Writer) error {
-	intermediate := s.v.UnixNano()
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendDatetime(b, s.v)
+	})
+}
 
-	return binary.Write(w, binary.LittleEndian, intermediate)
+func (s DatetimeScalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendDatetime(b, s.v), nil
 }
 
 func (s *DatetimeScalar) Unmarshall(r io.Reader) (err error) {
-	var intermediate int64
+	s.v, err = ReadDatetime(r)
 
-	err = binary.Read(r, binary.LittleEndian, &intermediate)
-	if err != nil {
-		return
-	}
+	return
+}
+
+func (s DatetimeScalar) Value() any {
+	return s.v
+}
 
+func (s DatetimeScalar) Size() int {
+	return DatetimeSize
+}
+
+func timeFromUnixNano(i int64) time.Time {
 	// This happens when an empty time.Time{} is serialised.
 	//
 	// In this situation, Unmarshall will create a time.Time with the date
 	//   time.Date(1754, time.August, 30, 22, 42, 26, 128654848, time.Local)
 	// Which represents the _actual_ earliest a time.Time can represent (as
 	// opposed to 1st January, Year 0 which a nil time.Time seems to be)
-	if intermediate == -6795364578871345152 {
-		s.v = time.Time{}
-
-		return
+	if i == -6795364578871345152 {
+		return time.Time{}
 	}
 
-	s.v = time.Unix(0, intermediate)
-
-	return
-}
-
-func (s DatetimeScalar) Value() any {
-	return s.v
+	return time.Unix(0, i)
 }
 
 type UuidScalar struct {
@@ -107,34 +107,17 @@ func NewUuidScalar(u uuid.UUID) *UuidScalar {
 }
 
 func (s UuidScalar) Marshall(w io.Writer) error {
-	scv := make([]MarshallerUnmarshallerValuer, uuid.Size)
-	b := s.v.Bytes()
-
-	for idx := range b {
-		scv[idx] = NewByteScalar(b[idx])
-	}
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendUuid(b, s.v)
+	})
+}
 
-	return NewSliceCollection(scv, true).Marshall(w)
+func (s UuidScalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendUuid(b, s.v), nil
 }
 
 func (s *UuidScalar) Unmarshall(r io.Reader) (err error) {
-	scv := make([]MarshallerUnmarshallerValuer, uuid.Size)
-	for idx := range scv {
-		scv[idx] = NewByteScalar('\x00')
-	}
-
-	err = NewSliceCollection(scv, true).Unmarshall(r)
-	if err != nil {
-		return
-	}
-
-	b := make([]byte, uuid.Size)
-
-	for idx := range scv {
-		b[idx] = scv[idx].Value().(byte)
-	}
-
-	s.v, err = uuid.FromBytes(b)
+	s.v, err = ReadUuid(r)
 
 	return
 }
@@ -143,6 +126,10 @@ func (s UuidScalar) Value() any {
 	return s.v
 }
 
+func (s UuidScalar) Size() int {
+	return UuidSize
+}
+
 type Int16Scalar struct {
 	v int16
 }
@@ -154,17 +141,29 @@ func NewInt16Scalar(i int16) *Int16Scalar {
 }
 
 func (s *Int16Scalar) Marshall(w io.Writer) error {
-	return binary.Write(w, binary.LittleEndian, s.v)
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendInt16(b, s.v)
+	})
+}
+
+func (s *Int16Scalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendInt16(b, s.v), nil
 }
 
 func (s *Int16Scalar) Unmarshall(r io.Reader) (err error) {
-	return binary.Read(r, binary.LittleEndian, &s.v)
+	s.v, err = ReadInt16(r)
+
+	return
 }
 
 func (s Int16Scalar) Value() any {
 	return s.v
 }
 
+func (s Int16Scalar) Size() int {
+	return Int16Size
+}
+
 type Int32Scalar struct {
 	v int32
 }
@@ -176,17 +175,29 @@ func NewInt32Scalar(i int32) *Int32Scalar {
 }
 
 func (s *Int32Scalar) Marshall(w io.Writer) error {
-	return binary.Write(w, binary.LittleEndian, s.v)
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendInt32(b, s.v)
+	})
+}
+
+func (s *Int32Scalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendInt32(b, s.v), nil
 }
 
 func (s *Int32Scalar) Unmarshall(r io.Reader) (err error) {
-	return binary.Read(r, binary.LittleEndian, &s.v)
+	s.v, err = ReadInt32(r)
+
+	return
 }
 
 func (s Int32Scalar) Value() any {
 	return s.v
 }
 
+func (s Int32Scalar) Size() int {
+	return Int32Size
+}
+
 type UInt32Scalar struct {
 	v uint32
 }
@@ -198,17 +209,29 @@ func NewUInt32Scalar(i uint32) *UInt32Scalar {
 }
 
 func (s *UInt32Scalar) Marshall(w io.Writer) error {
-	return binary.Write(w, binary.LittleEndian, s.v)
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendUint32(b, s.v)
+	})
+}
+
+func (s *UInt32Scalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendUint32(b, s.v), nil
 }
 
 func (s *UInt32Scalar) Unmarshall(r io.Reader) (err error) {
-	return binary.Read(r, binary.LittleEndian, &s.v)
+	s.v, err = ReadUint32(r)
+
+	return
 }
 
 func (s UInt32Scalar) Value() any {
 	return s.v
 }
 
+func (s UInt32Scalar) Size() int {
+	return Uint32Size
+}
+
 type Int64Scalar struct {
 	v int64
 }
@@ -220,17 +243,29 @@ func NewInt64Scalar(i int64) *Int64Scalar {
 }
 
 func (s *Int64Scalar) Marshall(w io.Writer) error {
-	return binary.Write(w, binary.LittleEndian, s.v)
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendInt64(b, s.v)
+	})
+}
+
+func (s *Int64Scalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendInt64(b, s.v), nil
 }
 
 func (s *Int64Scalar) Unmarshall(r io.Reader) (err error) {
-	return binary.Read(r, binary.LittleEndian, &s.v)
+	s.v, err = ReadInt64(r)
+
+	return
 }
 
 func (s Int64Scalar) Value() any {
 	return s.v
 }
 
+func (s Int64Scalar) Size() int {
+	return Int64Size
+}
+
 type Float32Scalar struct {
 	v float32
 }
@@ -242,17 +277,29 @@ func NewFloat32Scalar(i float32) *Float32Scalar {
 }
 
 func (s *Float32Scalar) Marshall(w io.Writer) error {
-	return binary.Write(w, binary.LittleEndian, s.v)
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendFloat32(b, s.v)
+	})
+}
+
+func (s *Float32Scalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendFloat32(b, s.v), nil
 }
 
 func (s *Float32Scalar) Unmarshall(r io.Reader) (err error) {
-	return binary.Read(r, binary.LittleEndian, &s.v)
+	s.v, err = ReadFloat32(r)
+
+	return
 }
 
 func (s Float32Scalar) Value() any {
 	return s.v
 }
 
+func (s Float32Scalar) Size() int {
+	return Float32Size
+}
+
 type Float64Scalar struct {
 	v float64
 }
@@ -264,17 +311,29 @@ func NewFloat64Scalar(i float64) *Float64Scalar {
 }
 
 func (s *Float64Scalar) Marshall(w io.Writer) error {
-	return binary.Write(w, binary.LittleEndian, s.v)
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendFloat64(b, s.v)
+	})
+}
+
+func (s *Float64Scalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendFloat64(b, s.v), nil
 }
 
 func (s *Float64Scalar) Unmarshall(r io.Reader) (err error) {
-	return binary.Read(r, binary.LittleEndian, &s.v)
+	s.v, err = ReadFloat64(r)
+
+	return
 }
 
 func (s Float64Scalar) Value() any {
 	return s.v
 }
 
+func (s Float64Scalar) Size() int {
+	return Float64Size
+}
+
 type ByteScalar struct {
 	v byte
 }
@@ -286,17 +345,29 @@ func NewByteScalar(i byte) *ByteScalar {
 }
 
 func (s *ByteScalar) Marshall(w io.Writer) error {
-	return binary.Write(w, binary.LittleEndian, s.v)
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendByte(b, s.v)
+	})
+}
+
+func (s *ByteScalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendByte(b, s.v), nil
 }
 
 func (s *ByteScalar) Unmarshall(r io.Reader) (err error) {
-	return binary.Read(r, binary.LittleEndian, &s.v)
+	s.v, err = ReadByte(r)
+
+	return
 }
 
 func (s ByteScalar) Value() any {
 	return s.v
 }
 
+func (s ByteScalar) Size() int {
+	return ByteSize
+}
+
 type BoolScalar struct {
 	v bool
 }
@@ -308,17 +379,29 @@ func NewBoolScalar(i bool) *BoolScalar {
 }
 
 func (s *BoolScalar) Marshall(w io.Writer) error {
-	return binary.Write(w, binary.LittleEndian, s.v)
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendBool(b, s.v)
+	})
+}
+
+func (s *BoolScalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendBool(b, s.v), nil
 }
 
 func (s *BoolScalar) Unmarshall(r io.Reader) (err error) {
-	return binary.Read(r, binary.LittleEndian, &s.v)
+	s.v, err = ReadBool(r)
+
+	return
 }
 
 func (s BoolScalar) Value() any {
 	return s.v
 }
 
+func (s BoolScalar) Size() int {
+	return BoolSize
+}
+
 type Uint16Scalar struct {
 	v uint16
 }
@@ -330,13 +413,25 @@ func NewUint16Scalar(i uint16) *Uint16Scalar {
 }
 
 func (s *Uint16Scalar) Marshall(w io.Writer) error {
-	return binary.Write(w, binary.LittleEndian, s.v)
+	return writeAppended(w, func(b []byte) []byte {
+		return AppendUint16(b, s.v)
+	})
+}
+
+func (s *Uint16Scalar) AppendMarshall(b []byte) ([]byte, error) {
+	return AppendUint16(b, s.v), nil
 }
 
 func (s *Uint16Scalar) Unmarshall(r io.Reader) (err error) {
-	return binary.Read(r, binary.LittleEndian, &s.v)
+	s.v, err = ReadUint16(r)
+
+	return
 }
 
 func (s Uint16Scalar) Value() any {
 	return s.v
 }
+
+func (s Uint16Scalar) Size() int {
+	return Uint16Size
+}
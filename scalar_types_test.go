@@ -2,8 +2,11 @@ package mint
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/gofrs/uuid/v5"
@@ -681,6 +684,111 @@ func TestUint16Scalar(t *testing.T) {
 	}
 }
 
+func TestStringScalar_ShortReads(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		s    string
+	}{
+		{"Short string", "Hello, World!"},
+		{"Massive string", makeLongString()},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			_ = NewStringScalar(test.s).Marshall(b)
+
+			v := NewStringScalar("")
+
+			err := v.Unmarshall(iotest.HalfReader(b))
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if test.s != v.Value().(string) {
+				t.Errorf("expected %d bytes, received %d", len(test.s), len(v.Value().(string)))
+			}
+		})
+	}
+}
+
+func TestScalars_Truncated(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		m            MarshallerUnmarshallerValuer
+		u            MarshallerUnmarshallerValuer
+		keep         int
+		expectOffset int64
+		expectRead   int64
+		expectLen    int64
+		expectEOF    bool
+	}{
+		{"Empty input", NewInt64Scalar(1), new(Int64Scalar), 0, 0, 0, 8, true},
+		{"Partial int16", NewInt16Scalar(1), new(Int16Scalar), 1, 1, 1, 2, false},
+		{"Partial uint16", NewUint16Scalar(1), new(Uint16Scalar), 1, 1, 1, 2, false},
+		{"Partial int32", NewInt32Scalar(1), new(Int32Scalar), 3, 3, 3, 4, false},
+		{"Partial uint32", NewUInt32Scalar(1), new(UInt32Scalar), 2, 2, 2, 4, false},
+		{"Partial float32", NewFloat32Scalar(1), new(Float32Scalar), 2, 2, 2, 4, false},
+		{"Partial float64", NewFloat64Scalar(1), new(Float64Scalar), 7, 7, 7, 8, false},
+		{"Partial datetime", NewDatetimeScalar(time.Now()), new(DatetimeScalar), 4, 4, 4, 8, false},
+		{"Missing bool", NewBoolScalar(true), new(BoolScalar), 0, 0, 0, 1, true},
+		{"Missing byte", NewByteScalar('a'), new(ByteScalar), 0, 0, 0, 1, true},
+		{"Partial string length", NewStringScalar("Hello"), NewStringScalar(""), 4, 4, 4, 8, false},
+		{"Partial string body", NewStringScalar("Hello"), NewStringScalar(""), 10, 10, 2, 5, false},
+		{"Partial massive string", NewStringScalar(makeLongString()), NewStringScalar(""), 100_000, 100_000, 99_992, 500_000, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			_ = test.m.Marshall(b)
+
+			err := test.u.Unmarshall(bytes.NewReader(b.Bytes()[:test.keep]))
+
+			var te ErrTruncated
+			if !errors.As(err, &te) {
+				t.Fatalf("expected ErrTruncated, received %#v", err)
+			}
+
+			if test.expectOffset != te.Offset {
+				t.Errorf("expected offset %d, received %d", test.expectOffset, te.Offset)
+			}
+
+			if test.expectRead != te.Read {
+				t.Errorf("expected %d bytes read, received %d", test.expectRead, te.Read)
+			}
+
+			if test.expectLen != te.Expected {
+				t.Errorf("expected length %d, received %d", test.expectLen, te.Expected)
+			}
+
+			if test.expectEOF != errors.Is(err, io.EOF) {
+				t.Errorf("expected io.EOF: %v, received %#v", test.expectEOF, err)
+			}
+		})
+	}
+}
+
+func TestScalars_TruncatedStreamOffset(t *testing.T) {
+	b := new(bytes.Buffer)
+	_ = NewStringScalar("Hello").Marshall(b)
+	_ = NewInt64Scalar(1).Marshall(b)
+
+	dr := NewDecodeReader(bytes.NewReader(b.Bytes()[:b.Len()-3]), nil)
+
+	err := NewStringScalar("").Unmarshall(dr)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	err = new(Int64Scalar).Unmarshall(dr)
+
+	var te ErrTruncated
+	if !errors.As(err, &te) {
+		t.Fatalf("expected ErrTruncated, received %#v", err)
+	}
+
+	if te.Offset != 18 {
+		t.Errorf("expected offset 18, received %d", te.Offset)
+	}
+}
+
 func makeLongString() string {
 	sb := strings.Builder{}
 	for i := 0; i < 100_000; i++ {
@@ -689,3 +797,88 @@ func makeLongString() string {
 
 	return sb.String()
 }
+
+func TestScalars_WireFormat(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		v      Marshaller
+		expect []byte
+	}{
+		{"String", NewStringScalar("abc"), []byte{3, 0, 0, 0, 0, 0, 0, 0, 'a', 'b', 'c'}},
+		{"Datetime", NewDatetimeScalar(time.Unix(0, 258)), []byte{2, 1, 0, 0, 0, 0, 0, 0}},
+		{"Empty datetime", NewDatetimeScalar(time.Time{}), []byte{0, 0, 0x1a, 0x3d, 0xeb, 0x03, 0xb2, 0xa1}},
+		{"Uuid", NewUuidScalar(uuid.FromStringOrNil("00010203-0405-0607-0809-0a0b0c0d0e0f")), []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}},
+		{"Int16", NewInt16Scalar(-2), []byte{0xfe, 0xff}},
+		{"Uint16", NewUint16Scalar(258), []byte{2, 1}},
+		{"Int32", NewInt32Scalar(-2), []byte{0xfe, 0xff, 0xff, 0xff}},
+		{"UInt32", NewUInt32Scalar(258), []byte{2, 1, 0, 0}},
+		{"Int64", NewInt64Scalar(-2), []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"Float32", NewFloat32Scalar(1), []byte{0, 0, 0x80, 0x3f}},
+		{"Float64", NewFloat64Scalar(1), []byte{0, 0, 0, 0, 0, 0, 0xf0, 0x3f}},
+		{"Byte", NewByteScalar('a'), []byte{'a'}},
+		{"True", NewBoolScalar(true), []byte{1}},
+		{"False", NewBoolScalar(false), []byte{0}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := new(bytes.Buffer)
+
+			err := test.v.Marshall(b)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			if !bytes.Equal(test.expect, b.Bytes()) {
+				t.Errorf("expected\n\t%v\nreceived\n\t%v", test.expect, b.Bytes())
+			}
+		})
+	}
+}
+
+func TestScalars_UnmarshallAllocations(t *testing.T) {
+	input := AppendInt64(nil, 12345)
+	input = AppendUuid(input, uuid.Must(uuid.NewV4()))
+
+	r := bytes.NewReader(input)
+	dr := NewDecodeReader(r, nil)
+
+	i := NewInt64Scalar(0)
+	u := NewUuidScalar(uuid.Nil)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Reset(input)
+
+		_ = i.Unmarshall(dr)
+		_ = u.Unmarshall(dr)
+	})
+
+	if allocs != 0 {
+		t.Errorf("expected no allocations, received %v", allocs)
+	}
+}
+
+func TestScalars_MarshallAllocations(t *testing.T) {
+	scalars := []Marshaller{
+		NewStringScalar("abc"),
+		NewDatetimeScalar(time.Unix(0, 258)),
+		NewUuidScalar(uuid.Must(uuid.NewV4())),
+		NewInt16Scalar(-2),
+		NewUint16Scalar(258),
+		NewInt32Scalar(-2),
+		NewUInt32Scalar(258),
+		NewInt64Scalar(-2),
+		NewFloat32Scalar(1),
+		NewFloat64Scalar(1),
+		NewByteScalar('a'),
+		NewBoolScalar(true),
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, s := range scalars {
+			_ = s.Marshall(io.Discard)
+		}
+	})
+
+	if allocs != 0 {
+		t.Errorf("expected no allocations, received %v", allocs)
+	}
+}
@@ -0,0 +1,60 @@
+package mint
+
+import (
+	"github.com/gofrs/uuid/v5"
+)
+
+// The size, in bytes, of the mint encoding of each fixed size scalar
+const (
+	DatetimeSize = 8
+	UuidSize     = uuid.Size
+	Int16Size    = 2
+	Uint16Size   = 2
+	Int32Size    = 4
+	Uint32Size   = 4
+	Int64Size    = 8
+	Float32Size  = 4
+	Float64Size  = 8
+	ByteSize     = 1
+	BoolSize     = 1
+
+	// CollectionHeaderSize is the size of the length which
+	// precedes slices and maps
+	CollectionHeaderSize = 4
+)
+
+// StringSize returns the size, in bytes, of the mint encoding of s
+func StringSize(s string) int {
+	return Int64Size + len(s)
+}
+
+// sizeOf returns the size of the mint encoding of m, using Size or
+// MarshalledSize where m has either, and otherwise counting the bytes
+// Marshall writes
+func sizeOf(m Marshaller) int {
+	switch s := m.(type) {
+	case Sizer:
+		return s.Size()
+
+	case MarshalledSizer:
+		return s.MarshalledSize()
+	}
+
+	w := countingWriter(0)
+
+	// Any error here would equally stop m from being marshalled, so
+	// there's no size worth reporting beyond whatever was written
+	_ = m.Marshall(&w)
+
+	return int(w)
+}
+
+// countingWriter is an io.Writer which counts, and discards, the bytes
+// written to it
+type countingWriter int
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+
+	return len(p), nil
+}
@@ -0,0 +1,55 @@
+package mint
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type sizerMarshaller interface {
+	Marshaller
+	Sizer
+}
+
+func TestSize(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		v    sizerMarshaller
+	}{
+		{"Empty string", NewStringScalar("")},
+		{"String", NewStringScalar("Hello, World!")},
+		{"Massive string", NewStringScalar(makeLongString())},
+		{"Datetime", NewDatetimeScalar(time.Now())},
+		{"Uuid", NewUuidScalar(uuid.Must(uuid.NewV4()))},
+		{"Int16", NewInt16Scalar(-10_000)},
+		{"Uint16", NewUint16Scalar(10_000)},
+		{"Int32", NewInt32Scalar(-10_000)},
+		{"UInt32", NewUInt32Scalar(10_000)},
+		{"Int64", NewInt64Scalar(-10_000)},
+		{"Float32", NewFloat32Scalar(3.14)},
+		{"Float64", NewFloat64Scalar(-3.14)},
+		{"Byte", NewByteScalar('a')},
+		{"Bool", NewBoolScalar(true)},
+		{"Slice", NewSliceCollection([]MarshallerUnmarshallerValuer{NewStringScalar("a"), NewInt64Scalar(1)}, false)},
+		{"Fixed length slice", NewSliceCollection([]MarshallerUnmarshallerValuer{NewStringScalar("a"), NewInt64Scalar(1)}, true)},
+		{"Slice of non-sizers", NewSliceCollection([]MarshallerUnmarshallerValuer{&customMUV{foo: "a", bar: 1, baz: true}}, false)},
+		{"Map", NewMapCollection(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer{NewStringScalar("a"): NewBoolScalar(true), NewStringScalar("bc"): NewBoolScalar(false)})},
+		{"Empty map", NewMapCollection(map[MarshallerUnmarshallerValuer]MarshallerUnmarshallerValuer{})},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b := new(bytes.Buffer)
+
+			err := test.v.Marshall(b)
+			if err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			received := test.v.Size()
+			if b.Len() != received {
+				t.Errorf("expected %d, received %d", b.Len(), received)
+			}
+		})
+	}
+}
@@ -8,10 +8,64 @@ type Marshaller interface {
 	Marshall(io.Writer) error
 }
 
+// Appender is implemented by types which can append their mint encoding
+// to a byte slice, allowing callers to reuse a buffer across messages
+type Appender interface {
+	AppendMarshall([]byte) ([]byte, error)
+}
+
 type Unmarshaller interface {
 	Unmarshall(io.Reader) error
 }
 
+// BytesUnmarshaller is implemented by types which can be decoded directly
+// from a byte slice, returning the number of bytes consumed
+type BytesUnmarshaller interface {
+	UnmarshallBytes([]byte) (int, error)
+}
+
+// DecoderUnmarshaller is implemented by types which can be decoded from
+// a BytesDecoder, allowing nested types to share a single cursor
+type DecoderUnmarshaller interface {
+	UnmarshallDecoder(*BytesDecoder) error
+}
+
+// Sizer is implemented by scalars and collections, returning the number
+// of bytes their mint encoding takes up
+type Sizer interface {
+	Size() int
+}
+
+// MarshalledSizer is implemented by generated types and enums, returning
+// the number of bytes Marshall would write without having to marshall
+type MarshalledSizer interface {
+	MarshalledSize() int
+}
+
+// Validator is implemented by generated types, enums, and unions,
+// returning an error describing why a value is invalid, if it is
+type Validator interface {
+	Validate() error
+}
+
+// Transformer is implemented by generated types, normalising their
+// fields before they're validated and encoded
+type Transformer interface {
+	Transform() error
+}
+
+// Transform transforms v where it's a Transformer, such as a pointer to
+// an element of a collection of generated types, and does nothing
+// otherwise, so that collections of enums and unions needn't be told
+// apart from those of types
+func Transform(v any) error {
+	if t, ok := v.(Transformer); ok {
+		return t.Transform()
+	}
+
+	return nil
+}
+
 type Valuer interface {
 	Value() any
 }